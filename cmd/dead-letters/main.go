@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/bruhabruh/file-hosting/internal/app"
+	"github.com/bruhabruh/file-hosting/internal/config"
+)
+
+func main() {
+	replay := flag.Bool("replay", false, "schedule the dead-lettered deletion jobs again")
+	limit := flag.Int("limit", 100, "maximum number of jobs")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	app := app.New(cfg)
+	app.DeadLetters(*replay, *limit)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bruhabruh/file-hosting/internal/service"
+)
+
+func main() {
+	id := flag.String("id", "", "id of the key, the part of the token before the dot")
+	label := flag.String("label", "", "label of the key in logs")
+	scopes := flag.String("scopes", "list", "comma separated scopes of the key")
+	flag.Parse()
+
+	token, hash, err := service.GenerateAPIKey(*id)
+	if err != nil {
+		log.Fatalf("failed to generate api key: %v", err)
+	}
+
+	fmt.Printf("Token, shown only once: %s\n\n", token)
+	fmt.Println("Add to apiKeys.keys:")
+	fmt.Printf("  - id: %s\n", *id)
+	if len(*label) > 0 {
+		fmt.Printf("    label: %q\n", *label)
+	}
+	fmt.Printf("    hash: %q\n", hash)
+	fmt.Printf("    scopes: [%s]\n", strings.Join(strings.Split(*scopes, ","), ", "))
+}
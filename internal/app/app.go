@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/bruhabruh/file-hosting/internal/config"
+	"github.com/bruhabruh/file-hosting/internal/domain"
 	"github.com/bruhabruh/file-hosting/internal/grpctransport"
 	"github.com/bruhabruh/file-hosting/internal/httptransport"
 	"github.com/bruhabruh/file-hosting/internal/service"
@@ -37,51 +39,141 @@ func (a *App) Run() {
 
 	ctx = logging.ContextWithLogger(ctx, logger)
 
-	mq, err := rabbitmq.NewRabbitMQ(a.config.RabbitMQ().URL())
+	var rdb *redis.Client
+	if a.config.Redis().Enabled() {
+		rdb = redis.NewClient(&redis.Options{
+			Addr:     a.config.Redis().URL(),
+			Password: a.config.Redis().Password(),
+			DB:       a.config.Redis().Database(),
+		})
+	}
+
+	fileStorage := a.newFileStorage()
+
+	metadataStore, err := a.newMetadataStore(ctx, fileStorage)
 	if err != nil {
-		log.Fatalf("Fail create rabbitmq: %s", err.Error())
+		log.Fatalf("Fail create metadata store: %s", err.Error())
 	}
+	defer metadataStore.Close()
 
-	s3, err := s3.New(
-		a.config.FileStorage().S3().Endpoint(),
-		a.config.FileStorage().S3().Region(),
-		a.config.FileStorage().S3().AccessKey(),
-		a.config.FileStorage().S3().SecretKey(),
-		a.config.FileStorage().S3().UseSSL(),
-		a.config.FileStorage().S3().Bucket(),
-		a.config.FileStorage().S3().Directory(),
-	)
+	var mq *rabbitmq.RabbitMQ
+	if a.config.Scheduler().Type() == config.SchedulerRabbitMQ {
+		mq, err = rabbitmq.NewRabbitMQ(a.config.RabbitMQ().URL())
+		if err != nil {
+			log.Fatalf("Fail create rabbitmq: %s", err.Error())
+		}
+		defer mq.Close()
+	}
+
+	scheduler, err := a.newDeletionScheduler(rdb, mq, metadataStore)
 	if err != nil {
-		log.Fatalf("Fail create s3 client: %s", err.Error())
+		log.Fatalf("Fail create deletion scheduler: %s", err.Error())
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     a.config.Redis().URL(),
-		Password: a.config.Redis().Password(),
-		DB:       a.config.Redis().Database(),
-	})
+	retention := domain.VersionRetention{
+		KeepLast: a.config.Versions().KeepLast(),
+		KeepFor:  time.Duration(a.config.Versions().KeepDays()) * 24 * time.Hour,
+	}
 
-	var fileStorage storage.FileStorage
-	if a.config.FileStorage().Basic().Enabled() {
-		fileStorage = storage.NewBasicFileStorage(a.config.FileStorage().Basic().Directory())
+	expiry := domain.ExpiryPolicies{
+		Anonymous:  a.expiryPolicy(a.config.Expiration().Anonymous()),
+		Authorized: a.expiryPolicy(a.config.Expiration().Authorized()),
 	}
-	if a.config.FileStorage().S3().Enabled() {
-		fileStorage = storage.NewS3FileStorage(s3)
+
+	var webhookService service.WebhookService
+	var webhooks service.WebhookNotifier
+	if a.config.Webhooks().Enabled() {
+		webhookService, err = a.newWebhookService(ctx, fileStorage)
+		if err != nil {
+			log.Fatalf("Fail create webhook service: %s", err.Error())
+		}
+		webhooks = webhookService
 	}
 
-	fileHostingService, err := service.NewFileHostingCachedService(ctx, fileStorage, mq, rdb)
+	var fileHostingService service.FileHostingService
+	if rdb != nil {
+		fileHostingService, err = service.NewFileHostingCachedService(ctx, fileStorage, metadataStore, scheduler, service.NewRedisDownloadCounter(rdb), webhooks, retention, a.config.Trash().Retention(), a.config.Tombstones().Retention(), expiry, rdb)
+	} else {
+		fileHostingService, err = service.NewFileHostingService(ctx, fileStorage, metadataStore, scheduler, service.NewMemoryDownloadCounter(), webhooks, retention, a.config.Trash().Retention(), a.config.Tombstones().Retention(), expiry)
+	}
 	if err != nil {
 		log.Fatalf("Fail create file hosting service: %s", err.Error())
 	}
 
+	go a.reconcile(ctx, fileHostingService)
+
+	resumableUploadService := service.NewResumableUploadService(ctx, fileStorage, fileHostingService, a.config.HTTP().Tus().Expiration(), expiry.Anonymous)
+
+	var presignService service.PresignService
+	if a.config.FileStorage().S3().Enabled() && a.config.FileStorage().S3().Presign().Enabled() {
+		presignService, err = service.NewPresignService(ctx, fileStorage, fileHostingService, a.config.FileStorage().S3().Presign().Expiration(), int64(a.config.HTTP().MaxBodySizeInMB())*1024*1024, expiry.Authorized)
+		if err != nil {
+			log.Fatalf("Fail create presign service: %s", err.Error())
+		}
+	}
+
+	var signedURLService service.SignedURLService
+	if a.config.SignedURLs().Enabled() {
+		signedURLService, err = a.newSignedURLService(fileHostingService)
+		if err != nil {
+			log.Fatalf("Fail create signed url service: %s", err.Error())
+		}
+	}
+
+	filePasswordService, err := a.newFilePasswordService(ctx, rdb)
+	if err != nil {
+		log.Fatalf("Fail create file password service: %s", err.Error())
+	}
+
+	apiKeyService, err := a.newAPIKeyService(rdb)
+	if err != nil {
+		log.Fatalf("Fail create api key service: %s", err.Error())
+	}
+
+	var jwtService service.JWTService
+	if a.config.JWT().Enabled() {
+		jwtService, err = a.newJWTService(ctx)
+		if err != nil {
+			log.Fatalf("Fail create jwt service: %s", err.Error())
+		}
+	}
+
+	authenticator := service.NewAuthenticator(apiKeyService, jwtService)
+
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(
 		collectors.NewGoCollector(collectors.WithGoCollectorRuntimeMetrics()),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 	)
 
-	http := httptransport.New(a.config, logger, reg, fileHostingService)
-	grpc := grpctransport.New(a.config, logger, reg, fileHostingService)
+	healthChecks := map[string]httptransport.HealthCheck{}
+	if mq != nil {
+		reg.MustRegister(
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: "service",
+				Subsystem: "rabbitmq",
+				Name:      "connected",
+				Help:      "Whether the connection to RabbitMQ is open.",
+			}, func() float64 {
+				if mq.Connected() {
+					return 1
+				}
+				return 0
+			}),
+			prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: "service",
+				Subsystem: "rabbitmq",
+				Name:      "reconnects_total",
+				Help:      "Number of times the connection to RabbitMQ was restored.",
+			}, func() float64 {
+				return float64(mq.Reconnects())
+			}),
+		)
+		healthChecks["rabbitmq"] = mq.Health
+	}
+
+	http := httptransport.New(a.config, logger, reg, fileHostingService, resumableUploadService, presignService, signedURLService, filePasswordService, webhookService, authenticator, healthChecks)
+	grpc := grpctransport.New(a.config, logger, reg, fileHostingService, presignService, signedURLService, filePasswordService, webhookService, authenticator)
 
 	http.Run()
 	defer func() {
@@ -108,3 +200,181 @@ func (a *App) Run() {
 		}
 	}
 }
+
+func (a *App) newFileStorage() storage.FileStorage {
+	var fileStorage storage.FileStorage
+	if a.config.FileStorage().Basic().Enabled() {
+		fileStorage = storage.NewBasicFileStorage(a.config.FileStorage().Basic().Directory())
+	}
+	if a.config.FileStorage().S3().Enabled() {
+		s3, err := s3.New(
+			a.config.FileStorage().S3().Endpoint(),
+			a.config.FileStorage().S3().Region(),
+			a.config.FileStorage().S3().AccessKey(),
+			a.config.FileStorage().S3().SecretKey(),
+			a.config.FileStorage().S3().UseSSL(),
+			a.config.FileStorage().S3().Bucket(),
+			a.config.FileStorage().S3().Directory(),
+			s3.WithPartSize(int64(a.config.FileStorage().S3().PartSizeInMB())*1024*1024),
+			s3.WithConcurrency(a.config.FileStorage().S3().Concurrency()),
+		)
+		if err != nil {
+			log.Fatalf("Fail create s3 client: %s", err.Error())
+		}
+
+		fileStorage = storage.NewS3FileStorage(s3)
+	}
+	if a.config.FileStorage().ContentAddressed() {
+		fileStorage = storage.NewContentAddressedFileStorage(fileStorage)
+	}
+
+	return fileStorage
+}
+
+func (a *App) expiryPolicy(cfg *config.ExpiryPolicyConfig) domain.ExpiryPolicy {
+	return domain.ExpiryPolicy{
+		Default: cfg.Default(),
+		Min:     cfg.Min(),
+		Max:     cfg.Max(),
+	}
+}
+
+func (a *App) newAPIKeyService(rdb *redis.Client) (service.APIKeyService, error) {
+	keys := []*domain.APIKey{}
+	for _, cfg := range a.config.APIKeys().Keys() {
+		scopes := make([]domain.Scope, 0, len(cfg.Scopes()))
+		for _, scope := range cfg.Scopes() {
+			scopes = append(scopes, domain.Scope(scope))
+		}
+		keys = append(keys, &domain.APIKey{
+			Id:        cfg.Id(),
+			Label:     cfg.Label(),
+			Hash:      cfg.Hash(),
+			Scopes:    scopes,
+			ExpiresAt: cfg.ExpiresAt(),
+		})
+	}
+
+	var limiter service.PasswordAttemptLimiter
+	if rdb != nil {
+		limiter = service.NewRedisPasswordAttemptLimiter(rdb, "api-key-failures", a.config.APIKeys().FailuresWindow())
+	} else {
+		limiter = service.NewMemoryPasswordAttemptLimiter(a.config.APIKeys().FailuresWindow())
+	}
+
+	return service.NewAPIKeyService(a.config.ApiKey(), keys, limiter, a.config.APIKeys().MaxFailures())
+}
+
+func (a *App) newJWTService(ctx context.Context) (service.JWTService, error) {
+	scopeMapping := map[string][]domain.Scope{}
+	for _, cfg := range a.config.JWT().ScopeMapping() {
+		for _, scope := range cfg.Scopes() {
+			scopeMapping[cfg.Value()] = append(scopeMapping[cfg.Value()], domain.Scope(scope))
+		}
+	}
+
+	return service.NewJWTService(ctx, service.JWTOptions{
+		JWKSURL:                a.config.JWT().JWKSURL(),
+		Issuer:                 a.config.JWT().Issuer(),
+		Audience:               a.config.JWT().Audience(),
+		RefreshInterval:        a.config.JWT().RefreshInterval(),
+		RefreshUnknownInterval: a.config.JWT().RefreshUnknownInterval(),
+		OwnerClaim:             a.config.JWT().OwnerClaim(),
+		ScopesClaim:            a.config.JWT().ScopesClaim(),
+		ScopeMapping:           scopeMapping,
+	})
+}
+
+func (a *App) newSignedURLService(fileHostingService service.FileHostingService) (service.SignedURLService, error) {
+	keys := make([]*domain.SigningKey, 0, len(a.config.SignedURLs().Keys()))
+	for _, cfg := range a.config.SignedURLs().Keys() {
+		keys = append(keys, &domain.SigningKey{
+			Id:     cfg.Id(),
+			Secret: cfg.Secret(),
+		})
+	}
+
+	return service.NewSignedURLService(
+		fileHostingService,
+		a.config.Origin(),
+		keys,
+		a.config.SignedURLs().DefaultLifetime(),
+		a.config.SignedURLs().MaxLifetime(),
+	)
+}
+
+func (a *App) newFilePasswordService(ctx context.Context, rdb *redis.Client) (service.FilePasswordService, error) {
+	cfg := a.config.Passwords()
+
+	if len(cfg.CookieSecret()) == 0 {
+		logging.L(ctx).Warn("passwords.cookieSecret is empty, entered passwords are forgotten on restart and are not shared by replicas")
+	}
+
+	var limiter service.PasswordAttemptLimiter
+	if rdb != nil {
+		limiter = service.NewRedisPasswordAttemptLimiter(rdb, "password-attempts", cfg.AttemptsWindow())
+	} else {
+		limiter = service.NewMemoryPasswordAttemptLimiter(cfg.AttemptsWindow())
+	}
+
+	return service.NewFilePasswordService(limiter, cfg.MaxAttempts(), cfg.CookieSecret(), cfg.CookieLifetime())
+}
+
+func (a *App) newWebhookService(ctx context.Context, fileStorage storage.FileStorage) (service.WebhookService, error) {
+	subscriptions := []*domain.WebhookSubscription{}
+	for _, cfg := range a.config.Webhooks().Subscriptions() {
+		events := make([]domain.WebhookEventType, 0, len(cfg.Events()))
+		for _, event := range cfg.Events() {
+			events = append(events, domain.WebhookEventType(event))
+		}
+		subscriptions = append(subscriptions, &domain.WebhookSubscription{
+			Url:    cfg.URL(),
+			Secret: cfg.Secret(),
+			Events: events,
+		})
+	}
+
+	return service.NewWebhookService(
+		ctx,
+		fileStorage,
+		subscriptions,
+		a.config.Webhooks().Workers(),
+		a.config.Webhooks().Timeout(),
+		a.config.Webhooks().LeadTime(),
+		service.WebhookRetryPolicy{
+			MaxRetries: a.config.Webhooks().Retry().MaxRetries(),
+			Backoff:    a.config.Webhooks().Retry().Backoff(),
+			MaxBackoff: a.config.Webhooks().Retry().MaxBackoff(),
+		},
+	)
+}
+
+func (a *App) newDeletionScheduler(rdb *redis.Client, mq *rabbitmq.RabbitMQ, metadataStore storage.MetadataStore) (service.DeletionScheduler, error) {
+	switch a.config.Scheduler().Type() {
+	case config.SchedulerSweeper:
+		return service.NewSweeperDeletionScheduler(metadataStore, a.config.Scheduler().Sweeper().Interval()), nil
+	case config.SchedulerRabbitMQ:
+		return a.newRabbitMQDeletionScheduler(mq)
+	default:
+		return service.NewRedisDeletionScheduler(rdb), nil
+	}
+}
+
+func (a *App) newRabbitMQDeletionScheduler(mq *rabbitmq.RabbitMQ) (*service.RabbitMQDeletionScheduler, error) {
+	return service.NewRabbitMQDeletionScheduler(mq, rabbitmq.RetryPolicy{
+		MaxRetries: a.config.RabbitMQ().Retry().MaxRetries(),
+		Backoff:    a.config.RabbitMQ().Retry().Backoff(),
+		MaxBackoff: a.config.RabbitMQ().Retry().MaxBackoff(),
+	})
+}
+
+func (a *App) newMetadataStore(ctx context.Context, fileStorage storage.FileStorage) (storage.MetadataStore, error) {
+	switch a.config.MetadataStore().Type() {
+	case config.MetadataStoreSQLite:
+		return storage.NewSQLiteMetadataStore(a.config.MetadataStore().SQLite().Path())
+	case config.MetadataStorePostgres:
+		return storage.NewPostgresMetadataStore(ctx, a.config.MetadataStore().Postgres().URL())
+	default:
+		return storage.NewSidecarMetadataStore(fileStorage), nil
+	}
+}
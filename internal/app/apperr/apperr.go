@@ -148,7 +148,7 @@ func httpToGRPCCode(httpCode int) codes.Code {
 		return codes.Unauthenticated
 	case fiber.StatusForbidden:
 		return codes.PermissionDenied
-	case fiber.StatusNotFound:
+	case fiber.StatusNotFound, fiber.StatusGone:
 		return codes.NotFound
 	case fiber.StatusConflict:
 		return codes.AlreadyExists
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bruhabruh/file-hosting/internal/config"
+	"github.com/bruhabruh/file-hosting/pkg/rabbitmq"
+	"github.com/goccy/go-json"
+)
+
+// DeadLetters prints up to limit dead-lettered deletion jobs of the RabbitMQ
+// scheduler as JSON lines or, with replay, schedules them again.
+func (a *App) DeadLetters(replay bool, limit int) {
+	if a.config.Scheduler().Type() != config.SchedulerRabbitMQ {
+		log.Fatalf("Scheduler is %s, dead letters are kept by %s only", a.config.Scheduler().Type(), config.SchedulerRabbitMQ)
+	}
+
+	mq, err := rabbitmq.NewRabbitMQ(a.config.RabbitMQ().URL())
+	if err != nil {
+		log.Fatalf("Fail create rabbitmq: %s", err.Error())
+	}
+	defer mq.Close()
+
+	scheduler, err := a.newRabbitMQDeletionScheduler(mq)
+	if err != nil {
+		log.Fatalf("Fail create deletion scheduler: %s", err.Error())
+	}
+
+	if replay {
+		replayed, err := scheduler.ReplayDeadLetters(limit)
+		if err != nil {
+			log.Fatalf("Fail replay dead letters: %s", err.Error())
+		}
+		fmt.Printf("Replayed %d jobs\n", replayed)
+		return
+	}
+
+	jobs, err := scheduler.DeadLetters(limit)
+	if err != nil {
+		log.Fatalf("Fail read dead letters: %s", err.Error())
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, job := range jobs {
+		if err := encoder.Encode(job); err != nil {
+			log.Fatalf("Fail print dead letter: %s", err.Error())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/bruhabruh/file-hosting/internal/config"
+	"github.com/bruhabruh/file-hosting/internal/storage"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+)
+
+// MigrateMetadata imports the .metadata sidecars of all files in the file
+// storage into the configured metadata store. Sidecars are left in place,
+// so the migration can be repeated and the store switched back.
+func (a *App) MigrateMetadata() {
+	ctx := context.Background()
+
+	logger := a.config.Logger().Build()
+
+	ctx = logging.ContextWithLogger(ctx, logger)
+
+	if a.config.MetadataStore().Type() == config.MetadataStoreSidecar {
+		log.Fatalf("Metadata store is %s, nothing to migrate", config.MetadataStoreSidecar)
+	}
+
+	fileStorage := a.newFileStorage()
+	sidecars := storage.NewSidecarMetadataStore(fileStorage)
+
+	metadataStore, err := a.newMetadataStore(ctx, fileStorage)
+	if err != nil {
+		log.Fatalf("Fail create metadata store: %s", err.Error())
+	}
+	defer metadataStore.Close()
+
+	files, err := fileStorage.Files(ctx)
+	if err != nil {
+		log.Fatalf("Fail list files: %s", err.Error())
+	}
+
+	imported := 0
+	for _, file := range files {
+		metadata, err := sidecars.Get(ctx, file)
+		if err != nil {
+			logger.Warn("Skip file without metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
+			continue
+		}
+
+		if err := metadataStore.Put(ctx, metadata); err != nil {
+			log.Fatalf("Fail import metadata of file %s: %s", file, err.Error())
+		}
+		imported++
+
+		// Versions of deleted files are not reachable from the listing
+		versions, err := sidecars.ListIn(ctx, fmt.Sprintf("%s/%s", storage.VersionDirectory, file))
+		if err != nil {
+			logger.Warn("Skip versions of file", logging.StringAttr("file", file), logging.ErrAttr(err))
+			continue
+		}
+		for _, version := range versions {
+			if err := metadataStore.Put(ctx, version); err != nil {
+				log.Fatalf("Fail import metadata of file %s: %s", version.Id, err.Error())
+			}
+			imported++
+		}
+	}
+
+	logger.Info(
+		"Metadata migrated",
+		logging.IntAttr("files", len(files)),
+		logging.IntAttr("imported", imported),
+	)
+}
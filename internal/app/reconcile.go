@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/config"
+	"github.com/bruhabruh/file-hosting/internal/service"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+)
+
+// reconcile reconciles files at startup and then every reconcile.interval
+// until ctx is done.
+func (a *App) reconcile(ctx context.Context, fileHostingService service.FileHostingService) {
+	// Jobs in RabbitMQ can not be replaced, so they are not duplicated on
+	// every interval
+	reschedule := true
+
+	for {
+		start := time.Now()
+		report, err := fileHostingService.Reconcile(ctx, reschedule)
+		if err != nil {
+			logging.L(ctx).Error("Fail reconcile files", logging.ErrAttr(err))
+		} else {
+			for _, file := range report.FilesWithoutMetadata {
+				logging.L(ctx).Warn("File without metadata", logging.StringAttr("file", file))
+			}
+			for _, file := range report.MetadataWithoutFiles {
+				logging.L(ctx).Warn("Metadata without file", logging.StringAttr("file", file))
+			}
+			logging.L(ctx).Info(
+				"Files reconciled",
+				logging.IntAttr("files", report.Files),
+				logging.IntAttr("deleted", report.Deleted),
+				logging.IntAttr("rescheduled", report.Rescheduled),
+				logging.IntAttr("files_without_metadata", len(report.FilesWithoutMetadata)),
+				logging.IntAttr("metadata_without_files", len(report.MetadataWithoutFiles)),
+				logging.DurationAttr("duration", time.Since(start)),
+			)
+		}
+
+		if a.config.Reconcile().Interval() == 0 {
+			return
+		}
+		reschedule = a.config.Scheduler().Type() != config.SchedulerRabbitMQ
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(a.config.Reconcile().Interval()):
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type APIKeysConfig struct {
+	file           string
+	keys           []*APIKeyConfig
+	maxFailures    int
+	failuresWindow time.Duration
+	// err is the error of reading file, reported by Validate.
+	err error
+}
+
+type APIKeyConfig struct {
+	id        string
+	label     string
+	hash      string
+	scopes    []string
+	expiresAt string
+}
+
+type rawAPIKey struct {
+	Id        string   `mapstructure:"id"`
+	Label     string   `mapstructure:"label"`
+	Hash      string   `mapstructure:"hash"`
+	Scopes    []string `mapstructure:"scopes"`
+	ExpiresAt string   `mapstructure:"expiresAt"`
+}
+
+func newAPIKeysConfig(prefix string, v *viper.Viper) *APIKeysConfig {
+	v.SetDefault(path(prefix, "file"), "")
+	v.SetDefault(path(prefix, "maxFailures"), 10)
+	v.SetDefault(path(prefix, "failuresWindow"), "15m")
+
+	c := &APIKeysConfig{
+		file:           v.GetString(path(prefix, "file")),
+		maxFailures:    v.GetInt(path(prefix, "maxFailures")),
+		failuresWindow: v.GetDuration(path(prefix, "failuresWindow")),
+	}
+
+	var rawKeys []rawAPIKey
+	v.UnmarshalKey(path(prefix, "keys"), &rawKeys)
+
+	if len(c.file) > 0 {
+		fv := viper.New()
+		fv.SetConfigFile(c.file)
+		if err := fv.ReadInConfig(); err != nil {
+			c.err = err
+		} else {
+			var fileKeys []rawAPIKey
+			if err := fv.UnmarshalKey("keys", &fileKeys); err != nil {
+				c.err = err
+			}
+			rawKeys = append(rawKeys, fileKeys...)
+		}
+	}
+
+	c.keys = make([]*APIKeyConfig, 0, len(rawKeys))
+	for _, raw := range rawKeys {
+		c.keys = append(c.keys, &APIKeyConfig{
+			id:        raw.Id,
+			label:     raw.Label,
+			hash:      raw.Hash,
+			scopes:    raw.Scopes,
+			expiresAt: raw.ExpiresAt,
+		})
+	}
+
+	return c
+}
+
+// File is a YAML or JSON file with more keys, read once at start.
+func (c *APIKeysConfig) File() string {
+	return c.file
+}
+
+// Keys are the keys of the config and of the file.
+func (c *APIKeysConfig) Keys() []*APIKeyConfig {
+	return c.keys
+}
+
+// MaxFailures is how many wrong secrets of a key a client can send within
+// FailuresWindow.
+func (c *APIKeysConfig) MaxFailures() int {
+	return c.maxFailures
+}
+
+func (c *APIKeysConfig) FailuresWindow() time.Duration {
+	return c.failuresWindow
+}
+
+func (c *APIKeysConfig) Validate() error {
+	if c.err != nil {
+		return fmt.Errorf("fail read file %s: %w", c.file, c.err)
+	}
+
+	if c.maxFailures <= 0 {
+		return fmt.Errorf("invalid maxFailures: %d", c.maxFailures)
+	}
+
+	if c.failuresWindow <= 0 {
+		return fmt.Errorf("invalid failuresWindow: %s", c.failuresWindow)
+	}
+
+	ids := make([]string, 0, len(c.keys))
+	for i, key := range c.keys {
+		if err := key.Validate(); err != nil {
+			return fmt.Errorf("invalid key %d: %w", i+1, err)
+		}
+		if slices.Contains(ids, key.id) {
+			return fmt.Errorf("duplicate key id: %s", key.id)
+		}
+		ids = append(ids, key.id)
+	}
+
+	return nil
+}
+
+// Id is the part of a token before the dot.
+func (c *APIKeyConfig) Id() string {
+	return c.id
+}
+
+// Label names the key in logs, the id when empty.
+func (c *APIKeyConfig) Label() string {
+	return c.label
+}
+
+// Hash is the argon2id or bcrypt hash of the secret part of a token.
+func (c *APIKeyConfig) Hash() string {
+	return c.hash
+}
+
+func (c *APIKeyConfig) Scopes() []string {
+	return c.scopes
+}
+
+// ExpiresAt is zero for keys which never expire.
+func (c *APIKeyConfig) ExpiresAt() time.Time {
+	expiresAt, _ := time.Parse(time.RFC3339, c.expiresAt)
+	return expiresAt
+}
+
+func (c *APIKeyConfig) Validate() error {
+	if len(c.id) == 0 || strings.Contains(c.id, ".") {
+		return fmt.Errorf("invalid id: %q", c.id)
+	}
+
+	if !strings.HasPrefix(c.hash, "$argon2id$") && !strings.HasPrefix(c.hash, "$2a$") && !strings.HasPrefix(c.hash, "$2b$") && !strings.HasPrefix(c.hash, "$2y$") {
+		return fmt.Errorf("hash of %s must be argon2id or bcrypt", c.id)
+	}
+
+	if len(c.scopes) == 0 {
+		return fmt.Errorf("scopes of %s are required", c.id)
+	}
+
+	if len(c.expiresAt) > 0 {
+		if _, err := time.Parse(time.RFC3339, c.expiresAt); err != nil {
+			return fmt.Errorf("invalid expiresAt of %s: %s", c.id, c.expiresAt)
+		}
+	}
+
+	return nil
+}
@@ -8,14 +8,26 @@ import (
 )
 
 type Config struct {
-	origin      string
-	apiKey      string
-	http        *HTTPConfig
-	grpc        *GRPCConfig
-	logger      *LoggerConfig
-	fileStorage *FileStorageConfig
-	rabbitmq    *RabbitMQConfig
-	redis       *RedisConfig
+	origin        string
+	apiKey        string
+	apiKeys       *APIKeysConfig
+	jwt           *JWTConfig
+	signedURLs    *SignedURLsConfig
+	passwords     *PasswordsConfig
+	http          *HTTPConfig
+	grpc          *GRPCConfig
+	logger        *LoggerConfig
+	fileStorage   *FileStorageConfig
+	metadataStore *MetadataStoreConfig
+	versions      *VersionsConfig
+	trash         *TrashConfig
+	tombstones    *TombstonesConfig
+	expiration    *ExpirationConfig
+	scheduler     *SchedulerConfig
+	reconcile     *ReconcileConfig
+	rabbitmq      *RabbitMQConfig
+	redis         *RedisConfig
+	webhooks      *WebhooksConfig
 }
 
 func newConfig(v *viper.Viper) *Config {
@@ -23,14 +35,26 @@ func newConfig(v *viper.Viper) *Config {
 	v.SetDefault("port", 8080)
 
 	return &Config{
-		origin:      v.GetString("origin"),
-		apiKey:      v.GetString("API_KEY"),
-		http:        newHTTPConfig("http", v),
-		grpc:        newGRPCConfig("grpc", v),
-		logger:      newLoggerConfig("logger", v),
-		fileStorage: newFileStorageConfig("fileStorage", v),
-		rabbitmq:    newRabbitMQConfig("rabbitmq", v),
-		redis:       newRedisConfig("redis", v),
+		origin:        v.GetString("origin"),
+		apiKey:        v.GetString("API_KEY"),
+		apiKeys:       newAPIKeysConfig("apiKeys", v),
+		jwt:           newJWTConfig("jwt", v),
+		signedURLs:    newSignedURLsConfig("signedUrls", v),
+		passwords:     newPasswordsConfig("passwords", v),
+		http:          newHTTPConfig("http", v),
+		grpc:          newGRPCConfig("grpc", v),
+		logger:        newLoggerConfig("logger", v),
+		fileStorage:   newFileStorageConfig("fileStorage", v),
+		metadataStore: newMetadataStoreConfig("metadataStore", v),
+		versions:      newVersionsConfig("versions", v),
+		trash:         newTrashConfig("trash", v),
+		tombstones:    newTombstonesConfig("tombstones", v),
+		expiration:    newExpirationConfig("expiration", v),
+		scheduler:     newSchedulerConfig("scheduler", v),
+		reconcile:     newReconcileConfig("reconcile", v),
+		rabbitmq:      newRabbitMQConfig("rabbitmq", v),
+		redis:         newRedisConfig("redis", v),
+		webhooks:      newWebhooksConfig("webhooks", v),
 	}
 }
 
@@ -38,10 +62,28 @@ func (c *Config) Origin() string {
 	return c.origin
 }
 
+// ApiKey is the key with all scopes set by the API_KEY variable, kept for
+// compatibility. Empty disables it.
 func (c *Config) ApiKey() string {
 	return c.apiKey
 }
 
+func (c *Config) APIKeys() *APIKeysConfig {
+	return c.apiKeys
+}
+
+func (c *Config) JWT() *JWTConfig {
+	return c.jwt
+}
+
+func (c *Config) SignedURLs() *SignedURLsConfig {
+	return c.signedURLs
+}
+
+func (c *Config) Passwords() *PasswordsConfig {
+	return c.passwords
+}
+
 func (c *Config) HTTP() *HTTPConfig {
 	return c.http
 }
@@ -58,6 +100,34 @@ func (c *Config) FileStorage() *FileStorageConfig {
 	return c.fileStorage
 }
 
+func (c *Config) MetadataStore() *MetadataStoreConfig {
+	return c.metadataStore
+}
+
+func (c *Config) Versions() *VersionsConfig {
+	return c.versions
+}
+
+func (c *Config) Trash() *TrashConfig {
+	return c.trash
+}
+
+func (c *Config) Scheduler() *SchedulerConfig {
+	return c.scheduler
+}
+
+func (c *Config) Tombstones() *TombstonesConfig {
+	return c.tombstones
+}
+
+func (c *Config) Expiration() *ExpirationConfig {
+	return c.expiration
+}
+
+func (c *Config) Reconcile() *ReconcileConfig {
+	return c.reconcile
+}
+
 func (c *Config) RabbitMQ() *RabbitMQConfig {
 	return c.rabbitmq
 }
@@ -66,9 +136,31 @@ func (c *Config) Redis() *RedisConfig {
 	return c.redis
 }
 
+func (c *Config) Webhooks() *WebhooksConfig {
+	return c.webhooks
+}
+
 func (c *Config) Validate() error {
-	if len(c.apiKey) == 0 {
-		return errors.New("API_KEY is required")
+	if err := c.apiKeys.Validate(); err != nil {
+		return fmt.Errorf("invalid api keys config: %w", err)
+	}
+
+	if len(c.apiKey) == 0 && len(c.apiKeys.Keys()) == 0 && !c.jwt.Enabled() {
+		return errors.New("API_KEY, apiKeys or jwt is required")
+	}
+
+	if c.jwt.Enabled() {
+		if err := c.jwt.Validate(); err != nil {
+			return fmt.Errorf("invalid jwt config: %w", err)
+		}
+	}
+
+	if err := c.signedURLs.Validate(); err != nil {
+		return fmt.Errorf("invalid signed urls config: %w", err)
+	}
+
+	if err := c.passwords.Validate(); err != nil {
+		return fmt.Errorf("invalid passwords config: %w", err)
 	}
 
 	if err := c.http.Validate(); err != nil {
@@ -87,13 +179,53 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid file storage config: %w", err)
 	}
 
-	if err := c.rabbitmq.Validate(); err != nil {
-		return fmt.Errorf("invalid rabbitmq config: %w", err)
+	if err := c.metadataStore.Validate(); err != nil {
+		return fmt.Errorf("invalid metadata store config: %w", err)
+	}
+
+	if err := c.versions.Validate(); err != nil {
+		return fmt.Errorf("invalid versions config: %w", err)
+	}
+
+	if err := c.trash.Validate(); err != nil {
+		return fmt.Errorf("invalid trash config: %w", err)
+	}
+
+	if err := c.tombstones.Validate(); err != nil {
+		return fmt.Errorf("invalid tombstones config: %w", err)
+	}
+
+	if err := c.expiration.Validate(); err != nil {
+		return fmt.Errorf("invalid expiration config: %w", err)
+	}
+
+	if err := c.scheduler.Validate(); err != nil {
+		return fmt.Errorf("invalid scheduler config: %w", err)
+	}
+
+	if err := c.reconcile.Validate(); err != nil {
+		return fmt.Errorf("invalid reconcile config: %w", err)
+	}
+
+	if c.scheduler.Type() == SchedulerRedis && !c.redis.Enabled() {
+		return errors.New("invalid scheduler config: redis scheduler requires redis to be enabled")
+	}
+
+	if c.scheduler.Type() == SchedulerRabbitMQ {
+		if err := c.rabbitmq.Validate(); err != nil {
+			return fmt.Errorf("invalid rabbitmq config: %w", err)
+		}
 	}
 
 	if err := c.redis.Validate(); err != nil {
 		return fmt.Errorf("invalid redis config: %w", err)
 	}
 
+	if c.webhooks.Enabled() {
+		if err := c.webhooks.Validate(); err != nil {
+			return fmt.Errorf("invalid webhooks config: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type ExpirationConfig struct {
+	anonymous  *ExpiryPolicyConfig
+	authorized *ExpiryPolicyConfig
+}
+
+func newExpirationConfig(prefix string, v *viper.Viper) *ExpirationConfig {
+	return &ExpirationConfig{
+		anonymous:  newExpiryPolicyConfig(path(prefix, "anonymous"), "168h", v),
+		authorized: newExpiryPolicyConfig(path(prefix, "authorized"), "0", v),
+	}
+}
+
+// Anonymous limits files uploaded without the API key.
+func (c *ExpirationConfig) Anonymous() *ExpiryPolicyConfig {
+	return c.anonymous
+}
+
+// Authorized limits files uploaded with the API key.
+func (c *ExpirationConfig) Authorized() *ExpiryPolicyConfig {
+	return c.authorized
+}
+
+func (c *ExpirationConfig) Validate() error {
+	if err := c.anonymous.Validate(); err != nil {
+		return fmt.Errorf("invalid anonymous config: %w", err)
+	}
+
+	if err := c.authorized.Validate(); err != nil {
+		return fmt.Errorf("invalid authorized config: %w", err)
+	}
+
+	return nil
+}
+
+type ExpiryPolicyConfig struct {
+	defaultDuration time.Duration
+	min             time.Duration
+	max             time.Duration
+}
+
+func newExpiryPolicyConfig(prefix string, defaultMax string, v *viper.Viper) *ExpiryPolicyConfig {
+	v.SetDefault(path(prefix, "default"), "1h")
+	v.SetDefault(path(prefix, "min"), "1m")
+	v.SetDefault(path(prefix, "max"), defaultMax)
+
+	return &ExpiryPolicyConfig{
+		defaultDuration: v.GetDuration(path(prefix, "default")),
+		min:             v.GetDuration(path(prefix, "min")),
+		max:             v.GetDuration(path(prefix, "max")),
+	}
+}
+
+// Default is the duration of files uploaded without a duration.
+func (c *ExpiryPolicyConfig) Default() time.Duration {
+	return c.defaultDuration
+}
+
+func (c *ExpiryPolicyConfig) Min() time.Duration {
+	return c.min
+}
+
+// Max is the longest duration, 0 allows any duration and permanent files.
+func (c *ExpiryPolicyConfig) Max() time.Duration {
+	return c.max
+}
+
+func (c *ExpiryPolicyConfig) Validate() error {
+	if c.min < 0 {
+		return fmt.Errorf("invalid min: %s", c.min)
+	}
+
+	if c.max < 0 {
+		return fmt.Errorf("invalid max: %s", c.max)
+	}
+
+	if c.defaultDuration < c.min || (c.max > 0 && c.defaultDuration > c.max) {
+		return fmt.Errorf("invalid default: %s, must be between min and max", c.defaultDuration)
+	}
+
+	return nil
+}
@@ -7,17 +7,25 @@ import (
 )
 
 type FileStorageConfig struct {
-	basic *BasicFileStorageConfig
-	s3    *S3FileStorageConfig
+	contentAddressed bool
+	basic            *BasicFileStorageConfig
+	s3               *S3FileStorageConfig
 }
 
 func newFileStorageConfig(prefix string, v *viper.Viper) *FileStorageConfig {
+	v.SetDefault(path(prefix, "contentAddressed"), false)
+
 	return &FileStorageConfig{
-		basic: newBasicFileStorageConfig(path(prefix, "basic"), v),
-		s3:    newS3FileStorageConfig(path(prefix, "s3"), v),
+		contentAddressed: v.GetBool(path(prefix, "contentAddressed")),
+		basic:            newBasicFileStorageConfig(path(prefix, "basic"), v),
+		s3:               newS3FileStorageConfig(path(prefix, "s3"), v),
 	}
 }
 
+func (c *FileStorageConfig) ContentAddressed() bool {
+	return c.contentAddressed
+}
+
 func (c *FileStorageConfig) Basic() *BasicFileStorageConfig {
 	return c.basic
 }
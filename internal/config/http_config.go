@@ -11,6 +11,7 @@ type HTTPConfig struct {
 	allowPage       bool
 	maxBodySizeInMB int
 	port            int
+	tus             *TusConfig
 }
 
 func newHTTPConfig(prefix string, v *viper.Viper) *HTTPConfig {
@@ -24,6 +25,7 @@ func newHTTPConfig(prefix string, v *viper.Viper) *HTTPConfig {
 		allowPage:       v.GetBool(path(prefix, "allowPage")),
 		maxBodySizeInMB: v.GetInt(path(prefix, "maxBodySizeInMB")),
 		port:            v.GetInt(path(prefix, "port")),
+		tus:             newTusConfig(path(prefix, "tus"), v),
 	}
 }
 
@@ -43,10 +45,20 @@ func (c *HTTPConfig) Port() int {
 	return c.port
 }
 
+func (c *HTTPConfig) Tus() *TusConfig {
+	return c.tus
+}
+
 func (c *HTTPConfig) Validate() error {
 	if c.enabled && (c.port < 0 || c.port > 65535) {
 		return fmt.Errorf("invalid port: %d", c.port)
 	}
 
+	if c.tus.enabled {
+		if err := c.tus.Validate(); err != nil {
+			return fmt.Errorf("invalid tus config: %w", err)
+		}
+	}
+
 	return nil
 }
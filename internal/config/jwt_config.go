@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type JWTConfig struct {
+	enabled                bool
+	jwksURL                string
+	issuer                 string
+	audience               string
+	refreshInterval        time.Duration
+	refreshUnknownInterval time.Duration
+	ownerClaim             string
+	scopesClaim            string
+	scopeMapping           []*JWTScopeMappingConfig
+}
+
+type JWTScopeMappingConfig struct {
+	value  string
+	scopes []string
+}
+
+func newJWTConfig(prefix string, v *viper.Viper) *JWTConfig {
+	v.SetDefault(path(prefix, "enabled"), false)
+	v.SetDefault(path(prefix, "refreshInterval"), "1h")
+	v.SetDefault(path(prefix, "refreshUnknownInterval"), "5m")
+	v.SetDefault(path(prefix, "ownerClaim"), "sub")
+	v.SetDefault(path(prefix, "scopesClaim"), "scope")
+
+	var rawScopeMapping []struct {
+		Value  string   `mapstructure:"value"`
+		Scopes []string `mapstructure:"scopes"`
+	}
+	v.UnmarshalKey(path(prefix, "scopeMapping"), &rawScopeMapping)
+
+	scopeMapping := make([]*JWTScopeMappingConfig, 0, len(rawScopeMapping))
+	for _, raw := range rawScopeMapping {
+		scopeMapping = append(scopeMapping, &JWTScopeMappingConfig{
+			value:  raw.Value,
+			scopes: raw.Scopes,
+		})
+	}
+
+	return &JWTConfig{
+		enabled:                v.GetBool(path(prefix, "enabled")),
+		jwksURL:                v.GetString(path(prefix, "jwksUrl")),
+		issuer:                 v.GetString(path(prefix, "issuer")),
+		audience:               v.GetString(path(prefix, "audience")),
+		refreshInterval:        v.GetDuration(path(prefix, "refreshInterval")),
+		refreshUnknownInterval: v.GetDuration(path(prefix, "refreshUnknownInterval")),
+		ownerClaim:             v.GetString(path(prefix, "ownerClaim")),
+		scopesClaim:            v.GetString(path(prefix, "scopesClaim")),
+		scopeMapping:           scopeMapping,
+	}
+}
+
+func (c *JWTConfig) Enabled() bool {
+	return c.enabled
+}
+
+// JWKSURL is where the keys verifying tokens are fetched.
+func (c *JWTConfig) JWKSURL() string {
+	return c.jwksURL
+}
+
+func (c *JWTConfig) Issuer() string {
+	return c.issuer
+}
+
+func (c *JWTConfig) Audience() string {
+	return c.audience
+}
+
+// RefreshInterval is how often the keys are fetched again.
+func (c *JWTConfig) RefreshInterval() time.Duration {
+	return c.refreshInterval
+}
+
+// RefreshUnknownInterval limits fetches caused by tokens with an unknown key
+// id, which happen when the keys are rotated.
+func (c *JWTConfig) RefreshUnknownInterval() time.Duration {
+	return c.refreshUnknownInterval
+}
+
+// OwnerClaim is the claim stored as the owner of uploaded files.
+func (c *JWTConfig) OwnerClaim() string {
+	return c.ownerClaim
+}
+
+// ScopesClaim is a claim with a space separated string or a list of scopes.
+func (c *JWTConfig) ScopesClaim() string {
+	return c.scopesClaim
+}
+
+// ScopeMapping maps values of the scopes claim to scopes, values without a
+// mapping are used as scopes.
+func (c *JWTConfig) ScopeMapping() []*JWTScopeMappingConfig {
+	return c.scopeMapping
+}
+
+func (c *JWTConfig) Validate() error {
+	u, err := url.Parse(c.jwksURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("invalid jwksUrl: %s", c.jwksURL)
+	}
+
+	if len(c.issuer) == 0 {
+		return errors.New("issuer is required")
+	}
+
+	if len(c.audience) == 0 {
+		return errors.New("audience is required")
+	}
+
+	if c.refreshInterval <= 0 {
+		return fmt.Errorf("invalid refreshInterval: %s", c.refreshInterval)
+	}
+
+	if c.refreshUnknownInterval <= 0 {
+		return fmt.Errorf("invalid refreshUnknownInterval: %s", c.refreshUnknownInterval)
+	}
+
+	if len(c.ownerClaim) == 0 {
+		return errors.New("ownerClaim is required")
+	}
+
+	if len(c.scopesClaim) == 0 {
+		return errors.New("scopesClaim is required")
+	}
+
+	for i, mapping := range c.scopeMapping {
+		if err := mapping.Validate(); err != nil {
+			return fmt.Errorf("invalid scope mapping %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Value is a value of the scopes claim.
+func (c *JWTScopeMappingConfig) Value() string {
+	return c.value
+}
+
+func (c *JWTScopeMappingConfig) Scopes() []string {
+	return c.scopes
+}
+
+func (c *JWTScopeMappingConfig) Validate() error {
+	if len(c.value) == 0 {
+		return errors.New("value is required")
+	}
+
+	if len(c.scopes) == 0 {
+		return fmt.Errorf("scopes of %s are required", c.value)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	MetadataStoreSidecar  = "sidecar"
+	MetadataStoreSQLite   = "sqlite"
+	MetadataStorePostgres = "postgres"
+)
+
+type MetadataStoreConfig struct {
+	storeType string
+	sqlite    *SQLiteConfig
+	postgres  *PostgresConfig
+}
+
+func newMetadataStoreConfig(prefix string, v *viper.Viper) *MetadataStoreConfig {
+	v.SetDefault(path(prefix, "type"), MetadataStoreSidecar)
+
+	return &MetadataStoreConfig{
+		storeType: v.GetString(path(prefix, "type")),
+		sqlite:    newSQLiteConfig(path(prefix, "sqlite"), v),
+		postgres:  newPostgresConfig(path(prefix, "postgres"), v),
+	}
+}
+
+func (c *MetadataStoreConfig) Type() string {
+	return c.storeType
+}
+
+func (c *MetadataStoreConfig) SQLite() *SQLiteConfig {
+	return c.sqlite
+}
+
+func (c *MetadataStoreConfig) Postgres() *PostgresConfig {
+	return c.postgres
+}
+
+func (c *MetadataStoreConfig) Validate() error {
+	switch c.storeType {
+	case MetadataStoreSidecar:
+		return nil
+	case MetadataStoreSQLite:
+		return c.sqlite.Validate()
+	case MetadataStorePostgres:
+		return c.postgres.Validate()
+	default:
+		return fmt.Errorf("invalid type: %s", c.storeType)
+	}
+}
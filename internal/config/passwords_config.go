@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type PasswordsConfig struct {
+	cookieSecret   string
+	cookieLifetime time.Duration
+	maxAttempts    int
+	attemptsWindow time.Duration
+}
+
+func newPasswordsConfig(prefix string, v *viper.Viper) *PasswordsConfig {
+	v.SetDefault(path(prefix, "cookieSecret"), "")
+	v.SetDefault(path(prefix, "cookieLifetime"), "15m")
+	v.SetDefault(path(prefix, "maxAttempts"), 5)
+	v.SetDefault(path(prefix, "attemptsWindow"), "15m")
+
+	return &PasswordsConfig{
+		cookieSecret:   v.GetString(path(prefix, "cookieSecret")),
+		cookieLifetime: v.GetDuration(path(prefix, "cookieLifetime")),
+		maxAttempts:    v.GetInt(path(prefix, "maxAttempts")),
+		attemptsWindow: v.GetDuration(path(prefix, "attemptsWindow")),
+	}
+}
+
+// CookieSecret signs the cookies of entered passwords. Empty generates a
+// secret at startup, so the cookies are dropped on restart and are not
+// accepted by other replicas.
+func (c *PasswordsConfig) CookieSecret() string {
+	return c.cookieSecret
+}
+
+// CookieLifetime is how long a file is read without entering its password
+// again.
+func (c *PasswordsConfig) CookieLifetime() time.Duration {
+	return c.cookieLifetime
+}
+
+// MaxAttempts is how many wrong passwords a client can enter for a file
+// within AttemptsWindow.
+func (c *PasswordsConfig) MaxAttempts() int {
+	return c.maxAttempts
+}
+
+func (c *PasswordsConfig) AttemptsWindow() time.Duration {
+	return c.attemptsWindow
+}
+
+func (c *PasswordsConfig) Validate() error {
+	if len(c.cookieSecret) > 0 && len(c.cookieSecret) < minSigningSecretSize {
+		return fmt.Errorf("cookieSecret must have at least %d characters", minSigningSecretSize)
+	}
+
+	if c.cookieLifetime <= 0 {
+		return fmt.Errorf("invalid cookieLifetime: %s", c.cookieLifetime)
+	}
+
+	if c.maxAttempts <= 0 {
+		return fmt.Errorf("invalid maxAttempts: %d", c.maxAttempts)
+	}
+
+	if c.attemptsWindow <= 0 {
+		return fmt.Errorf("invalid attemptsWindow: %s", c.attemptsWindow)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/viper"
+)
+
+type PostgresConfig struct {
+	host     string
+	port     int
+	database string
+	sslMode  string
+	username string
+	password string
+}
+
+func newPostgresConfig(prefix string, v *viper.Viper) *PostgresConfig {
+	v.SetDefault(path(prefix, "host"), "localhost")
+	v.SetDefault(path(prefix, "port"), 5432)
+	v.SetDefault(path(prefix, "database"), "file_hosting")
+	v.SetDefault(path(prefix, "sslMode"), "disable")
+
+	return &PostgresConfig{
+		host:     v.GetString(path(prefix, "host")),
+		port:     v.GetInt(path(prefix, "port")),
+		database: v.GetString(path(prefix, "database")),
+		sslMode:  v.GetString(path(prefix, "sslMode")),
+		username: v.GetString("POSTGRES_USERNAME"),
+		password: v.GetString("POSTGRES_PASSWORD"),
+	}
+}
+
+func (c *PostgresConfig) Host() string {
+	return c.host
+}
+
+func (c *PostgresConfig) Port() int {
+	return c.port
+}
+
+func (c *PostgresConfig) Database() string {
+	return c.database
+}
+
+func (c *PostgresConfig) SSLMode() string {
+	return c.sslMode
+}
+
+func (c *PostgresConfig) Username() string {
+	return c.username
+}
+
+func (c *PostgresConfig) Password() string {
+	return c.password
+}
+
+func (c *PostgresConfig) URL() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.username, c.password),
+		Host:     fmt.Sprintf("%s:%d", c.host, c.port),
+		Path:     c.database,
+		RawQuery: url.Values{"sslmode": {c.sslMode}}.Encode(),
+	}
+	return u.String()
+}
+
+func (c *PostgresConfig) Validate() error {
+	if c.host == "" {
+		return fmt.Errorf("invalid host: %s", c.host)
+	}
+
+	if c.port < 0 || c.port > 65535 {
+		return fmt.Errorf("invalid port: %d", c.port)
+	}
+
+	if c.database == "" {
+		return fmt.Errorf("invalid database: %s", c.database)
+	}
+
+	if c.username == "" {
+		return fmt.Errorf("invalid username: %s", c.username)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// maxPresignExpiration is the longest lifetime of a presigned S3 URL.
+const maxPresignExpiration = 7 * 24 * time.Hour
+
+type PresignConfig struct {
+	enabled    bool
+	expiration time.Duration
+}
+
+func newPresignConfig(prefix string, v *viper.Viper) *PresignConfig {
+	v.SetDefault(path(prefix, "enabled"), false)
+	v.SetDefault(path(prefix, "expiration"), "15m")
+
+	return &PresignConfig{
+		enabled:    v.GetBool(path(prefix, "enabled")),
+		expiration: v.GetDuration(path(prefix, "expiration")),
+	}
+}
+
+func (c *PresignConfig) Enabled() bool {
+	return c.enabled
+}
+
+func (c *PresignConfig) Expiration() time.Duration {
+	return c.expiration
+}
+
+func (c *PresignConfig) Validate() error {
+	if c.expiration < time.Second || c.expiration > maxPresignExpiration {
+		return fmt.Errorf("invalid expiration: %s", c.expiration)
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ type RabbitMQConfig struct {
 	port     int
 	username string
 	password string
+	retry    *RetryConfig
 }
 
 func newRabbitMQConfig(prefix string, v *viper.Viper) *RabbitMQConfig {
@@ -22,6 +23,7 @@ func newRabbitMQConfig(prefix string, v *viper.Viper) *RabbitMQConfig {
 		port:     v.GetInt(path(prefix, "port")),
 		username: v.GetString("RABBITMQ_USERNAME"),
 		password: v.GetString("RABBITMQ_PASSWORD"),
+		retry:    newRetryConfig(path(prefix, "retry"), v),
 	}
 }
 
@@ -41,6 +43,10 @@ func (c *RabbitMQConfig) Password() string {
 	return c.password
 }
 
+func (c *RabbitMQConfig) Retry() *RetryConfig {
+	return c.retry
+}
+
 func (c *RabbitMQConfig) URL() string {
 	return fmt.Sprintf("amqp://%s:%s@%s:%d", c.username, c.password, c.host, c.port)
 }
@@ -62,5 +68,9 @@ func (c *RabbitMQConfig) Validate() error {
 		return fmt.Errorf("invalid password: %s", c.password)
 	}
 
+	if err := c.retry.Validate(); err != nil {
+		return fmt.Errorf("invalid retry config: %w", err)
+	}
+
 	return nil
 }
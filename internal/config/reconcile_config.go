@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type ReconcileConfig struct {
+	interval time.Duration
+}
+
+func newReconcileConfig(prefix string, v *viper.Viper) *ReconcileConfig {
+	v.SetDefault(path(prefix, "interval"), "1h")
+
+	return &ReconcileConfig{
+		interval: v.GetDuration(path(prefix, "interval")),
+	}
+}
+
+// Interval is how often files are reconciled after the startup, 0 reconciles
+// at startup only.
+func (c *ReconcileConfig) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *ReconcileConfig) Validate() error {
+	if c.interval < 0 {
+		return fmt.Errorf("invalid interval: %s", c.interval)
+	}
+
+	return nil
+}
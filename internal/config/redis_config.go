@@ -7,6 +7,7 @@ import (
 )
 
 type RedisConfig struct {
+	enabled  bool
 	host     string
 	port     int
 	password string
@@ -14,11 +15,13 @@ type RedisConfig struct {
 }
 
 func newRedisConfig(prefix string, v *viper.Viper) *RedisConfig {
+	v.SetDefault(path(prefix, "enabled"), true)
 	v.SetDefault(path(prefix, "host"), "localhost")
 	v.SetDefault(path(prefix, "port"), 6379)
 	v.SetDefault(path(prefix, "database"), 0)
 
 	return &RedisConfig{
+		enabled:  v.GetBool(path(prefix, "enabled")),
 		host:     v.GetString(path(prefix, "host")),
 		port:     v.GetInt(path(prefix, "port")),
 		password: v.GetString("REDIS_PASSWORD"),
@@ -26,6 +29,11 @@ func newRedisConfig(prefix string, v *viper.Viper) *RedisConfig {
 	}
 }
 
+// Enabled tells whether file metadata is cached in Redis.
+func (c *RedisConfig) Enabled() bool {
+	return c.enabled
+}
+
 func (c *RedisConfig) Host() string {
 	return c.host
 }
@@ -47,6 +55,10 @@ func (c *RedisConfig) URL() string {
 }
 
 func (c *RedisConfig) Validate() error {
+	if !c.enabled {
+		return nil
+	}
+
 	if c.host == "" {
 		return fmt.Errorf("invalid host: %s", c.host)
 	}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type RetryConfig struct {
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func newRetryConfig(prefix string, v *viper.Viper) *RetryConfig {
+	v.SetDefault(path(prefix, "maxRetries"), 5)
+	v.SetDefault(path(prefix, "backoff"), "1m")
+	v.SetDefault(path(prefix, "maxBackoff"), "1h")
+
+	return &RetryConfig{
+		maxRetries: v.GetInt(path(prefix, "maxRetries")),
+		backoff:    v.GetDuration(path(prefix, "backoff")),
+		maxBackoff: v.GetDuration(path(prefix, "maxBackoff")),
+	}
+}
+
+// MaxRetries is how many times a failed message is retried before it is
+// dead-lettered.
+func (c *RetryConfig) MaxRetries() int {
+	return c.maxRetries
+}
+
+// Backoff is the delay before the first retry, doubled for each next one.
+func (c *RetryConfig) Backoff() time.Duration {
+	return c.backoff
+}
+
+func (c *RetryConfig) MaxBackoff() time.Duration {
+	return c.maxBackoff
+}
+
+func (c *RetryConfig) Validate() error {
+	if c.maxRetries < 0 {
+		return fmt.Errorf("invalid maxRetries: %d", c.maxRetries)
+	}
+
+	if c.backoff <= 0 {
+		return fmt.Errorf("invalid backoff: %s", c.backoff)
+	}
+
+	if c.maxBackoff < c.backoff {
+		return fmt.Errorf("invalid maxBackoff: %s", c.maxBackoff)
+	}
+
+	return nil
+}
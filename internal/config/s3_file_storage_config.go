@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/spf13/viper"
 )
@@ -15,12 +16,19 @@ type S3FileStorageConfig struct {
 	accessKey string
 	secretKey string
 	useSSL    bool
+
+	partSizeInMB int
+	concurrency  int
+
+	presign *PresignConfig
 }
 
 func newS3FileStorageConfig(prefix string, v *viper.Viper) *S3FileStorageConfig {
 	v.SetDefault(path(prefix, "enabled"), false)
 	v.SetDefault(path(prefix, "useSSL"), true)
 	v.SetDefault(path(prefix, "directory"), "")
+	v.SetDefault(path(prefix, "partSizeInMB"), 16)
+	v.SetDefault(path(prefix, "concurrency"), 4)
 
 	return &S3FileStorageConfig{
 		enabled:   v.GetBool(path(prefix, "enabled")),
@@ -31,6 +39,11 @@ func newS3FileStorageConfig(prefix string, v *viper.Viper) *S3FileStorageConfig
 		accessKey: v.GetString("S3_ACCESS_KEY"),
 		secretKey: v.GetString("S3_SECRET_KEY"),
 		useSSL:    v.GetBool(path(prefix, "useSSL")),
+
+		partSizeInMB: v.GetInt(path(prefix, "partSizeInMB")),
+		concurrency:  v.GetInt(path(prefix, "concurrency")),
+
+		presign: newPresignConfig(path(prefix, "presign"), v),
 	}
 }
 
@@ -66,6 +79,18 @@ func (c *S3FileStorageConfig) UseSSL() bool {
 	return c.useSSL
 }
 
+func (c *S3FileStorageConfig) PartSizeInMB() int {
+	return c.partSizeInMB
+}
+
+func (c *S3FileStorageConfig) Concurrency() int {
+	return c.concurrency
+}
+
+func (c *S3FileStorageConfig) Presign() *PresignConfig {
+	return c.presign
+}
+
 func (c *S3FileStorageConfig) Validate() error {
 	if c.endpoint == "" {
 		return errors.New("endpoint cannot be empty")
@@ -82,5 +107,16 @@ func (c *S3FileStorageConfig) Validate() error {
 	if c.secretKey == "" {
 		return errors.New("secret key cannot be empty")
 	}
+	if c.partSizeInMB < 5 || c.partSizeInMB > 5*1024 {
+		return errors.New("part size must be between 5 and 5120 MB")
+	}
+	if c.concurrency < 1 {
+		return errors.New("concurrency must be greater than 0")
+	}
+	if c.presign.enabled {
+		if err := c.presign.Validate(); err != nil {
+			return fmt.Errorf("invalid presign config: %w", err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	SchedulerSweeper  = "sweeper"
+	SchedulerRedis    = "redis"
+	SchedulerRabbitMQ = "rabbitmq"
+)
+
+type SchedulerConfig struct {
+	schedulerType string
+	sweeper       *SweeperConfig
+}
+
+func newSchedulerConfig(prefix string, v *viper.Viper) *SchedulerConfig {
+	v.SetDefault(path(prefix, "type"), SchedulerRedis)
+
+	return &SchedulerConfig{
+		schedulerType: v.GetString(path(prefix, "type")),
+		sweeper:       newSweeperConfig(path(prefix, "sweeper"), v),
+	}
+}
+
+// Type is where deletions of expiring files wait until they are due.
+func (c *SchedulerConfig) Type() string {
+	return c.schedulerType
+}
+
+func (c *SchedulerConfig) Sweeper() *SweeperConfig {
+	return c.sweeper
+}
+
+func (c *SchedulerConfig) Validate() error {
+	switch c.schedulerType {
+	case SchedulerSweeper:
+		return c.sweeper.Validate()
+	case SchedulerRedis, SchedulerRabbitMQ:
+		return nil
+	default:
+		return fmt.Errorf("invalid type: %s", c.schedulerType)
+	}
+}
+
+type SweeperConfig struct {
+	interval time.Duration
+}
+
+func newSweeperConfig(prefix string, v *viper.Viper) *SweeperConfig {
+	v.SetDefault(path(prefix, "interval"), "1m")
+
+	return &SweeperConfig{
+		interval: v.GetDuration(path(prefix, "interval")),
+	}
+}
+
+// Interval is how often the metadata is scanned for expired files.
+func (c *SweeperConfig) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *SweeperConfig) Validate() error {
+	if c.interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", c.interval)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type SignedURLsConfig struct {
+	defaultLifetime time.Duration
+	maxLifetime     time.Duration
+	keys            []*SigningKeyConfig
+}
+
+type SigningKeyConfig struct {
+	id     string
+	secret string
+}
+
+// minSigningSecretSize is the minimal size of a signing secret in bytes.
+const minSigningSecretSize = 32
+
+func newSignedURLsConfig(prefix string, v *viper.Viper) *SignedURLsConfig {
+	v.SetDefault(path(prefix, "defaultLifetime"), "1h")
+	v.SetDefault(path(prefix, "maxLifetime"), "168h")
+
+	var rawKeys []struct {
+		Id     string `mapstructure:"id"`
+		Secret string `mapstructure:"secret"`
+	}
+	v.UnmarshalKey(path(prefix, "keys"), &rawKeys)
+
+	keys := make([]*SigningKeyConfig, 0, len(rawKeys))
+	for _, raw := range rawKeys {
+		keys = append(keys, &SigningKeyConfig{
+			id:     raw.Id,
+			secret: raw.Secret,
+		})
+	}
+
+	return &SignedURLsConfig{
+		defaultLifetime: v.GetDuration(path(prefix, "defaultLifetime")),
+		maxLifetime:     v.GetDuration(path(prefix, "maxLifetime")),
+		keys:            keys,
+	}
+}
+
+// Enabled tells whether signing keys are configured.
+func (c *SignedURLsConfig) Enabled() bool {
+	return len(c.keys) > 0
+}
+
+// DefaultLifetime is the lifetime of URLs signed without one.
+func (c *SignedURLsConfig) DefaultLifetime() time.Duration {
+	return c.defaultLifetime
+}
+
+func (c *SignedURLsConfig) MaxLifetime() time.Duration {
+	return c.maxLifetime
+}
+
+// Keys sign URLs by the first key, URLs signed by any of them are accepted.
+func (c *SignedURLsConfig) Keys() []*SigningKeyConfig {
+	return c.keys
+}
+
+func (c *SignedURLsConfig) Validate() error {
+	if c.defaultLifetime <= 0 {
+		return fmt.Errorf("invalid defaultLifetime: %s", c.defaultLifetime)
+	}
+
+	if c.maxLifetime < c.defaultLifetime {
+		return fmt.Errorf("maxLifetime %s is less than defaultLifetime %s", c.maxLifetime, c.defaultLifetime)
+	}
+
+	ids := make(map[string]bool, len(c.keys))
+	for i, key := range c.keys {
+		if err := key.Validate(); err != nil {
+			return fmt.Errorf("invalid key %d: %w", i+1, err)
+		}
+		if ids[key.id] {
+			return fmt.Errorf("duplicate key id: %s", key.id)
+		}
+		ids[key.id] = true
+	}
+
+	return nil
+}
+
+func (c *SigningKeyConfig) Id() string {
+	return c.id
+}
+
+func (c *SigningKeyConfig) Secret() string {
+	return c.secret
+}
+
+func (c *SigningKeyConfig) Validate() error {
+	if len(c.id) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(c.secret) < minSigningSecretSize {
+		return fmt.Errorf("secret of %s must have at least %d characters", c.id, minSigningSecretSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/spf13/viper"
+)
+
+type SQLiteConfig struct {
+	path string
+}
+
+func newSQLiteConfig(prefix string, v *viper.Viper) *SQLiteConfig {
+	v.SetDefault(path(prefix, "path"), "data/metadata.db")
+
+	return &SQLiteConfig{
+		path: v.GetString(path(prefix, "path")),
+	}
+}
+
+func (c *SQLiteConfig) Path() string {
+	return c.path
+}
+
+func (c *SQLiteConfig) Validate() error {
+	if c.path == "" {
+		return errors.New("path cannot be empty")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type TombstonesConfig struct {
+	retention time.Duration
+}
+
+func newTombstonesConfig(prefix string, v *viper.Viper) *TombstonesConfig {
+	v.SetDefault(path(prefix, "retention"), "720h")
+
+	return &TombstonesConfig{
+		retention: v.GetDuration(path(prefix, "retention")),
+	}
+}
+
+// Retention is how long expired and deleted files are answered with 410, 0
+// disables tombstones.
+func (c *TombstonesConfig) Retention() time.Duration {
+	return c.retention
+}
+
+func (c *TombstonesConfig) Validate() error {
+	if c.retention < 0 {
+		return fmt.Errorf("invalid retention: %s", c.retention)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type TrashConfig struct {
+	retention time.Duration
+}
+
+func newTrashConfig(prefix string, v *viper.Viper) *TrashConfig {
+	v.SetDefault(path(prefix, "retention"), "168h")
+
+	return &TrashConfig{
+		retention: v.GetDuration(path(prefix, "retention")),
+	}
+}
+
+// Retention is how long deleted files are kept in the trash, 0 deletes files
+// immediately.
+func (c *TrashConfig) Retention() time.Duration {
+	return c.retention
+}
+
+func (c *TrashConfig) Validate() error {
+	if c.retention < 0 {
+		return fmt.Errorf("invalid retention: %s", c.retention)
+	}
+
+	return nil
+}
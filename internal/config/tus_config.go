@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type TusConfig struct {
+	enabled     bool
+	maxSizeInMB int
+	expiration  time.Duration
+}
+
+func newTusConfig(prefix string, v *viper.Viper) *TusConfig {
+	v.SetDefault(path(prefix, "enabled"), true)
+	v.SetDefault(path(prefix, "maxSizeInMB"), 1024)
+	v.SetDefault(path(prefix, "expiration"), "24h")
+
+	return &TusConfig{
+		enabled:     v.GetBool(path(prefix, "enabled")),
+		maxSizeInMB: v.GetInt(path(prefix, "maxSizeInMB")),
+		expiration:  v.GetDuration(path(prefix, "expiration")),
+	}
+}
+
+func (c *TusConfig) Enabled() bool {
+	return c.enabled
+}
+
+func (c *TusConfig) MaxSizeInMB() int {
+	return c.maxSizeInMB
+}
+
+func (c *TusConfig) Expiration() time.Duration {
+	return c.expiration
+}
+
+func (c *TusConfig) Validate() error {
+	if c.maxSizeInMB <= 0 {
+		return fmt.Errorf("invalid max size in MB: %d", c.maxSizeInMB)
+	}
+
+	if c.expiration <= 0 {
+		return fmt.Errorf("invalid expiration: %s", c.expiration)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+type VersionsConfig struct {
+	keepLast int
+	keepDays int
+}
+
+func newVersionsConfig(prefix string, v *viper.Viper) *VersionsConfig {
+	v.SetDefault(path(prefix, "keepLast"), 0)
+	v.SetDefault(path(prefix, "keepDays"), 0)
+
+	return &VersionsConfig{
+		keepLast: v.GetInt(path(prefix, "keepLast")),
+		keepDays: v.GetInt(path(prefix, "keepDays")),
+	}
+}
+
+// KeepLast is the count of previous versions kept for a file, 0 keeps all.
+func (c *VersionsConfig) KeepLast() int {
+	return c.keepLast
+}
+
+// KeepDays is how long previous versions are kept, 0 keeps them until the
+// file expires.
+func (c *VersionsConfig) KeepDays() int {
+	return c.keepDays
+}
+
+func (c *VersionsConfig) Validate() error {
+	if c.keepLast < 0 {
+		return fmt.Errorf("invalid keep last: %d", c.keepLast)
+	}
+
+	if c.keepDays < 0 {
+		return fmt.Errorf("invalid keep days: %d", c.keepDays)
+	}
+
+	return nil
+}
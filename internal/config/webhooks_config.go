@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type WebhooksConfig struct {
+	enabled       bool
+	leadTime      time.Duration
+	timeout       time.Duration
+	workers       int
+	subscriptions []*WebhookSubscriptionConfig
+	retry         *RetryConfig
+}
+
+type WebhookSubscriptionConfig struct {
+	url    string
+	secret string
+	events []string
+}
+
+func newWebhooksConfig(prefix string, v *viper.Viper) *WebhooksConfig {
+	v.SetDefault(path(prefix, "enabled"), false)
+	v.SetDefault(path(prefix, "leadTime"), "24h")
+	v.SetDefault(path(prefix, "timeout"), "10s")
+	v.SetDefault(path(prefix, "workers"), 4)
+
+	var rawSubscriptions []struct {
+		Url    string   `mapstructure:"url"`
+		Secret string   `mapstructure:"secret"`
+		Events []string `mapstructure:"events"`
+	}
+	v.UnmarshalKey(path(prefix, "subscriptions"), &rawSubscriptions)
+
+	subscriptions := make([]*WebhookSubscriptionConfig, 0, len(rawSubscriptions))
+	for _, raw := range rawSubscriptions {
+		subscriptions = append(subscriptions, &WebhookSubscriptionConfig{
+			url:    raw.Url,
+			secret: raw.Secret,
+			events: raw.Events,
+		})
+	}
+
+	return &WebhooksConfig{
+		enabled:       v.GetBool(path(prefix, "enabled")),
+		leadTime:      v.GetDuration(path(prefix, "leadTime")),
+		timeout:       v.GetDuration(path(prefix, "timeout")),
+		workers:       v.GetInt(path(prefix, "workers")),
+		subscriptions: subscriptions,
+		retry:         newRetryConfig(path(prefix, "retry"), v),
+	}
+}
+
+func (c *WebhooksConfig) Enabled() bool {
+	return c.enabled
+}
+
+// LeadTime is how long before a file expires the file.expiring event is
+// sent, 0 disables the event.
+func (c *WebhooksConfig) LeadTime() time.Duration {
+	return c.leadTime
+}
+
+// Timeout bounds a single request to a webhook.
+func (c *WebhooksConfig) Timeout() time.Duration {
+	return c.timeout
+}
+
+// Workers is how many requests to webhooks are sent in parallel.
+func (c *WebhooksConfig) Workers() int {
+	return c.workers
+}
+
+// Subscriptions are set in the config, others are added by the API.
+func (c *WebhooksConfig) Subscriptions() []*WebhookSubscriptionConfig {
+	return c.subscriptions
+}
+
+func (c *WebhooksConfig) Retry() *RetryConfig {
+	return c.retry
+}
+
+func (c *WebhooksConfig) Validate() error {
+	if c.leadTime < 0 {
+		return fmt.Errorf("invalid leadTime: %s", c.leadTime)
+	}
+
+	if c.timeout <= 0 {
+		return fmt.Errorf("invalid timeout: %s", c.timeout)
+	}
+
+	if c.workers <= 0 {
+		return fmt.Errorf("invalid workers: %d", c.workers)
+	}
+
+	for i, subscription := range c.subscriptions {
+		if err := subscription.Validate(); err != nil {
+			return fmt.Errorf("invalid subscription %d: %w", i+1, err)
+		}
+	}
+
+	if err := c.retry.Validate(); err != nil {
+		return fmt.Errorf("invalid retry config: %w", err)
+	}
+
+	return nil
+}
+
+func (c *WebhookSubscriptionConfig) URL() string {
+	return c.url
+}
+
+// Secret signs the requests to the webhook.
+func (c *WebhookSubscriptionConfig) Secret() string {
+	return c.secret
+}
+
+// Events are the sent event types, empty for all.
+func (c *WebhookSubscriptionConfig) Events() []string {
+	return c.events
+}
+
+func (c *WebhookSubscriptionConfig) Validate() error {
+	u, err := url.Parse(c.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("invalid url: %s", c.url)
+	}
+
+	if len(c.secret) == 0 {
+		return fmt.Errorf("secret of %s is required", c.url)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Scope is a permission granted to an API key.
+type Scope string
+
+const (
+	// ScopeUploadNamed allows uploads with a chosen name and changes of the
+	// content or the expiry of named files.
+	ScopeUploadNamed Scope = "upload-named"
+	// ScopeUploadPermanent allows files which never expire.
+	ScopeUploadPermanent Scope = "upload-permanent"
+	// ScopeList allows listing files, versions and the trash.
+	ScopeList   Scope = "list"
+	ScopeRename Scope = "rename"
+	// ScopeDelete allows deleting files and versions and restoring them from
+	// the trash.
+	ScopeDelete Scope = "delete"
+	// ScopeReadPrivate allows reading private files and signing URLs of
+	// files.
+	ScopeReadPrivate Scope = "read-private"
+	// ScopeAdmin grants all scopes and manages webhooks.
+	ScopeAdmin Scope = "admin"
+)
+
+var Scopes = []Scope{
+	ScopeUploadNamed,
+	ScopeUploadPermanent,
+	ScopeList,
+	ScopeRename,
+	ScopeDelete,
+	ScopeReadPrivate,
+	ScopeAdmin,
+}
+
+func (s Scope) IsValid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// APIKey is a key accepted in the Authorization header as
+// "Bearer <id>.<secret>". Only a hash of the secret is kept. Verified JWTs
+// are represented by keys too, with the Owner of their claims.
+type APIKey struct {
+	Id string
+	// Label names the key in logs.
+	Label  string
+	Hash   string
+	Scopes []Scope
+	// ExpiresAt is when the key stops being accepted, zero for never.
+	ExpiresAt time.Time
+	// Owner is stored in the metadata of uploaded files, empty for keys of
+	// the config.
+	Owner string
+}
+
+func (k *APIKey) HasScope(scope Scope) bool {
+	return slices.Contains(k.Scopes, ScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
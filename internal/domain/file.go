@@ -1,18 +1,9 @@
 package domain
 
-import (
-	"github.com/goccy/go-json"
-)
+import "io"
 
+// File is an opened hosted file. Content must be closed by the caller.
 type File struct {
-	Content  []byte        `json:"content"`
-	Metadata *FileMetadata `json:"metadata"`
-}
-
-func NewFileFromBytes(data []byte) (*File, error) {
-	var file File
-	if err := json.Unmarshal(data, &file); err != nil {
-		return nil, err
-	}
-	return &file, nil
+	Content  io.ReadSeekCloser
+	Metadata *FileMetadata
 }
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// PermanentDuration is the duration of files which never expire.
+const PermanentDuration = "-1"
+
+// FileExpiry is the requested lifetime of an uploaded file. Duration is a Go
+// duration ("90m"), an ISO 8601 duration ("P1DT12H"), whole days or weeks
+// ("7d", "2w") or PermanentDuration. ExpiresAt is an absolute time instead.
+// Zero values request the default duration.
+type FileExpiry struct {
+	Duration  string
+	ExpiresAt time.Time
+}
+
+// ExpiryPolicy limits the lifetime of uploaded files. Max 0 allows any
+// duration and permanent files.
+type ExpiryPolicy struct {
+	Default time.Duration
+	Min     time.Duration
+	Max     time.Duration
+}
+
+// ExpiryPolicies are the policies of anonymous uploads and of uploads
+// authorized by the API key.
+type ExpiryPolicies struct {
+	Anonymous  ExpiryPolicy
+	Authorized ExpiryPolicy
+}
+
+func (e FileExpiry) IsPermanent() bool {
+	return e.Duration == PermanentDuration
+}
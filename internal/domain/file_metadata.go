@@ -2,20 +2,66 @@ package domain
 
 import (
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/goccy/go-json"
 )
 
+// PermanentExpiredAt is the ExpiredAt of files which never expire.
+var PermanentExpiredAt = time.Unix(0, 0)
+
 type FileMetadata struct {
 	Id         string              `json:"id"`
 	Name       string              `json:"name"`
 	MimeType   string              `json:"mime_type"`
 	Sha1       string              `json:"sha1"`
+	Size       int64               `json:"size"`
 	Meta       map[string][]string `json:"meta"`
 	CreatedAt  time.Time           `json:"created_at"`
 	ExpiredAt  time.Time           `json:"expired_at"`
 	BackupName string              `json:"backup_name,omitempty"`
+	// MaxDownloads is how many times the file can be downloaded before it is
+	// deleted, 0 for no limit.
+	MaxDownloads int64 `json:"max_downloads,omitempty"`
+	// Owner is the identity of the JWT caller who uploaded the file, empty
+	// for files uploaded with API keys or anonymously.
+	Owner string `json:"owner,omitempty"`
+	// ManagementTokenHash is the SHA-256 of the management token of a file
+	// with a generated id, which authorizes deleting the file and changing
+	// its expiry and metadata without an API key.
+	ManagementTokenHash string `json:"management_token_hash,omitempty"`
+	// ManagementToken is set only in the result of the upload, it is not
+	// stored.
+	ManagementToken string `json:"-"`
+	// Private files are read only with an API key or a signed URL.
+	Private bool `json:"private,omitempty"`
+	// PasswordHash is the argon2id hash of the password required to read
+	// the file, empty for files without a password.
+	PasswordHash string `json:"password_hash,omitempty"`
+	// RemainingDownloads is filled for files with MaxDownloads, it is not
+	// stored.
+	RemainingDownloads *int64 `json:"remaining_downloads,omitempty"`
+}
+
+// FileMetadataView is FileMetadata as returned to clients and sent to
+// webhooks. It leaves out the hashes of the management token and of the
+// password, which are only stored.
+type FileMetadataView struct {
+	Id                 string              `json:"id"`
+	Name               string              `json:"name"`
+	MimeType           string              `json:"mime_type"`
+	Sha1               string              `json:"sha1"`
+	Size               int64               `json:"size"`
+	Meta               map[string][]string `json:"meta"`
+	CreatedAt          time.Time           `json:"created_at"`
+	ExpiredAt          time.Time           `json:"expired_at"`
+	BackupName         string              `json:"backup_name,omitempty"`
+	MaxDownloads       int64               `json:"max_downloads,omitempty"`
+	Owner              string              `json:"owner,omitempty"`
+	Private            bool                `json:"private,omitempty"`
+	PasswordProtected  bool                `json:"password_protected,omitempty"`
+	RemainingDownloads *int64              `json:"remaining_downloads,omitempty"`
 }
 
 func NewFileMetadataFromBytes(data []byte) (*FileMetadata, error) {
@@ -26,9 +72,76 @@ func NewFileMetadataFromBytes(data []byte) (*FileMetadata, error) {
 	return &metadata, nil
 }
 
+// Clone returns a copy of the stored fields of m which shares nothing with
+// m. ManagementToken and RemainingDownloads, which are not stored, are left
+// empty.
+func (m *FileMetadata) Clone() *FileMetadata {
+	clone := *m
+	clone.ManagementToken = ""
+	clone.RemainingDownloads = nil
+	if m.Meta != nil {
+		clone.Meta = make(map[string][]string, len(m.Meta))
+		for key, values := range m.Meta {
+			clone.Meta[key] = slices.Clone(values)
+		}
+	}
+	return &clone
+}
+
+// View returns the metadata shown to clients, nil for nil m.
+func (m *FileMetadata) View() *FileMetadataView {
+	if m == nil {
+		return nil
+	}
+
+	return &FileMetadataView{
+		Id:                 m.Id,
+		Name:               m.Name,
+		MimeType:           m.MimeType,
+		Sha1:               m.Sha1,
+		Size:               m.Size,
+		Meta:               m.Meta,
+		CreatedAt:          m.CreatedAt,
+		ExpiredAt:          m.ExpiredAt,
+		BackupName:         m.BackupName,
+		MaxDownloads:       m.MaxDownloads,
+		Owner:              m.Owner,
+		Private:            m.Private,
+		PasswordProtected:  m.IsPasswordProtected(),
+		RemainingDownloads: m.RemainingDownloads,
+	}
+}
+
+// FileMetadataViews returns the views of files.
+func FileMetadataViews(files []*FileMetadata) []*FileMetadataView {
+	views := make([]*FileMetadataView, len(files))
+	for i, file := range files {
+		views[i] = file.View()
+	}
+	return views
+}
+
 func (m *FileMetadata) UpdateContentType(content []byte) string {
 	if len(m.MimeType) == 0 {
 		m.MimeType = http.DetectContentType(content)
 	}
 	return m.MimeType
 }
+
+// IsPermanent reports whether the file never expires. Times are compared by
+// value, stores return them in different locations.
+func (m *FileMetadata) IsPermanent() bool {
+	return m.ExpiredAt.Equal(PermanentExpiredAt)
+}
+
+// IsPasswordProtected reports whether the file is read only with its
+// password.
+func (m *FileMetadata) IsPasswordProtected() bool {
+	return len(m.PasswordHash) > 0
+}
+
+// IsDownloadLimited reports whether the file is deleted after MaxDownloads
+// downloads.
+func (m *FileMetadata) IsDownloadLimited() bool {
+	return m.MaxDownloads > 0
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+const (
+	DefaultFileQueryLimit = 100
+	MaxFileQueryLimit     = 1000
+)
+
+type FileSort string
+
+const (
+	FileSortCreatedAt FileSort = "created_at"
+	FileSortName      FileSort = "name"
+	FileSortSize      FileSort = "size"
+)
+
+func (s FileSort) IsValid() bool {
+	switch s {
+	case FileSortCreatedAt, FileSortName, FileSortSize:
+		return true
+	}
+	return false
+}
+
+// FileQuery selects a page of files. Zero values of the filters match any file.
+type FileQuery struct {
+	NamePrefix string
+	// MimeType matches exactly or by type when it ends with "/*", e.g. "image/*".
+	MimeType      string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// ExpiredAfter and ExpiredBefore never match permanent files.
+	ExpiredAfter  time.Time
+	ExpiredBefore time.Time
+	HasBackup     *bool
+	Owner         string
+	// Meta matches files having the value among the values of each key.
+	Meta       map[string]string
+	Sort       FileSort
+	Descending bool
+	Limit      int
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+}
+
+type FilePage struct {
+	Files []*FileMetadata `json:"files"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total is the count of files matching the filters on all pages.
+	Total int64 `json:"total"`
+}
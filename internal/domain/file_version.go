@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// FileVersion is a version of a named file. Version is the unix time in
+// nanoseconds when the version was uploaded.
+type FileVersion struct {
+	Version  string        `json:"version"`
+	Current  bool          `json:"current"`
+	Metadata *FileMetadata `json:"metadata"`
+}
+
+// VersionRetention limits the previous versions kept for a file. Zero values
+// keep versions without limit.
+type VersionRetention struct {
+	KeepLast int
+	KeepFor  time.Duration
+}
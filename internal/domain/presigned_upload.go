@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// PresignedUpload is the state of a file uploaded by a client directly to
+// the storage through a presigned URL.
+type PresignedUpload struct {
+	Id           string        `json:"id"`
+	Url          string        `json:"url"`
+	UrlExpiredAt time.Time     `json:"url_expired_at"`
+	Metadata     *FileMetadata `json:"metadata"`
+	Duration     string        `json:"duration"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	ExpiredAt    time.Time     `json:"expired_at"`
+	FileId       string        `json:"file_id,omitempty"`
+}
+
+func NewPresignedUploadFromBytes(data []byte) (*PresignedUpload, error) {
+	var upload PresignedUpload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (u *PresignedUpload) IsFinalized() bool {
+	return len(u.FileId) > 0
+}
@@ -0,0 +1,12 @@
+package domain
+
+// ReconcileReport is the result of a walk over all hosted files.
+type ReconcileReport struct {
+	Files       int `json:"files"`
+	Deleted     int `json:"deleted"`
+	Rescheduled int `json:"rescheduled"`
+	// FilesWithoutMetadata are files whose content has no metadata.
+	FilesWithoutMetadata []string `json:"files_without_metadata"`
+	// MetadataWithoutFiles are ids of metadata whose content is missing.
+	MetadataWithoutFiles []string `json:"metadata_without_files"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// ResumableUpload is the state of a file uploaded in several requests.
+type ResumableUpload struct {
+	Id        string        `json:"id"`
+	Length    int64         `json:"length"`
+	Offset    int64         `json:"offset"`
+	Chunks    []int64       `json:"chunks"`
+	Metadata  *FileMetadata `json:"metadata"`
+	Duration  string        `json:"duration"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	CreatedAt time.Time     `json:"created_at"`
+	ExpiredAt time.Time     `json:"expired_at"`
+	FileId    string        `json:"file_id,omitempty"`
+	// ManagementToken of the file is returned by the request completing the
+	// upload, and kept until a client resuming the upload fetched it once in
+	// case that response was lost.
+	ManagementToken string `json:"management_token,omitempty"`
+}
+
+func NewResumableUploadFromBytes(data []byte) (*ResumableUpload, error) {
+	var upload ResumableUpload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (u *ResumableUpload) IsCompleted() bool {
+	return len(u.FileId) > 0
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// TombstoneReason tells why a file is gone.
+type TombstoneReason string
+
+const (
+	TombstoneExpired TombstoneReason = "expired"
+	TombstoneDeleted TombstoneReason = "deleted"
+	// TombstoneDownloadLimit is of files deleted after their last download.
+	TombstoneDownloadLimit TombstoneReason = "download_limit"
+)
+
+// Tombstone records that the file FileId is gone since GoneAt, until PurgeAt.
+type Tombstone struct {
+	FileId  string          `json:"file_id"`
+	Reason  TombstoneReason `json:"reason"`
+	GoneAt  time.Time       `json:"gone_at"`
+	PurgeAt time.Time       `json:"purge_at"`
+}
+
+// Message describes why the file is gone, for 410 responses.
+func (t *Tombstone) Message() string {
+	goneAt := t.GoneAt.UTC().Format(time.RFC3339)
+	switch t.Reason {
+	case TombstoneExpired:
+		return "File " + t.FileId + " expired at " + goneAt
+	case TombstoneDownloadLimit:
+		return "File " + t.FileId + " was deleted after its last download at " + goneAt
+	default:
+		return "File " + t.FileId + " was deleted at " + goneAt
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// TrashedFile is a deleted file kept in the trash until PurgeAt.
+type TrashedFile struct {
+	Id        string        `json:"id"`
+	FileId    string        `json:"file_id"`
+	DeletedAt time.Time     `json:"deleted_at"`
+	PurgeAt   time.Time     `json:"purge_at"`
+	Metadata  *FileMetadata `json:"metadata"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// SignedURL reads a private file without an API key until ExpiresAt.
+type SignedURL struct {
+	Url       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SigningKey is a secret signing URLs. Keys are rotated by signing with a
+// new key while URLs signed by the previous ones are still accepted.
+type SigningKey struct {
+	Id     string
+	Secret string
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// WebhookEventType is the kind of a change of a file sent to webhooks.
+type WebhookEventType string
+
+const (
+	WebhookFileUploaded WebhookEventType = "file.uploaded"
+	// WebhookFileOverwritten is sent for uploads replacing an existing file,
+	// whose previous content is kept as a version.
+	WebhookFileOverwritten WebhookEventType = "file.overwritten"
+	WebhookFileRenamed     WebhookEventType = "file.renamed"
+	// WebhookFileDeleted is sent for files deleted by a request or after
+	// their last download, the reason tells which.
+	WebhookFileDeleted WebhookEventType = "file.deleted"
+	WebhookFileExpired WebhookEventType = "file.expired"
+	// WebhookFileExpiring is sent the configured lead time before a file
+	// expires.
+	WebhookFileExpiring WebhookEventType = "file.expiring"
+)
+
+var WebhookEventTypes = []WebhookEventType{
+	WebhookFileUploaded,
+	WebhookFileOverwritten,
+	WebhookFileRenamed,
+	WebhookFileDeleted,
+	WebhookFileExpired,
+	WebhookFileExpiring,
+}
+
+func (t WebhookEventType) IsValid() bool {
+	return slices.Contains(WebhookEventTypes, t)
+}
+
+// WebhookEvent is the body of a webhook request.
+type WebhookEvent struct {
+	Id     string           `json:"id"`
+	Type   WebhookEventType `json:"type"`
+	FileId string           `json:"file_id"`
+	// OldFileId is the previous id of renamed files.
+	OldFileId  string            `json:"old_file_id,omitempty"`
+	Reason     TombstoneReason   `json:"reason,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Metadata   *FileMetadataView `json:"metadata,omitempty"`
+}
+
+// WebhookSubscription sends the events of Events, or all events when it is
+// empty, to Url. Requests are signed with Secret.
+type WebhookSubscription struct {
+	Id     string             `json:"id"`
+	Url    string             `json:"url"`
+	Secret string             `json:"secret,omitempty"`
+	Events []WebhookEventType `json:"events,omitempty"`
+	// Static subscriptions are set in the config and can not be deleted by
+	// the API.
+	Static    bool      `json:"static"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewWebhookSubscriptionFromBytes(data []byte) (*WebhookSubscription, error) {
+	var subscription WebhookSubscription
+	if err := json.Unmarshal(data, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *WebhookSubscription) Accepts(eventType WebhookEventType) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, eventType)
+}
+
+// WebhookDelivery is an attempt to send an event to a subscription.
+type WebhookDelivery struct {
+	Id             string           `json:"id"`
+	SubscriptionId string           `json:"subscription_id"`
+	EventId        string           `json:"event_id"`
+	EventType      WebhookEventType `json:"event_type"`
+	FileId         string           `json:"file_id"`
+	// Attempt counts from 1, attempts after the first one are retries.
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Succeeded   bool      `json:"succeeded"`
+	Duration    string    `json:"duration"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	// NextAttemptAt is when a failed delivery is retried, nil when it is not.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+}
@@ -0,0 +1,154 @@
+package grpctransport
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/service"
+	"github.com/bruhabruh/file-hosting/pkg/filehosting"
+	"github.com/bruhabruh/file-hosting/pkg/grpcinterceptors"
+	"github.com/bruhabruh/file-hosting/pkg/sloggrpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// methodScopes are the scopes required by methods, other methods accept any
+// valid API key.
+var methodScopes = map[string]domain.Scope{
+	filehosting.FileHosting_GetFiles_FullMethodName:                domain.ScopeList,
+	filehosting.FileHosting_RenameFile_FullMethodName:              domain.ScopeRename,
+	filehosting.FileHosting_UpdateExpiry_FullMethodName:            domain.ScopeUploadNamed,
+	filehosting.FileHosting_UpdateFileMetadata_FullMethodName:      domain.ScopeUploadNamed,
+	filehosting.FileHosting_DeleteFile_FullMethodName:              domain.ScopeDelete,
+	filehosting.FileHosting_GetTrashedFiles_FullMethodName:         domain.ScopeList,
+	filehosting.FileHosting_RestoreTrashedFile_FullMethodName:      domain.ScopeDelete,
+	filehosting.FileHosting_GetFileVersions_FullMethodName:         domain.ScopeList,
+	filehosting.FileHosting_GetFileVersion_FullMethodName:          domain.ScopeList,
+	filehosting.FileHosting_RestoreFileVersion_FullMethodName:      domain.ScopeUploadNamed,
+	filehosting.FileHosting_DeleteFileVersion_FullMethodName:       domain.ScopeDelete,
+	filehosting.FileHosting_CreatePresignedUpload_FullMethodName:   domain.ScopeUploadNamed,
+	filehosting.FileHosting_FinalizePresignedUpload_FullMethodName: domain.ScopeUploadNamed,
+	filehosting.FileHosting_SignFileUrl_FullMethodName:             domain.ScopeReadPrivate,
+	filehosting.FileHosting_GetWebhooks_FullMethodName:             domain.ScopeAdmin,
+	filehosting.FileHosting_CreateWebhook_FullMethodName:           domain.ScopeAdmin,
+	filehosting.FileHosting_DeleteWebhook_FullMethodName:           domain.ScopeAdmin,
+	filehosting.FileHosting_GetWebhookDeliveries_FullMethodName:    domain.ScopeAdmin,
+}
+
+// managedMethods accept the management token of the file instead of an API
+// key, the token is verified by the method.
+var managedMethods = map[string]bool{
+	filehosting.FileHosting_UpdateExpiry_FullMethodName:       true,
+	filehosting.FileHosting_UpdateFileMetadata_FullMethodName: true,
+	filehosting.FileHosting_DeleteFile_FullMethodName:         true,
+}
+
+// metadataManagementToken is the metadata carrying a management token.
+const metadataManagementToken = "x-management-token"
+
+// metadataFilePassword is the metadata carrying the password of a file.
+const metadataFilePassword = "x-file-password"
+
+// authorize accepts calls with an API key or a JWT having the scope of the
+// method.
+// The key is added to the context and its label to the request log.
+func authorize(authenticator service.Authenticator) grpcinterceptors.AuthorizeFunc {
+	return func(ctx context.Context, fullMethod string, authorization string) (context.Context, error) {
+		if managedMethods[fullMethod] && len(managementToken(ctx)) > 0 {
+			return ctx, nil
+		}
+
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok {
+			return ctx, apperr.ToGRPCError(apperr.ErrUnauthorized)
+		}
+
+		key, err := authenticator.Authenticate(ctx, token, peerHost(ctx))
+		if err != nil {
+			return ctx, apperr.ToGRPCError(err)
+		}
+
+		sloggrpc.AddCustomAttributes(ctx, slog.String("api_key", key.Label))
+		ctx = service.ContextWithAPIKey(ctx, key)
+
+		if scope, ok := methodScopes[fullMethod]; ok {
+			if err := service.RequireScope(ctx, scope); err != nil {
+				return ctx, apperr.ToGRPCError(err)
+			}
+		}
+
+		return ctx, nil
+	}
+}
+
+// requireExpiryScope rejects permanent expiry unless the key of the call has
+// domain.ScopeUploadPermanent.
+func requireExpiryScope(ctx context.Context, expiry domain.FileExpiry) error {
+	if !expiry.IsPermanent() {
+		return nil
+	}
+	return service.RequireScope(ctx, domain.ScopeUploadPermanent)
+}
+
+// authorizeManagement verifies the management token of file when the call
+// was not authorized by a key.
+func (s *fileHostingServer) authorizeManagement(ctx context.Context, file string) error {
+	if service.APIKeyFromContext(ctx) != nil {
+		return nil
+	}
+	return s.fileHostingService.AuthorizeManagementToken(ctx, file, managementToken(ctx))
+}
+
+func managementToken(ctx context.Context) string {
+	return incomingMetadata(ctx, metadataManagementToken)
+}
+
+// authorizePrivate accepts reading a private file only with
+// domain.ScopeReadPrivate.
+func authorizePrivate(ctx context.Context, file *domain.FileMetadata) error {
+	if !file.Private {
+		return nil
+	}
+	return service.RequireScope(ctx, domain.ScopeReadPrivate)
+}
+
+// authorizePassword verifies the x-file-password metadata when the file has
+// a password. Failed attempts are limited per file and peer address.
+func (s *fileHostingServer) authorizePassword(ctx context.Context, file *domain.FileMetadata) error {
+	if !file.IsPasswordProtected() {
+		return nil
+	}
+
+	password := incomingMetadata(ctx, metadataFilePassword)
+	if len(password) == 0 {
+		return apperr.ErrUnauthorized
+	}
+
+	return s.filePasswordService.VerifyPassword(ctx, file, password, peerHost(ctx))
+}
+
+func incomingMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// peerHost is the address of the caller without its port.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -1,8 +1,10 @@
 package grpctransport
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/bruhabruh/file-hosting/internal/app/apperr"
@@ -14,27 +16,48 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// streamChunkSize is the size of content chunks sent by GetFileStream.
+const streamChunkSize = 64 * 1024
+
 type fileHostingServer struct {
 	filehosting.UnimplementedFileHostingServer
 
-	config             *config.Config
-	logger             *logging.Logger
-	fileHostingService service.FileHostingService
+	config              *config.Config
+	logger              *logging.Logger
+	fileHostingService  service.FileHostingService
+	presignService      service.PresignService
+	signedURLService    service.SignedURLService
+	filePasswordService service.FilePasswordService
+	webhookService      service.WebhookService
 }
 
-func newFileHostingServer(config *config.Config, logger *logging.Logger, fileHostingService service.FileHostingService) *fileHostingServer {
+func newFileHostingServer(config *config.Config, logger *logging.Logger, fileHostingService service.FileHostingService, presignService service.PresignService, signedURLService service.SignedURLService, filePasswordService service.FilePasswordService, webhookService service.WebhookService) *fileHostingServer {
 	return &fileHostingServer{
-		config:             config,
-		logger:             logger,
-		fileHostingService: fileHostingService,
+		config:              config,
+		logger:              logger,
+		fileHostingService:  fileHostingService,
+		presignService:      presignService,
+		signedURLService:    signedURLService,
+		filePasswordService: filePasswordService,
+		webhookService:      webhookService,
 	}
 }
 
 func (s *fileHostingServer) GetFile(ctx context.Context, req *filehosting.FileId) (*filehosting.File, error) {
-	file, err := s.fileHostingService.GetFile(ctx, req.GetId())
+	if err := s.authorizeFilePassword(ctx, req.GetId()); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	file, err := s.fileHostingService.DownloadFile(ctx, req.GetId())
 	if err != nil {
 		return nil, apperr.ToGRPCError(err)
 	}
+	defer file.Content.Close()
+
+	content, err := io.ReadAll(file.Content)
+	if err != nil {
+		return nil, apperr.ToGRPCError(apperr.ErrInternalServerError.WithMessage("Fail read file"))
+	}
 
 	grpcMetadata := make(map[string]*filehosting.MetadataValue)
 	for key, values := range file.Metadata.Meta {
@@ -43,7 +66,7 @@ func (s *fileHostingServer) GetFile(ctx context.Context, req *filehosting.FileId
 
 	return &filehosting.File{
 		Filename:    file.Metadata.Name,
-		Content:     file.Content,
+		Content:     content,
 		ContentType: file.Metadata.MimeType,
 		Metadata:    grpcMetadata,
 	}, nil
@@ -55,27 +78,27 @@ func (s *fileHostingServer) GetFileMetadata(ctx context.Context, req *filehostin
 		return nil, apperr.ToGRPCError(err)
 	}
 
-	grpcMetadata := make(map[string]*filehosting.MetadataValue)
-	for key, values := range metadata.Meta {
-		grpcMetadata[key] = &filehosting.MetadataValue{Values: values}
+	if err := authorizePrivate(ctx, metadata); err != nil {
+		return nil, apperr.ToGRPCError(err)
 	}
-
-	var backupName *string
-	backupName = nil
-	if len(metadata.BackupName) > 0 {
-		backupName = &metadata.BackupName
+	if err := s.authorizePassword(ctx, metadata); err != nil {
+		return nil, apperr.ToGRPCError(err)
 	}
 
-	return &filehosting.FileMetadata{
-		Id:         metadata.Id,
-		Name:       metadata.Name,
-		MimeType:   metadata.MimeType,
-		Sha1:       metadata.Sha1,
-		CreatedAt:  metadata.CreatedAt.UTC().Format(time.RFC3339),
-		ExpiredAt:  metadata.ExpiredAt.UTC().Format(time.RFC3339),
-		BackupName: backupName,
-		Meta:       grpcMetadata,
-	}, nil
+	return toGRPCFileMetadata(metadata), nil
+}
+
+// authorizeFilePassword checks the scope for private files and the password
+// before a download, so a rejected call does not count a download.
+func (s *fileHostingServer) authorizeFilePassword(ctx context.Context, id string) error {
+	metadata, err := s.fileHostingService.GetFileMetadata(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizePrivate(ctx, metadata); err != nil {
+		return err
+	}
+	return s.authorizePassword(ctx, metadata)
 }
 
 func (s *fileHostingServer) UploadFile(ctx context.Context, req *filehosting.UploadFileRequest) (*filehosting.UploadFileResponse, error) {
@@ -88,57 +111,178 @@ func (s *fileHostingServer) UploadFile(ctx context.Context, req *filehosting.Upl
 		domainMetadata[key] = data
 	}
 
+	passwordHash, err := hashPassword(ctx, req.GetPassword())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
 	metadata := &domain.FileMetadata{
-		Name:     req.GetFilename(),
-		MimeType: req.GetContentType(),
-		Meta:     domainMetadata,
+		Name:         req.GetFilename(),
+		MimeType:     req.GetContentType(),
+		Meta:         domainMetadata,
+		MaxDownloads: req.GetMaxDownloads(),
+		Owner:        service.OwnerFromContext(ctx),
+		Private:      req.GetPrivate(),
+		PasswordHash: passwordHash,
 	}
 
-	fileName, _, err := s.fileHostingService.UploadFile(ctx, req.GetContent(), metadata, req.GetDuration())
+	expiry, err := toFileExpiry(req.GetDuration(), req.GetExpiresAt())
 	if err != nil {
 		return nil, apperr.ToGRPCError(err)
 	}
 
-	return &filehosting.UploadFileResponse{
-		Url: fmt.Sprintf("%s/%s", s.config.Origin(), fileName),
-		Id:  fileName,
-	}, nil
-}
+	if err := requireExpiryScope(ctx, expiry); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
 
-func (s *fileHostingServer) GetFiles(ctx context.Context, req *emptypb.Empty) (*filehosting.Files, error) {
-	files, err := s.fileHostingService.GetFiles(ctx)
+	content := req.GetContent()
+	fileName, fileMetadata, err := s.uploadFile(ctx, bytes.NewReader(content), int64(len(content)), metadata, expiry, req.GetGenerativeName())
 	if err != nil {
 		return nil, apperr.ToGRPCError(err)
 	}
 
-	metadata := make([]*filehosting.FileMetadata, len(files))
-	for i, file := range files {
-		meta := make(map[string]*filehosting.MetadataValue)
+	return toGRPCUploadFileResponse(s.config.Origin(), fileName, fileMetadata), nil
+}
 
-		for key, values := range file.Meta {
-			meta[key] = &filehosting.MetadataValue{Values: values}
-		}
+func (s *fileHostingServer) UploadFileStream(stream filehosting.FileHosting_UploadFileStreamServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return apperr.ToGRPCError(apperr.ErrBadRequest.WithMessage("Fail receive file info"))
+	}
 
-		var backupName *string
-		backupName = nil
-		if len(file.BackupName) > 0 {
-			backupName = &file.BackupName
-		}
+	info := first.GetInfo()
+	if info == nil {
+		return apperr.ToGRPCError(apperr.ErrBadRequest.WithMessage("First message must contain file info"))
+	}
 
-		metadata[i] = &filehosting.FileMetadata{
-			Id:         file.Id,
-			Name:       file.Name,
-			MimeType:   file.MimeType,
-			Sha1:       file.Sha1,
-			CreatedAt:  file.CreatedAt.UTC().Format(time.RFC3339),
-			ExpiredAt:  file.ExpiredAt.UTC().Format(time.RFC3339),
-			BackupName: backupName,
-			Meta:       meta,
+	domainMetadata := make(map[string][]string)
+	for key, metadataValue := range info.GetMetadata() {
+		domainMetadata[key] = append([]string{}, metadataValue.GetValues()...)
+	}
+
+	passwordHash, err := hashPassword(stream.Context(), info.GetPassword())
+	if err != nil {
+		return apperr.ToGRPCError(err)
+	}
+
+	metadata := &domain.FileMetadata{
+		Name:         info.GetFilename(),
+		MimeType:     info.GetContentType(),
+		Meta:         domainMetadata,
+		MaxDownloads: info.GetMaxDownloads(),
+		Owner:        service.OwnerFromContext(stream.Context()),
+		Private:      info.GetPrivate(),
+		PasswordHash: passwordHash,
+	}
+
+	size := int64(-1)
+	if info.Size != nil {
+		size = info.GetSize()
+	}
+
+	expiry, err := toFileExpiry(info.GetDuration(), info.GetExpiresAt())
+	if err != nil {
+		return apperr.ToGRPCError(err)
+	}
+
+	if err := requireExpiryScope(stream.Context(), expiry); err != nil {
+		return apperr.ToGRPCError(err)
+	}
+
+	fileName, fileMetadata, err := s.uploadFile(stream.Context(), &uploadStreamReader{stream: stream}, size, metadata, expiry, info.GetGenerativeName())
+	if err != nil {
+		return apperr.ToGRPCError(err)
+	}
+
+	return stream.SendAndClose(toGRPCUploadFileResponse(s.config.Origin(), fileName, fileMetadata))
+}
+
+// uploadFile stores the file under a generated id or, with
+// domain.ScopeUploadNamed, under its name.
+func (s *fileHostingServer) uploadFile(ctx context.Context, content io.Reader, size int64, metadata *domain.FileMetadata, expiry domain.FileExpiry, generativeName bool) (string, *domain.FileMetadata, error) {
+	if generativeName {
+		return s.fileHostingService.UploadFileWithGenerativeName(ctx, content, size, metadata, expiry)
+	}
+
+	if err := service.RequireScope(ctx, domain.ScopeUploadNamed); err != nil {
+		return "", nil, err
+	}
+	return s.fileHostingService.UploadFile(ctx, content, size, metadata, expiry)
+}
+
+// hashPassword hashes the password of an upload, empty means no password.
+func hashPassword(ctx context.Context, password string) (string, error) {
+	if len(password) == 0 {
+		return "", nil
+	}
+
+	hash, err := service.HashSecret(password)
+	if err != nil {
+		logging.L(ctx).Error("Fail hash password", logging.ErrAttr(err))
+		return "", apperr.ErrInternalServerError.WithMessage("Fail hash password")
+	}
+
+	return hash, nil
+}
+
+func (s *fileHostingServer) GetFileStream(req *filehosting.FileId, stream filehosting.FileHosting_GetFileStreamServer) error {
+	if err := s.authorizeFilePassword(stream.Context(), req.GetId()); err != nil {
+		return apperr.ToGRPCError(err)
+	}
+
+	file, err := s.fileHostingService.DownloadFile(stream.Context(), req.GetId())
+	if err != nil {
+		return apperr.ToGRPCError(err)
+	}
+	defer file.Content.Close()
+
+	err = stream.Send(&filehosting.FileChunk{
+		Data: &filehosting.FileChunk_Metadata{Metadata: toGRPCFileMetadata(file.Metadata)},
+	})
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, err := file.Content.Read(buf)
+		if n > 0 {
+			sendErr := stream.Send(&filehosting.FileChunk{
+				Data: &filehosting.FileChunk_Chunk{Chunk: buf[:n]},
+			})
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return apperr.ToGRPCError(apperr.ErrInternalServerError.WithMessage("Fail read file"))
 		}
 	}
+}
+
+func (s *fileHostingServer) GetFiles(ctx context.Context, req *filehosting.GetFilesRequest) (*filehosting.Files, error) {
+	query, err := toFileQuery(req)
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	page, err := s.fileHostingService.GetFiles(ctx, query)
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	metadata := make([]*filehosting.FileMetadata, len(page.Files))
+	for i, file := range page.Files {
+		metadata[i] = toGRPCFileMetadata(file)
+	}
 
 	return &filehosting.Files{
-		Metadata: metadata,
+		Metadata:   metadata,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
 	}, nil
 }
 
@@ -150,10 +294,482 @@ func (s *fileHostingServer) RenameFile(ctx context.Context, req *filehosting.Ren
 	return &emptypb.Empty{}, nil
 }
 
+func (s *fileHostingServer) UpdateExpiry(ctx context.Context, req *filehosting.UpdateExpiryRequest) (*filehosting.FileMetadata, error) {
+	expiry, err := toFileExpiry(req.GetDuration(), req.GetExpiresAt())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	if err := s.authorizeManagement(ctx, req.GetId()); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	if err := requireExpiryScope(ctx, expiry); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	metadata, err := s.fileHostingService.UpdateExpiry(ctx, req.GetId(), expiry)
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return toGRPCFileMetadata(metadata), nil
+}
+
+func (s *fileHostingServer) UpdateFileMetadata(ctx context.Context, req *filehosting.UpdateFileMetadataRequest) (*filehosting.FileMetadata, error) {
+	if err := s.authorizeManagement(ctx, req.GetId()); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	meta := make(map[string][]string)
+	for key, metadataValue := range req.GetMetadata() {
+		meta[key] = append([]string{}, metadataValue.GetValues()...)
+	}
+
+	metadata, err := s.fileHostingService.UpdateMeta(ctx, req.GetId(), meta)
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return toGRPCFileMetadata(metadata), nil
+}
+
+func (s *fileHostingServer) SignFileUrl(ctx context.Context, req *filehosting.SignFileUrlRequest) (*filehosting.SignedFileUrl, error) {
+	if s.signedURLService == nil {
+		return nil, apperr.ToGRPCError(apperr.ErrNotImplemented.WithMessage("Signed urls are disabled"))
+	}
+
+	signedURL, err := s.signedURLService.Sign(ctx, req.GetId(), req.GetLifetime(), req.GetIp(), req.GetMethod())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return &filehosting.SignedFileUrl{
+		Url:       signedURL.Url,
+		ExpiresAt: signedURL.ExpiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
 func (s *fileHostingServer) DeleteFile(ctx context.Context, req *filehosting.FileId) (*emptypb.Empty, error) {
+	if err := s.authorizeManagement(ctx, req.GetId()); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
 	if err := s.fileHostingService.DeleteFile(ctx, req.GetId()); err != nil {
 		return nil, apperr.ToGRPCError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
+
+func (s *fileHostingServer) CreatePresignedUpload(ctx context.Context, req *filehosting.CreatePresignedUploadRequest) (*filehosting.PresignedUpload, error) {
+	if s.presignService == nil {
+		return nil, apperr.ToGRPCError(apperr.ErrNotImplemented.WithMessage("Presigned urls are disabled"))
+	}
+
+	domainMetadata := make(map[string][]string)
+	for key, metadataValue := range req.GetMetadata() {
+		domainMetadata[key] = append([]string{}, metadataValue.GetValues()...)
+	}
+
+	passwordHash, err := hashPassword(ctx, req.GetPassword())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	metadata := &domain.FileMetadata{
+		Name:         req.GetFilename(),
+		Meta:         domainMetadata,
+		MaxDownloads: req.GetMaxDownloads(),
+		Owner:        service.OwnerFromContext(ctx),
+		Private:      req.GetPrivate(),
+		PasswordHash: passwordHash,
+	}
+
+	expiry, err := toFileExpiry(req.GetDuration(), req.GetExpiresAt())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	if err := requireExpiryScope(ctx, expiry); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	upload, err := s.presignService.CreateUpload(ctx, metadata, expiry)
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return &filehosting.PresignedUpload{
+		Id:           upload.Id,
+		Url:          upload.Url,
+		UrlExpiredAt: upload.UrlExpiredAt.UTC().Format(time.RFC3339),
+		ExpiredAt:    upload.ExpiredAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *fileHostingServer) FinalizePresignedUpload(ctx context.Context, req *filehosting.PresignedUploadId) (*filehosting.UploadFileResponse, error) {
+	if s.presignService == nil {
+		return nil, apperr.ToGRPCError(apperr.ErrNotImplemented.WithMessage("Presigned urls are disabled"))
+	}
+
+	upload, err := s.presignService.FinalizeUpload(ctx, req.GetId())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return &filehosting.UploadFileResponse{
+		Url: fmt.Sprintf("%s/%s", s.config.Origin(), upload.FileId),
+		Id:  upload.FileId,
+	}, nil
+}
+
+func (s *fileHostingServer) GetTrashedFiles(ctx context.Context, req *emptypb.Empty) (*filehosting.TrashedFiles, error) {
+	files, err := s.fileHostingService.GetTrashedFiles(ctx)
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	grpcFiles := make([]*filehosting.TrashedFile, len(files))
+	for i, file := range files {
+		grpcFiles[i] = &filehosting.TrashedFile{
+			Id:        file.Id,
+			FileId:    file.FileId,
+			DeletedAt: file.DeletedAt.UTC().Format(time.RFC3339),
+			PurgeAt:   file.PurgeAt.UTC().Format(time.RFC3339),
+			Metadata:  toGRPCFileMetadata(file.Metadata),
+		}
+	}
+
+	return &filehosting.TrashedFiles{
+		Files: grpcFiles,
+	}, nil
+}
+
+func (s *fileHostingServer) RestoreTrashedFile(ctx context.Context, req *filehosting.TrashedFileId) (*filehosting.FileMetadata, error) {
+	metadata, err := s.fileHostingService.RestoreTrashedFile(ctx, req.GetId())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return toGRPCFileMetadata(metadata), nil
+}
+
+func (s *fileHostingServer) GetFileVersions(ctx context.Context, req *filehosting.FileId) (*filehosting.FileVersions, error) {
+	versions, err := s.fileHostingService.GetFileVersions(ctx, req.GetId())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	grpcVersions := make([]*filehosting.FileVersion, len(versions))
+	for i, version := range versions {
+		grpcVersions[i] = &filehosting.FileVersion{
+			Version:  version.Version,
+			Current:  version.Current,
+			Metadata: toGRPCFileMetadata(version.Metadata),
+		}
+	}
+
+	return &filehosting.FileVersions{
+		Versions: grpcVersions,
+	}, nil
+}
+
+func (s *fileHostingServer) GetFileVersion(ctx context.Context, req *filehosting.FileVersionId) (*filehosting.File, error) {
+	file, err := s.fileHostingService.GetFileVersion(ctx, req.GetId(), req.GetVersion())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+	defer file.Content.Close()
+
+	content, err := io.ReadAll(file.Content)
+	if err != nil {
+		return nil, apperr.ToGRPCError(apperr.ErrInternalServerError.WithMessage("Fail read file"))
+	}
+
+	grpcMetadata := make(map[string]*filehosting.MetadataValue)
+	for key, values := range file.Metadata.Meta {
+		grpcMetadata[key] = &filehosting.MetadataValue{Values: values}
+	}
+
+	return &filehosting.File{
+		Filename:    file.Metadata.Name,
+		Content:     content,
+		ContentType: file.Metadata.MimeType,
+		Metadata:    grpcMetadata,
+	}, nil
+}
+
+func (s *fileHostingServer) RestoreFileVersion(ctx context.Context, req *filehosting.FileVersionId) (*filehosting.FileMetadata, error) {
+	metadata, err := s.fileHostingService.RestoreFileVersion(ctx, req.GetId(), req.GetVersion())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return toGRPCFileMetadata(metadata), nil
+}
+
+func (s *fileHostingServer) DeleteFileVersion(ctx context.Context, req *filehosting.FileVersionId) (*emptypb.Empty, error) {
+	if err := s.fileHostingService.DeleteFileVersion(ctx, req.GetId(), req.GetVersion()); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *fileHostingServer) GetWebhooks(ctx context.Context, req *emptypb.Empty) (*filehosting.Webhooks, error) {
+	if s.webhookService == nil {
+		return nil, apperr.ToGRPCError(apperr.ErrNotImplemented.WithMessage("Webhooks are disabled"))
+	}
+
+	subscriptions, err := s.webhookService.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	webhooks := make([]*filehosting.Webhook, len(subscriptions))
+	for i, subscription := range subscriptions {
+		webhooks[i] = toGRPCWebhook(subscription)
+	}
+
+	return &filehosting.Webhooks{
+		Webhooks: webhooks,
+	}, nil
+}
+
+func (s *fileHostingServer) CreateWebhook(ctx context.Context, req *filehosting.CreateWebhookRequest) (*filehosting.Webhook, error) {
+	if s.webhookService == nil {
+		return nil, apperr.ToGRPCError(apperr.ErrNotImplemented.WithMessage("Webhooks are disabled"))
+	}
+
+	events := make([]domain.WebhookEventType, len(req.GetEvents()))
+	for i, event := range req.GetEvents() {
+		events[i] = domain.WebhookEventType(event)
+	}
+
+	subscription, err := s.webhookService.CreateSubscription(ctx, &domain.WebhookSubscription{
+		Url:    req.GetUrl(),
+		Secret: req.GetSecret(),
+		Events: events,
+	})
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return toGRPCWebhook(subscription), nil
+}
+
+func (s *fileHostingServer) DeleteWebhook(ctx context.Context, req *filehosting.WebhookId) (*emptypb.Empty, error) {
+	if s.webhookService == nil {
+		return nil, apperr.ToGRPCError(apperr.ErrNotImplemented.WithMessage("Webhooks are disabled"))
+	}
+
+	if err := s.webhookService.DeleteSubscription(ctx, req.GetId()); err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *fileHostingServer) GetWebhookDeliveries(ctx context.Context, req *filehosting.WebhookId) (*filehosting.WebhookDeliveries, error) {
+	if s.webhookService == nil {
+		return nil, apperr.ToGRPCError(apperr.ErrNotImplemented.WithMessage("Webhooks are disabled"))
+	}
+
+	deliveries, err := s.webhookService.GetDeliveries(ctx, req.GetId())
+	if err != nil {
+		return nil, apperr.ToGRPCError(err)
+	}
+
+	grpcDeliveries := make([]*filehosting.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		grpcDelivery := &filehosting.WebhookDelivery{
+			Id:          delivery.Id,
+			WebhookId:   delivery.SubscriptionId,
+			EventId:     delivery.EventId,
+			EventType:   string(delivery.EventType),
+			FileId:      delivery.FileId,
+			Attempt:     int32(delivery.Attempt),
+			StatusCode:  int32(delivery.StatusCode),
+			Succeeded:   delivery.Succeeded,
+			Duration:    delivery.Duration,
+			AttemptedAt: delivery.AttemptedAt.UTC().Format(time.RFC3339),
+		}
+		if len(delivery.Error) > 0 {
+			grpcDelivery.Error = &delivery.Error
+		}
+		if delivery.NextAttemptAt != nil {
+			nextAttemptAt := delivery.NextAttemptAt.UTC().Format(time.RFC3339)
+			grpcDelivery.NextAttemptAt = &nextAttemptAt
+		}
+		grpcDeliveries[i] = grpcDelivery
+	}
+
+	return &filehosting.WebhookDeliveries{
+		Deliveries: grpcDeliveries,
+	}, nil
+}
+
+func toGRPCWebhook(subscription *domain.WebhookSubscription) *filehosting.Webhook {
+	events := make([]string, len(subscription.Events))
+	for i, event := range subscription.Events {
+		events[i] = string(event)
+	}
+
+	var secret *string
+	if len(subscription.Secret) > 0 {
+		secret = &subscription.Secret
+	}
+
+	return &filehosting.Webhook{
+		Id:        subscription.Id,
+		Url:       subscription.Url,
+		Secret:    secret,
+		Events:    events,
+		Static:    subscription.Static,
+		CreatedAt: subscription.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toFileExpiry(duration string, rawExpiresAt string) (domain.FileExpiry, error) {
+	expiry := domain.FileExpiry{Duration: duration}
+	if len(rawExpiresAt) > 0 {
+		expiresAt, err := time.Parse(time.RFC3339, rawExpiresAt)
+		if err != nil {
+			return domain.FileExpiry{}, apperr.ErrBadRequest.WithMessage("Invalid expiresAt, expected RFC 3339 time")
+		}
+		expiry.ExpiresAt = expiresAt
+	}
+	return expiry, nil
+}
+
+func toFileQuery(req *filehosting.GetFilesRequest) (*domain.FileQuery, error) {
+	query := &domain.FileQuery{
+		NamePrefix: req.GetNamePrefix(),
+		MimeType:   req.GetMimeType(),
+		HasBackup:  req.HasBackup,
+		Owner:      req.GetOwner(),
+		Meta:       req.GetMeta(),
+		Descending: req.GetDescending(),
+		Limit:      int(req.GetLimit()),
+		Cursor:     req.GetCursor(),
+	}
+
+	switch req.GetSort() {
+	case filehosting.FileSort_FILE_SORT_CREATED_AT:
+		query.Sort = domain.FileSortCreatedAt
+	case filehosting.FileSort_FILE_SORT_NAME:
+		query.Sort = domain.FileSortName
+	case filehosting.FileSort_FILE_SORT_SIZE:
+		query.Sort = domain.FileSortSize
+	default:
+		return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Unknown sort %s", req.GetSort()))
+	}
+
+	times := []struct {
+		name  string
+		raw   *string
+		value *time.Time
+	}{
+		{"createdAfter", req.CreatedAfter, &query.CreatedAfter},
+		{"createdBefore", req.CreatedBefore, &query.CreatedBefore},
+		{"expiredAfter", req.ExpiredAfter, &query.ExpiredAfter},
+		{"expiredBefore", req.ExpiredBefore, &query.ExpiredBefore},
+	}
+	for _, t := range times {
+		if t.raw == nil {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, *t.raw)
+		if err != nil {
+			return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Invalid %s, expected RFC 3339 time", t.name))
+		}
+		*t.value = parsed
+	}
+
+	return query, nil
+}
+
+func toGRPCUploadFileResponse(origin string, fileName string, metadata *domain.FileMetadata) *filehosting.UploadFileResponse {
+	var managementToken *string
+	if len(metadata.ManagementToken) > 0 {
+		managementToken = &metadata.ManagementToken
+	}
+
+	return &filehosting.UploadFileResponse{
+		Url:             fmt.Sprintf("%s/%s", origin, fileName),
+		Id:              fileName,
+		ManagementToken: managementToken,
+	}
+}
+
+func toGRPCFileMetadata(metadata *domain.FileMetadata) *filehosting.FileMetadata {
+	grpcMetadata := make(map[string]*filehosting.MetadataValue)
+	for key, values := range metadata.Meta {
+		grpcMetadata[key] = &filehosting.MetadataValue{Values: values}
+	}
+
+	var backupName *string
+	backupName = nil
+	if len(metadata.BackupName) > 0 {
+		backupName = &metadata.BackupName
+	}
+
+	var maxDownloads *int64
+	if metadata.IsDownloadLimited() {
+		maxDownloads = &metadata.MaxDownloads
+	}
+
+	var owner *string
+	if len(metadata.Owner) > 0 {
+		owner = &metadata.Owner
+	}
+
+	var private *bool
+	if metadata.Private {
+		private = &metadata.Private
+	}
+
+	var passwordProtected *bool
+	if metadata.IsPasswordProtected() {
+		protected := true
+		passwordProtected = &protected
+	}
+
+	return &filehosting.FileMetadata{
+		Id:                 metadata.Id,
+		Name:               metadata.Name,
+		MimeType:           metadata.MimeType,
+		Sha1:               metadata.Sha1,
+		Size:               metadata.Size,
+		CreatedAt:          metadata.CreatedAt.UTC().Format(time.RFC3339),
+		ExpiredAt:          metadata.ExpiredAt.UTC().Format(time.RFC3339),
+		BackupName:         backupName,
+		Meta:               grpcMetadata,
+		MaxDownloads:       maxDownloads,
+		RemainingDownloads: metadata.RemainingDownloads,
+		Owner:              owner,
+		Private:            private,
+		PasswordProtected:  passwordProtected,
+	}
+}
+
+// uploadStreamReader exposes the chunks of an UploadFileStream call as io.Reader.
+type uploadStreamReader struct {
+	stream filehosting.FileHosting_UploadFileStreamServer
+	chunk  []byte
+}
+
+func (r *uploadStreamReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = msg.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
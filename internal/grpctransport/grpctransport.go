@@ -22,11 +22,12 @@ type GRPCTransport struct {
 	config             *config.Config
 	logger             *logging.Logger
 	fileHostingService service.FileHostingService
+	authenticator      service.Authenticator
 	grpc               *grpc.Server
 	notify             chan error
 }
 
-func New(config *config.Config, logger *logging.Logger, registry *prometheus.Registry, fileHostingService service.FileHostingService) *GRPCTransport {
+func New(config *config.Config, logger *logging.Logger, registry *prometheus.Registry, fileHostingService service.FileHostingService, presignService service.PresignService, signedURLService service.SignedURLService, filePasswordService service.FilePasswordService, webhookService service.WebhookService, authenticator service.Authenticator) *GRPCTransport {
 	s := grpcprometheus.NewServerMetrics(
 		grpcprometheus.WithServerHandlingTimeHistogram(),
 		grpcprometheus.WithServerCounterOptions(
@@ -40,6 +41,7 @@ func New(config *config.Config, logger *logging.Logger, registry *prometheus.Reg
 		config:             config,
 		logger:             logger,
 		fileHostingService: fileHostingService,
+		authenticator:      authenticator,
 		grpc: grpc.NewServer(
 			grpc.MaxRecvMsgSize(11*1024*1024),
 			grpc.UnaryInterceptor(s.UnaryServerInterceptor()),
@@ -58,13 +60,16 @@ func New(config *config.Config, logger *logging.Logger, registry *prometheus.Reg
 						WithRequestBody:  false,
 						Filters:          []sloggrpc.Filter{},
 					}),
-				grpcinterceptors.UnaryServerAuthorizationInterceptor(config.ApiKey()),
+				grpcinterceptors.UnaryServerAuthorizationInterceptor(authorize(authenticator)),
+			),
+			grpc.ChainStreamInterceptor(
+				grpcinterceptors.StreamServerAuthorizationInterceptor(authorize(authenticator)),
 			),
 		),
 		notify: make(chan error, 1),
 	}
 
-	filehosting.RegisterFileHostingServer(transport.grpc, newFileHostingServer(config, logger, fileHostingService))
+	filehosting.RegisterFileHostingServer(transport.grpc, newFileHostingServer(config, logger, fileHostingService, presignService, signedURLService, filePasswordService, webhookService))
 
 	reflection.Register(transport.grpc)
 
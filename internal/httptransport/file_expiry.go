@@ -0,0 +1,22 @@
+package httptransport
+
+import (
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// parseFileExpiry reads a duration and an RFC 3339 expires_at time, values
+// are validated by the service.
+func parseFileExpiry(rawDuration string, rawExpiresAt string) (domain.FileExpiry, error) {
+	expiry := domain.FileExpiry{Duration: rawDuration}
+	if len(rawExpiresAt) > 0 {
+		expiresAt, err := time.Parse(time.RFC3339, rawExpiresAt)
+		if err != nil {
+			return domain.FileExpiry{}, apperr.ErrBadRequest.WithMessage("Invalid expires_at, expected RFC 3339 time")
+		}
+		expiry.ExpiresAt = expiresAt
+	}
+	return expiry, nil
+}
@@ -0,0 +1,40 @@
+package httptransport
+
+import (
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+)
+
+type fileExpiryUpdate struct {
+	Duration  string `json:"duration"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+func (ht *HttpTransport) fileExpiryRoute() {
+	ht.fiber.Patch("/file/:file/expiry", ht.fileAuthorizationMiddleware(domain.ScopeUploadNamed), func(c *fiber.Ctx) error {
+		rawBody := c.BodyRaw()
+
+		var fileExpiryUpdate fileExpiryUpdate
+		if err := json.Unmarshal(rawBody, &fileExpiryUpdate); err != nil {
+			return apperr.ErrBadRequest.WithMessage("invalid json")
+		}
+
+		expiry, err := parseFileExpiry(fileExpiryUpdate.Duration, fileExpiryUpdate.ExpiresAt)
+		if err != nil {
+			return err
+		}
+
+		if err := requireExpiryScope(c, expiry); err != nil {
+			return err
+		}
+
+		metadata, err := ht.fileHostingService.UpdateExpiry(c.UserContext(), c.Params("file"), expiry)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(metadata.View())
+	})
+}
@@ -1,15 +1,47 @@
 package httptransport
 
 import (
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
 )
 
+type fileMetaUpdate struct {
+	Meta map[string][]string `json:"meta"`
+}
+
 func (ht *HttpTransport) fileMetadataRoute() {
 	ht.fiber.Get("/file/:file/metadata", func(c *fiber.Ctx) error {
 		metadata, err := ht.fileHostingService.GetFileMetadata(c.UserContext(), c.Params("file"))
 		if err != nil {
 			return err
 		}
-		return c.JSON(metadata)
+		if err := ht.authorizePrivateFile(c, metadata); err != nil {
+			return err
+		}
+		if ok, err := ht.authorizePassword(c, metadata); !ok {
+			if err != nil {
+				return err
+			}
+			return apperr.ErrUnauthorized
+		}
+		return c.JSON(metadata.View())
+	})
+
+	ht.fiber.Patch("/file/:file/metadata", ht.fileAuthorizationMiddleware(domain.ScopeUploadNamed), func(c *fiber.Ctx) error {
+		rawBody := c.BodyRaw()
+
+		var fileMetaUpdate fileMetaUpdate
+		if err := json.Unmarshal(rawBody, &fileMetaUpdate); err != nil {
+			return apperr.ErrBadRequest.WithMessage("invalid json")
+		}
+
+		metadata, err := ht.fileHostingService.UpdateMeta(c.UserContext(), c.Params("file"), fileMetaUpdate.Meta)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(metadata.View())
 	})
 }
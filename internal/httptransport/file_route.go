@@ -1,10 +1,22 @@
 package httptransport
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
 func (ht *HttpTransport) fileRoute() {
@@ -14,6 +26,18 @@ func (ht *HttpTransport) fileRoute() {
 			return err
 		}
 
+		if err := ht.authorizePrivateFile(c, metadata); err != nil {
+			return err
+		}
+
+		ok, err := ht.authorizePassword(c, metadata)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ht.sendPasswordForm(c, metadata, fiber.StatusUnauthorized, "")
+		}
+
 		etag := `"` + metadata.Sha1 + `"`
 		if inm := c.Get(fiber.HeaderIfNoneMatch); inm != "" {
 			if inm == "*" || strings.Contains(inm, etag) {
@@ -21,25 +45,175 @@ func (ht *HttpTransport) fileRoute() {
 			}
 		}
 
-		file, err := ht.fileHostingService.GetFile(c.UserContext(), c.Params("file"))
-		if err != nil {
-			return err
+		// Content is downloaded directly from the storage, which serves
+		// ranges itself. Downloads of limited files must pass through to be
+		// counted.
+		if ht.presignService != nil && !metadata.IsDownloadLimited() {
+			url, err := ht.presignService.GetFileURL(c.UserContext(), metadata)
+			if err != nil {
+				return err
+			}
+			c.Response().Header.Set(fiber.HeaderCacheControl, "no-store")
+			return c.Redirect(url, fiber.StatusTemporaryRedirect)
+		}
+
+		// Ranges are served only for files with a known size, metadata
+		// written before sizes were tracked is always sent as a whole.
+		// Files with max downloads are sent as a whole too, ranges would read
+		// them without counting a download.
+		var ranges []httpRange
+		if metadata.Size > 0 && !metadata.IsDownloadLimited() {
+			c.Response().Header.Set(fiber.HeaderAcceptRanges, "bytes")
+
+			rangeHeader := c.Get(fiber.HeaderRange)
+			if rangeHeader != "" && ifRangeMatches(c.Get(fiber.HeaderIfRange), etag, metadata) {
+				ranges, err = parseRange(rangeHeader, metadata.Size)
+				if err != nil {
+					c.Response().Header.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", metadata.Size))
+					return apperr.ErrRequestedRangeNotSatisfiable
+				}
+				if sumRangesSize(ranges) > metadata.Size {
+					// Overlapping ranges cost more than the whole file
+					ranges = nil
+				}
+			}
 		}
 
 		c.Response().Header.Set(fiber.HeaderETag, etag)
-		c.Response().Header.Set(
-			fiber.HeaderCacheControl,
-			"public, max-age=3600",
-		)
-		c.Response().Header.Set(fiber.HeaderContentType, file.Metadata.MimeType)
-		c.Response().Header.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"%s\"", file.Metadata.Name))
-		for key, value := range file.Metadata.Meta {
+		if metadata.IsDownloadLimited() || metadata.Private || metadata.IsPasswordProtected() {
+			// Cached copies would be downloads which are not counted or
+			// readable without authorization
+			c.Response().Header.Set(fiber.HeaderCacheControl, "no-store")
+		} else {
+			c.Response().Header.Set(
+				fiber.HeaderCacheControl,
+				"public, max-age=3600",
+			)
+		}
+		c.Response().Header.Set(fiber.HeaderLastModified, metadata.CreatedAt.UTC().Format(http.TimeFormat))
+		c.Response().Header.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"%s\"", metadata.Name))
+		for key, value := range metadata.Meta {
 			header := fmt.Sprintf("X-Meta-%s", key)
 			for i := range value {
 				c.Response().Header.Add(header, value[i])
 			}
 		}
 
-		return c.Send(file.Content)
+		switch len(ranges) {
+		case 0:
+			return ht.sendFile(c, metadata)
+		case 1:
+			return ht.sendFileRange(c, metadata, ranges[0])
+		default:
+			return ht.sendFileRanges(c, metadata, ranges)
+		}
+	})
+}
+
+// sendFile sends the whole file, which counts as a download of a file with
+// max downloads. HEAD requests are not counted.
+func (ht *HttpTransport) sendFile(c *fiber.Ctx, metadata *domain.FileMetadata) error {
+	var (
+		file *domain.File
+		err  error
+	)
+	if c.Method() == fiber.MethodHead {
+		file, err = ht.fileHostingService.GetFile(c.UserContext(), c.Params("file"))
+	} else {
+		file, err = ht.fileHostingService.DownloadFile(c.UserContext(), c.Params("file"))
+	}
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header.Set(fiber.HeaderContentType, file.Metadata.MimeType)
+	if file.Metadata.RemainingDownloads != nil {
+		c.Response().Header.Set("X-Remaining-Downloads", strconv.FormatInt(*file.Metadata.RemainingDownloads, 10))
+	}
+
+	size := file.Metadata.Size
+	if size <= 0 {
+		// Metadata written before sizes were tracked, let fasthttp stream it chunked
+		size = -1
+	}
+
+	c.Response().SetBodyStream(file.Content, int(size))
+
+	return nil
+}
+
+func (ht *HttpTransport) sendFileRange(c *fiber.Ctx, metadata *domain.FileMetadata, r httpRange) error {
+	content, err := ht.fileHostingService.GetFileRange(c.UserContext(), c.Params("file"), r.start, r.length)
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusPartialContent)
+	c.Response().Header.Set(fiber.HeaderContentType, metadata.MimeType)
+	c.Response().Header.Set(fiber.HeaderContentRange, r.contentRange(metadata.Size))
+	c.Response().SetBodyStream(content, int(r.length))
+
+	return nil
+}
+
+func (ht *HttpTransport) sendFileRanges(c *fiber.Ctx, metadata *domain.FileMetadata, ranges []httpRange) error {
+	// The handler context is released before the body is written, so
+	// everything the writer needs is captured here.
+	ctx := c.UserContext()
+	file := utils.CopyString(c.Params("file"))
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	c.Status(fiber.StatusPartialContent)
+	c.Response().Header.Set(fiber.HeaderContentType, "multipart/byteranges; boundary="+boundary)
+	c.Response().SetBodyStreamWriter(func(w *bufio.Writer) {
+		mw := multipart.NewWriter(w)
+		mw.SetBoundary(boundary)
+
+		for _, r := range ranges {
+			if err := ht.writeFileRangePart(ctx, mw, file, metadata, r); err != nil {
+				logging.L(ctx).Error("fail write range", logging.StringAttr("file", file), logging.ErrAttr(err))
+				return
+			}
+		}
+
+		mw.Close()
 	})
+
+	return nil
+}
+
+func (ht *HttpTransport) writeFileRangePart(ctx context.Context, mw *multipart.Writer, file string, metadata *domain.FileMetadata, r httpRange) error {
+	content, err := ht.fileHostingService.GetFileRange(ctx, file, r.start, r.length)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		fiber.HeaderContentType:  {metadata.MimeType},
+		fiber.HeaderContentRange: {r.contentRange(metadata.Size)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, content)
+	return err
+}
+
+// ifRangeMatches reports whether a range request must be honored according
+// to the If-Range header, which holds either a strong ETag or a date.
+func ifRangeMatches(ifRange string, etag string, metadata *domain.FileMetadata) bool {
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return ifRange == etag
+	}
+
+	date, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+	return metadata.CreatedAt.Truncate(time.Second).Equal(date)
 }
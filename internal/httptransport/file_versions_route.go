@@ -0,0 +1,80 @@
+package httptransport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// fileVersionView is a file version as returned to clients.
+type fileVersionView struct {
+	Version  string                   `json:"version"`
+	Current  bool                     `json:"current"`
+	Metadata *domain.FileMetadataView `json:"metadata"`
+}
+
+func (ht *HttpTransport) fileVersionsRoutes() {
+	ht.fiber.Get("/file/:file/versions", ht.authorizationMiddleware(domain.ScopeList), func(c *fiber.Ctx) error {
+		versions, err := ht.fileHostingService.GetFileVersions(c.UserContext(), c.Params("file"))
+		if err != nil {
+			return err
+		}
+
+		views := make([]fileVersionView, len(versions))
+		for i, version := range versions {
+			views[i] = fileVersionView{
+				Version:  version.Version,
+				Current:  version.Current,
+				Metadata: version.Metadata.View(),
+			}
+		}
+
+		return c.JSON(views)
+	})
+
+	ht.fiber.Get("/file/:file/versions/:version", ht.authorizationMiddleware(domain.ScopeList), func(c *fiber.Ctx) error {
+		file, err := ht.fileHostingService.GetFileVersion(c.UserContext(), c.Params("file"), c.Params("version"))
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header.Set(fiber.HeaderETag, `"`+file.Metadata.Sha1+`"`)
+		c.Response().Header.Set(fiber.HeaderContentType, file.Metadata.MimeType)
+		c.Response().Header.Set(fiber.HeaderLastModified, file.Metadata.CreatedAt.UTC().Format(http.TimeFormat))
+		c.Response().Header.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"%s\"", file.Metadata.Name))
+		for key, value := range file.Metadata.Meta {
+			header := fmt.Sprintf("X-Meta-%s", key)
+			for i := range value {
+				c.Response().Header.Add(header, value[i])
+			}
+		}
+
+		size := file.Metadata.Size
+		if size <= 0 {
+			size = -1
+		}
+		c.Response().SetBodyStream(file.Content, int(size))
+
+		return nil
+	})
+
+	ht.fiber.Post("/file/:file/versions/:version/restore", ht.authorizationMiddleware(domain.ScopeUploadNamed), func(c *fiber.Ctx) error {
+		metadata, err := ht.fileHostingService.RestoreFileVersion(c.UserContext(), c.Params("file"), c.Params("version"))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(metadata.View())
+	})
+
+	ht.fiber.Delete("/file/:file/versions/:version", ht.authorizationMiddleware(domain.ScopeDelete), func(c *fiber.Ctx) error {
+		err := ht.fileHostingService.DeleteFileVersion(c.UserContext(), c.Params("file"), c.Params("version"))
+		if err != nil {
+			return err
+		}
+
+		return c.SendStatus(http.StatusNoContent)
+	})
+}
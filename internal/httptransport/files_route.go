@@ -1,16 +1,98 @@
 package httptransport
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
 	"github.com/gofiber/fiber/v2"
 )
 
 func (ht *HttpTransport) filesRoute() {
-	ht.fiber.Get("/files", ht.authorizationMiddleware(), func(c *fiber.Ctx) error {
-		files, err := ht.fileHostingService.GetFiles(c.UserContext())
+	ht.fiber.Get("/files", ht.authorizationMiddleware(domain.ScopeList), func(c *fiber.Ctx) error {
+		query, err := parseFileQuery(c)
+		if err != nil {
+			return err
+		}
+
+		page, err := ht.fileHostingService.GetFiles(c.UserContext(), query)
 		if err != nil {
 			return err
 		}
 
-		return c.JSON(files)
+		c.Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+		if len(page.NextCursor) > 0 {
+			c.Set("X-Next-Cursor", page.NextCursor)
+		}
+
+		return c.JSON(domain.FileMetadataViews(page.Files))
 	})
 }
+
+func parseFileQuery(c *fiber.Ctx) (*domain.FileQuery, error) {
+	query := &domain.FileQuery{
+		NamePrefix: c.Query("prefix"),
+		MimeType:   c.Query("mime_type"),
+		Owner:      c.Query("owner"),
+		Sort:       domain.FileSort(c.Query("sort")),
+		Cursor:     c.Query("cursor"),
+	}
+
+	switch order := c.Query("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Unknown order %s", order))
+	}
+
+	if rawLimit := c.Query("limit"); len(rawLimit) > 0 {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil {
+			return nil, apperr.ErrBadRequest.WithMessage("Invalid limit")
+		}
+		query.Limit = limit
+	}
+
+	if rawHasBackup := c.Query("has_backup"); len(rawHasBackup) > 0 {
+		hasBackup, err := strconv.ParseBool(rawHasBackup)
+		if err != nil {
+			return nil, apperr.ErrBadRequest.WithMessage("Invalid has_backup")
+		}
+		query.HasBackup = &hasBackup
+	}
+
+	times := map[string]*time.Time{
+		"created_after":  &query.CreatedAfter,
+		"created_before": &query.CreatedBefore,
+		"expired_after":  &query.ExpiredAfter,
+		"expired_before": &query.ExpiredBefore,
+	}
+	for key, value := range times {
+		raw := c.Query(key)
+		if len(raw) == 0 {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Invalid %s, expected RFC 3339 time", key))
+		}
+		*value = parsed
+	}
+
+	for _, raw := range c.Context().QueryArgs().PeekMulti("meta") {
+		key, value, ok := strings.Cut(string(raw), ":")
+		if !ok {
+			return nil, apperr.ErrBadRequest.WithMessage("Invalid meta, expected key:value")
+		}
+		if query.Meta == nil {
+			query.Meta = make(map[string]string)
+		}
+		query.Meta[key] = value
+	}
+
+	return query, nil
+}
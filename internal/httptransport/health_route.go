@@ -6,8 +6,21 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// HealthCheck returns an error while a dependency is unavailable.
+type HealthCheck func() error
+
 func (ht *HttpTransport) healthRoute() {
 	ht.fiber.Get("/health", func(c *fiber.Ctx) error {
+		failures := map[string]string{}
+		for name, check := range ht.healthChecks {
+			if err := check(); err != nil {
+				failures[name] = err.Error()
+			}
+		}
+		if len(failures) > 0 {
+			return c.Status(http.StatusServiceUnavailable).JSON(failures)
+		}
+
 		return c.SendStatus(http.StatusNoContent)
 	})
 }
@@ -0,0 +1,100 @@
+package httptransport
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var errInvalidRange = errors.New("invalid range")
+
+type httpRange struct {
+	start  int64
+	length int64
+}
+
+func (r httpRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// parseRange parses a Range header as described in RFC 9110.
+// Ranges that start beyond the end of the file are skipped, errInvalidRange
+// is returned when the header is malformed or no range can be satisfied.
+func parseRange(header string, size int64) ([]httpRange, error) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return nil, errInvalidRange
+	}
+
+	var ranges []httpRange
+	for _, spec := range strings.Split(header[len(prefix):], ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		rawStart, rawEnd, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, errInvalidRange
+		}
+		rawStart, rawEnd = strings.TrimSpace(rawStart), strings.TrimSpace(rawEnd)
+
+		var r httpRange
+		if rawStart == "" {
+			// Suffix range: the last N bytes
+			if rawEnd == "" || rawEnd[0] == '-' {
+				return nil, errInvalidRange
+			}
+			n, err := strconv.ParseInt(rawEnd, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errInvalidRange
+			}
+			if n == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r.start = size - n
+			r.length = n
+		} else {
+			start, err := strconv.ParseInt(rawStart, 10, 64)
+			if err != nil || start < 0 {
+				return nil, errInvalidRange
+			}
+			if start >= size {
+				continue
+			}
+			r.start = start
+			if rawEnd == "" {
+				r.length = size - start
+			} else {
+				end, err := strconv.ParseInt(rawEnd, 10, 64)
+				if err != nil || start > end {
+					return nil, errInvalidRange
+				}
+				if end >= size {
+					end = size - 1
+				}
+				r.length = end - start + 1
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	if len(ranges) == 0 {
+		return nil, errInvalidRange
+	}
+
+	return ranges, nil
+}
+
+// sumRangesSize returns the amount of bytes requested by ranges.
+func sumRangesSize(ranges []httpRange) int64 {
+	var size int64
+	for _, r := range ranges {
+		size += r.length
+	}
+	return size
+}
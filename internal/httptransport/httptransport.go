@@ -3,11 +3,14 @@ package httptransport
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"strings"
 
 	"github.com/ansrivas/fiberprometheus/v2"
 	"github.com/bruhabruh/file-hosting/internal/app/apperr"
 	"github.com/bruhabruh/file-hosting/internal/config"
+	"github.com/bruhabruh/file-hosting/internal/domain"
 	"github.com/bruhabruh/file-hosting/internal/service"
 	"github.com/bruhabruh/file-hosting/pkg/logging"
 	"github.com/bruhabruh/file-hosting/pkg/slogfiber"
@@ -18,20 +21,34 @@ import (
 )
 
 type HttpTransport struct {
-	config             *config.Config
-	logger             *logging.Logger
-	registry           *prometheus.Registry
-	fileHostingService service.FileHostingService
-	fiber              *fiber.App
-	notify             chan error
+	config                 *config.Config
+	logger                 *logging.Logger
+	registry               *prometheus.Registry
+	fileHostingService     service.FileHostingService
+	resumableUploadService service.ResumableUploadService
+	presignService         service.PresignService
+	signedURLService       service.SignedURLService
+	filePasswordService    service.FilePasswordService
+	webhookService         service.WebhookService
+	authenticator          service.Authenticator
+	healthChecks           map[string]HealthCheck
+	fiber                  *fiber.App
+	notify                 chan error
 }
 
-func New(config *config.Config, logger *logging.Logger, registry *prometheus.Registry, fileHostingService service.FileHostingService) *HttpTransport {
+func New(config *config.Config, logger *logging.Logger, registry *prometheus.Registry, fileHostingService service.FileHostingService, resumableUploadService service.ResumableUploadService, presignService service.PresignService, signedURLService service.SignedURLService, filePasswordService service.FilePasswordService, webhookService service.WebhookService, authenticator service.Authenticator, healthChecks map[string]HealthCheck) *HttpTransport {
 	transport := &HttpTransport{
-		config:             config,
-		registry:           registry,
-		logger:             logger,
-		fileHostingService: fileHostingService,
+		config:                 config,
+		registry:               registry,
+		logger:                 logger,
+		fileHostingService:     fileHostingService,
+		resumableUploadService: resumableUploadService,
+		presignService:         presignService,
+		signedURLService:       signedURLService,
+		filePasswordService:    filePasswordService,
+		webhookService:         webhookService,
+		authenticator:          authenticator,
+		healthChecks:           healthChecks,
 		fiber: fiber.New(
 			fiber.Config{
 				AppName:               "File-Hosting",
@@ -39,6 +56,13 @@ func New(config *config.Config, logger *logging.Logger, registry *prometheus.Reg
 				JSONEncoder:           json.Marshal,
 				JSONDecoder:           json.Unmarshal,
 				BodyLimit:             config.HTTP().MaxBodySizeInMB() * 1024 * 1024,
+				// Bodies are streamed instead of being buffered, which bypasses
+				// BodyLimit. Uploads are limited by bodyLimitMiddleware and
+				// bodyLimitReader, other requests by requestBodyLimitMiddleware
+				StreamRequestBody: true,
+				// Multipart forms are read by the routes, so uploads are limited
+				// while they are read
+				DisablePreParseMultipartForm: true,
 				ErrorHandler: func(c *fiber.Ctx, err error) error {
 					code := fiber.StatusInternalServerError
 
@@ -132,6 +156,7 @@ func (ht *HttpTransport) configureMiddlewares() {
 	ht.fiber.Use(fiberProm.Middleware)
 
 	ht.fiber.Use(recover.New())
+	ht.fiber.Use(ht.requestBodyLimitMiddleware())
 }
 
 func (ht *HttpTransport) configureRoutes() {
@@ -139,25 +164,163 @@ func (ht *HttpTransport) configureRoutes() {
 	ht.indexRoute()
 	ht.filesRoute()
 	ht.fileRoute()
+	ht.filePasswordRoute()
 	ht.fileMetadataRoute()
+	ht.signedURLRoute()
 	ht.uploadPublicRoute()
 	ht.uploadPrivateRoute()
 	ht.renameFileRoute()
+	ht.fileExpiryRoute()
 	ht.deleteFileRoute()
+	ht.fileVersionsRoutes()
+	ht.trashRoutes()
+	ht.tusRoutes()
+	ht.presignRoutes()
+	ht.webhookRoutes()
 }
 
-func (ht *HttpTransport) authorizationMiddleware() fiber.Handler {
-	header := fmt.Sprintf("Bearer %s", ht.config.ApiKey())
+// authorizationMiddleware accepts requests with an API key or a JWT having
+// scope.
+func (ht *HttpTransport) authorizationMiddleware(scope domain.Scope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodOptions {
+			return c.Next()
+		}
+
+		if err := ht.authenticate(c); err != nil {
+			return err
+		}
+
+		if err := service.RequireScope(c.UserContext(), scope); err != nil {
+			return err
+		}
 
+		return c.Next()
+	}
+}
+
+// authenticationMiddleware accepts requests with any valid API key or JWT.
+func (ht *HttpTransport) authenticationMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if c.Method() == fiber.MethodOptions {
 			return c.Next()
 		}
 
-		if c.Get(fiber.HeaderAuthorization) != header {
-			return apperr.ErrUnauthorized
+		if err := ht.authenticate(c); err != nil {
+			return err
+		}
+
+		return c.Next()
+	}
+}
+
+// authenticate verifies the key of the Authorization header. The key is
+// added to the user context and its label to the access log.
+func (ht *HttpTransport) authenticate(c *fiber.Ctx) error {
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok {
+		return apperr.ErrUnauthorized
+	}
+
+	key, err := ht.authenticator.Authenticate(c.UserContext(), token, c.IP())
+	if err != nil {
+		return err
+	}
+
+	slogfiber.AddCustomAttributes(c, slog.String("api_key", key.Label))
+	c.SetUserContext(service.ContextWithAPIKey(c.UserContext(), key))
+
+	return nil
+}
+
+// requireExpiryScope rejects permanent expiry unless the key of the request
+// has domain.ScopeUploadPermanent.
+func requireExpiryScope(c *fiber.Ctx, expiry domain.FileExpiry) error {
+	if !expiry.IsPermanent() {
+		return nil
+	}
+	return service.RequireScope(c.UserContext(), domain.ScopeUploadPermanent)
+}
+
+// bodyLimitMiddleware rejects uploads declaring a body bigger than the
+// configured limit before it is read. Bodies of unknown length are limited
+// by bodyLimitReader while they are read.
+func (ht *HttpTransport) bodyLimitMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if int64(c.Request().Header.ContentLength()) > ht.maxBodySize() {
+			return apperr.ErrRequestEntityTooLarge
+		}
+
+		return c.Next()
+	}
+}
+
+// requestBodyLimitMiddleware limits the bodies of requests other than
+// streamed uploads, which are read whole. Their length must be declared and
+// within the configured limit, the body is read up to the declared length.
+func (ht *HttpTransport) requestBodyLimitMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if isStreamedUpload(c) {
+			return c.Next()
+		}
+
+		length := c.Request().Header.ContentLength()
+		if length == -1 {
+			return apperr.ErrLengthRequired
+		}
+		if int64(length) > ht.maxBodySize() {
+			return apperr.ErrRequestEntityTooLarge
 		}
 
 		return c.Next()
 	}
 }
+
+// isStreamedUpload tells whether the request is an upload whose body is
+// streamed by the route, which limits it. Chunks of tus uploads are limited
+// by the length of the upload.
+func isStreamedUpload(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodPost:
+		return c.Path() == "/upload" || strings.HasPrefix(c.Path(), "/upload/")
+	case fiber.MethodPatch:
+		return strings.HasPrefix(c.Path(), "/tus/")
+	}
+	return false
+}
+
+// bodyLimitReader reads a streamed body and fails with
+// apperr.ErrRequestEntityTooLarge once it exceeds remaining bytes.
+type bodyLimitReader struct {
+	reader    io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (r *bodyLimitReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, apperr.ErrRequestEntityTooLarge
+	}
+
+	if r.remaining == 0 {
+		// The body may end right at the limit
+		var probe [1]byte
+		n, err := r.reader.Read(probe[:])
+		if n > 0 {
+			r.exceeded = true
+			return 0, apperr.ErrRequestEntityTooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	return n, err
+}
+
+func (ht *HttpTransport) maxBodySize() int64 {
+	return int64(ht.config.HTTP().MaxBodySizeInMB()) * 1024 * 1024
+}
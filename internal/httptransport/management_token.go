@@ -0,0 +1,29 @@
+package httptransport
+
+import (
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// headerManagementToken carries the management token of a file uploaded with
+// a generated id, in upload responses and in requests managing the file.
+const headerManagementToken = "X-Management-Token"
+
+// fileAuthorizationMiddleware accepts requests with the management token of
+// the :file param, others are authorized by authorizationMiddleware.
+func (ht *HttpTransport) fileAuthorizationMiddleware(scope domain.Scope) fiber.Handler {
+	authorization := ht.authorizationMiddleware(scope)
+
+	return func(c *fiber.Ctx) error {
+		token := c.Get(headerManagementToken)
+		if len(token) == 0 {
+			return authorization(c)
+		}
+
+		if err := ht.fileHostingService.AuthorizeManagementToken(c.UserContext(), c.Params("file"), token); err != nil {
+			return err
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package httptransport
+
+import (
+	"strconv"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+)
+
+// parseMaxDownloads reads the max_downloads parameter, empty means no limit.
+func parseMaxDownloads(raw string) (int64, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+
+	maxDownloads, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || maxDownloads < 0 {
+		return 0, apperr.ErrBadRequest.WithMessage("Invalid max_downloads, expected a non-negative integer")
+	}
+
+	return maxDownloads, nil
+}
@@ -0,0 +1,160 @@
+package httptransport
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/service"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+)
+
+// headerFilePassword carries the password of a file in uploads setting it
+// and in requests reading the file.
+const headerFilePassword = "X-File-Password"
+
+// passwordCookie remembers the entered password, it is scoped to the path of
+// the file.
+const passwordCookie = "file_password"
+
+// passwordForm asks for the password of a file. It is submitted to the URL
+// of the file, which keeps the query of signed URLs.
+var passwordForm = template.Must(template.New("password").Parse(`<!doctype html>
+<html>
+  <head>
+    <title>{{.Name}}</title>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <style>
+      body { background: #18181b; color: #fafafa; font-family: sans-serif; }
+      main { max-width: 24rem; margin: 3rem auto; padding: 0 1rem; }
+      form { display: flex; flex-direction: column; gap: 1rem; }
+      input, button { padding: 0.5rem; border: 1px solid #3f3f46; border-radius: 0.375rem; background: #18181b; color: inherit; font-size: 1rem; }
+      p.error { color: #f87171; }
+    </style>
+  </head>
+  <body>
+    <main>
+      <h1>{{.Name}}</h1>
+      <p>The file is protected by a password.</p>
+      {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+      <form method="post" action="{{.Action}}">
+        <input type="password" name="password" placeholder="Password" autocomplete="current-password" required autofocus />
+        <button type="submit">Open</button>
+      </form>
+    </main>
+  </body>
+</html>
+`))
+
+// hashPassword hashes the password of an upload, empty means no password.
+func hashPassword(c *fiber.Ctx, password string) (string, error) {
+	if len(password) == 0 {
+		return "", nil
+	}
+
+	hash, err := service.HashSecret(password)
+	if err != nil {
+		logging.L(c.UserContext()).Error("Fail hash password", logging.ErrAttr(err))
+		return "", apperr.ErrInternalServerError.WithMessage("Fail hash password")
+	}
+
+	return hash, nil
+}
+
+// authorizePassword accepts reading a file with a password by the password
+// cookie or X-File-Password header, the password sets the cookie. It
+// returns false when the password is missing and the request accepts HTML,
+// then the password form is sent instead of an error.
+func (ht *HttpTransport) authorizePassword(c *fiber.Ctx, metadata *domain.FileMetadata) (bool, error) {
+	if !metadata.IsPasswordProtected() {
+		return true, nil
+	}
+
+	if ht.filePasswordService.VerifyCookie(metadata, c.Cookies(passwordCookie)) {
+		return true, nil
+	}
+
+	password := c.Get(headerFilePassword)
+	if len(password) == 0 {
+		if acceptsHTML(c) {
+			return false, nil
+		}
+		return false, apperr.ErrUnauthorized
+	}
+
+	if err := ht.filePasswordService.VerifyPassword(c.UserContext(), metadata, password, c.IP()); err != nil {
+		return false, err
+	}
+
+	ht.setPasswordCookie(c, metadata)
+
+	return true, nil
+}
+
+func (ht *HttpTransport) setPasswordCookie(c *fiber.Ctx, metadata *domain.FileMetadata) {
+	value, expiresAt := ht.filePasswordService.IssueCookie(metadata)
+
+	c.Cookie(&fiber.Cookie{
+		Name:     passwordCookie,
+		Value:    value,
+		Path:     c.Path(),
+		Expires:  expiresAt,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+func (ht *HttpTransport) sendPasswordForm(c *fiber.Ctx, metadata *domain.FileMetadata, status int, message string) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return passwordForm.Execute(c.Status(status), struct {
+		Name   string
+		Error  string
+		Action string
+	}{
+		Name:   metadata.Name,
+		Error:  message,
+		Action: c.OriginalURL(),
+	})
+}
+
+// filePasswordRoute receives the password form. The entered password sets
+// the cookie and redirects back to the file.
+func (ht *HttpTransport) filePasswordRoute() {
+	ht.fiber.Post("/file/:file", func(c *fiber.Ctx) error {
+		metadata, err := ht.fileHostingService.GetFileMetadata(c.UserContext(), c.Params("file"))
+		if err != nil {
+			return err
+		}
+
+		if !metadata.IsPasswordProtected() {
+			return apperr.ErrMethodNotAllowed
+		}
+
+		if err := ht.authorizePrivateFile(c, metadata); err != nil {
+			return err
+		}
+
+		if err := ht.filePasswordService.VerifyPassword(c.UserContext(), metadata, c.FormValue("password"), c.IP()); err != nil {
+			var appErr *apperr.AppError
+			if errors.As(err, &appErr) && appErr.Code() != fiber.StatusInternalServerError {
+				return ht.sendPasswordForm(c, metadata, appErr.Code(), appErr.Message())
+			}
+			return err
+		}
+
+		ht.setPasswordCookie(c, metadata)
+
+		return c.Redirect(c.OriginalURL(), fiber.StatusSeeOther)
+	})
+}
+
+func acceptsHTML(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodGet && strings.Contains(c.Get(fiber.HeaderAccept), fiber.MIMETextHTML)
+}
@@ -0,0 +1,100 @@
+package httptransport
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// presignedUploadView is a presigned upload as returned to clients.
+type presignedUploadView struct {
+	Id           string                   `json:"id"`
+	Url          string                   `json:"url"`
+	UrlExpiredAt time.Time                `json:"url_expired_at"`
+	Metadata     *domain.FileMetadataView `json:"metadata"`
+	Duration     string                   `json:"duration"`
+	ExpiresAt    time.Time                `json:"expires_at"`
+	CreatedAt    time.Time                `json:"created_at"`
+	ExpiredAt    time.Time                `json:"expired_at"`
+}
+
+// presignRoutes let clients upload files directly to the storage: the
+// client receives a presigned URL, sends the content to it by a PUT request
+// and finalizes the upload afterwards.
+func (ht *HttpTransport) presignRoutes() {
+	if ht.presignService == nil {
+		return
+	}
+
+	ht.fiber.Post("/presign/:file", ht.authorizationMiddleware(domain.ScopeUploadNamed), func(c *fiber.Ctx) error {
+		metadata := &domain.FileMetadata{
+			Name:  c.Params("file"),
+			Meta:  make(map[string][]string),
+			Owner: service.OwnerFromContext(c.UserContext()),
+		}
+
+		for key, value := range c.GetReqHeaders() {
+			lowerKey := strings.ToLower(key)
+			if strings.HasPrefix(lowerKey, "x-meta-") {
+				keyForMeta, _ := strings.CutPrefix(lowerKey, "x-meta-")
+				metadata.Meta[keyForMeta] = value
+			}
+		}
+
+		expiry, err := parseFileExpiry(c.Query("d"), c.Query("expires_at"))
+		if err != nil {
+			return err
+		}
+
+		if err := requireExpiryScope(c, expiry); err != nil {
+			return err
+		}
+
+		metadata.MaxDownloads, err = parseMaxDownloads(c.Query("max_downloads"))
+		if err != nil {
+			return err
+		}
+
+		metadata.Private, err = parsePrivate(c.Query("private"))
+		if err != nil {
+			return err
+		}
+
+		metadata.PasswordHash, err = hashPassword(c, c.Get(headerFilePassword))
+		if err != nil {
+			return err
+		}
+
+		upload, err := ht.presignService.CreateUpload(c.UserContext(), metadata, expiry)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(http.StatusCreated).JSON(presignedUploadView{
+			Id:           upload.Id,
+			Url:          upload.Url,
+			UrlExpiredAt: upload.UrlExpiredAt,
+			Metadata:     upload.Metadata.View(),
+			Duration:     upload.Duration,
+			ExpiresAt:    upload.ExpiresAt,
+			CreatedAt:    upload.CreatedAt,
+			ExpiredAt:    upload.ExpiredAt,
+		})
+	})
+
+	ht.fiber.Post("/presign/:id/finalize", ht.authorizationMiddleware(domain.ScopeUploadNamed), func(c *fiber.Ctx) error {
+		upload, err := ht.presignService.FinalizeUpload(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return err
+		}
+
+		link := fmt.Sprintf("%s/%s", ht.config.Origin(), upload.FileId)
+
+		return c.SendString(link)
+	})
+}
@@ -0,0 +1,76 @@
+package httptransport
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/service"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+)
+
+type fileURLSigning struct {
+	Lifetime string `json:"lifetime"`
+	IP       string `json:"ip"`
+	Method   string `json:"method"`
+}
+
+// parsePrivate reads the private parameter, empty means public.
+func parsePrivate(raw string) (bool, error) {
+	if len(raw) == 0 {
+		return false, nil
+	}
+
+	private, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, apperr.ErrBadRequest.WithMessage("Invalid private, expected true or false")
+	}
+
+	return private, nil
+}
+
+// authorizePrivateFile accepts reading a private file by a signed URL or with
+// an API key with domain.ScopeReadPrivate.
+func (ht *HttpTransport) authorizePrivateFile(c *fiber.Ctx, metadata *domain.FileMetadata) error {
+	if !metadata.Private {
+		return nil
+	}
+
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil || !service.IsSignedURL(query) {
+		if err := ht.authenticate(c); err != nil {
+			return err
+		}
+		return service.RequireScope(c.UserContext(), domain.ScopeReadPrivate)
+	}
+
+	if ht.signedURLService == nil {
+		return apperr.ErrForbidden.WithMessage("Signed urls are disabled")
+	}
+
+	return ht.signedURLService.Verify(c.UserContext(), metadata.Id, query, c.IP(), c.Method())
+}
+
+func (ht *HttpTransport) signedURLRoute() {
+	ht.fiber.Post("/file/:file/signed-url", ht.authorizationMiddleware(domain.ScopeReadPrivate), func(c *fiber.Ctx) error {
+		if ht.signedURLService == nil {
+			return apperr.ErrNotImplemented.WithMessage("Signed urls are disabled")
+		}
+
+		var fileURLSigning fileURLSigning
+		if rawBody := c.BodyRaw(); len(rawBody) > 0 {
+			if err := json.Unmarshal(rawBody, &fileURLSigning); err != nil {
+				return apperr.ErrBadRequest.WithMessage("invalid json")
+			}
+		}
+
+		signedURL, err := ht.signedURLService.Sign(c.UserContext(), c.Params("file"), fileURLSigning.Lifetime, fileURLSigning.IP, fileURLSigning.Method)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(signedURL)
+	})
+}
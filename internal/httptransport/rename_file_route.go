@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,7 +14,7 @@ type fileRename struct {
 }
 
 func (ht *HttpTransport) renameFileRoute() {
-	ht.fiber.Patch("/file/:file", ht.authorizationMiddleware(), func(c *fiber.Ctx) error {
+	ht.fiber.Patch("/file/:file", ht.authorizationMiddleware(domain.ScopeRename), func(c *fiber.Ctx) error {
 		rawBody := c.BodyRaw()
 
 		var fileRename fileRename
@@ -0,0 +1,48 @@
+package httptransport
+
+import (
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// trashedFileView is a trashed file as returned to clients.
+type trashedFileView struct {
+	Id        string                   `json:"id"`
+	FileId    string                   `json:"file_id"`
+	DeletedAt time.Time                `json:"deleted_at"`
+	PurgeAt   time.Time                `json:"purge_at"`
+	Metadata  *domain.FileMetadataView `json:"metadata"`
+}
+
+func (ht *HttpTransport) trashRoutes() {
+	ht.fiber.Get("/trash", ht.authorizationMiddleware(domain.ScopeList), func(c *fiber.Ctx) error {
+		files, err := ht.fileHostingService.GetTrashedFiles(c.UserContext())
+		if err != nil {
+			return err
+		}
+
+		views := make([]trashedFileView, len(files))
+		for i, file := range files {
+			views[i] = trashedFileView{
+				Id:        file.Id,
+				FileId:    file.FileId,
+				DeletedAt: file.DeletedAt,
+				PurgeAt:   file.PurgeAt,
+				Metadata:  file.Metadata.View(),
+			}
+		}
+
+		return c.JSON(views)
+	})
+
+	ht.fiber.Post("/trash/:id/restore", ht.authorizationMiddleware(domain.ScopeDelete), func(c *fiber.Ctx) error {
+		metadata, err := ht.fileHostingService.RestoreTrashedFile(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(metadata.View())
+	})
+}
@@ -0,0 +1,224 @@
+package httptransport
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,termination,expiration"
+
+	headerTusResumable   = "Tus-Resumable"
+	headerTusVersion     = "Tus-Version"
+	headerTusExtension   = "Tus-Extension"
+	headerTusMaxSize     = "Tus-Max-Size"
+	headerUploadLength   = "Upload-Length"
+	headerUploadOffset   = "Upload-Offset"
+	headerUploadMetadata = "Upload-Metadata"
+	headerUploadExpires  = "Upload-Expires"
+	headerFileUrl        = "X-File-Url"
+
+	mimeOffsetOctetStream = "application/offset+octet-stream"
+)
+
+// tusRoutes implements the tus 1.0 resumable upload protocol with the
+// creation, termination and expiration extensions.
+// See https://tus.io/protocols/resumable-upload
+func (ht *HttpTransport) tusRoutes() {
+	if !ht.config.HTTP().Tus().Enabled() {
+		return
+	}
+
+	tus := ht.fiber.Group("/tus", ht.tusMiddleware())
+
+	tus.Options("/", func(c *fiber.Ctx) error {
+		c.Set(headerTusVersion, tusVersion)
+		c.Set(headerTusExtension, tusExtensions)
+		c.Set(headerTusMaxSize, strconv.FormatInt(ht.tusMaxSize(), 10))
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	tus.Post("/", func(c *fiber.Ctx) error {
+		length, err := strconv.ParseInt(c.Get(headerUploadLength), 10, 64)
+		if err != nil || length < 0 {
+			return apperr.ErrBadRequest.WithMessage("Invalid Upload-Length")
+		}
+		if length > ht.tusMaxSize() {
+			return apperr.ErrRequestEntityTooLarge
+		}
+
+		uploadMetadata, err := parseUploadMetadata(c.Get(headerUploadMetadata))
+		if err != nil {
+			return apperr.ErrBadRequest.WithMessage("Invalid Upload-Metadata")
+		}
+
+		metadata := &domain.FileMetadata{
+			Name:     uploadMetadata["filename"],
+			MimeType: uploadMetadata["filetype"],
+			Meta:     make(map[string][]string),
+		}
+		for key, value := range uploadMetadata {
+			if key == "filename" || key == "filetype" || key == "duration" || key == "expires_at" || key == "max_downloads" || key == "private" || key == "password" {
+				continue
+			}
+			metadata.Meta[key] = []string{value}
+		}
+		for key, value := range c.GetReqHeaders() {
+			lowerKey := strings.ToLower(key)
+			if strings.HasPrefix(lowerKey, "x-meta-") {
+				keyForMeta, _ := strings.CutPrefix(lowerKey, "x-meta-")
+				metadata.Meta[keyForMeta] = value
+			}
+		}
+
+		expiry, err := parseFileExpiry(c.Query("d", uploadMetadata["duration"]), c.Query("expires_at", uploadMetadata["expires_at"]))
+		if err != nil {
+			return err
+		}
+
+		metadata.MaxDownloads, err = parseMaxDownloads(c.Query("max_downloads", uploadMetadata["max_downloads"]))
+		if err != nil {
+			return err
+		}
+
+		metadata.Private, err = parsePrivate(c.Query("private", uploadMetadata["private"]))
+		if err != nil {
+			return err
+		}
+
+		metadata.PasswordHash, err = hashPassword(c, c.Get(headerFilePassword, uploadMetadata["password"]))
+		if err != nil {
+			return err
+		}
+
+		upload, err := ht.resumableUploadService.CreateUpload(c.UserContext(), length, metadata, expiry)
+		if err != nil {
+			return err
+		}
+
+		c.Set(fiber.HeaderLocation, ht.tusUploadURL(upload.Id))
+		c.Set(headerUploadExpires, upload.ExpiredAt.UTC().Format(http.TimeFormat))
+		return c.SendStatus(http.StatusCreated)
+	})
+
+	tus.Head("/:id", func(c *fiber.Ctx) error {
+		upload, err := ht.resumableUploadService.ResumeUpload(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return err
+		}
+
+		ht.setUploadHeaders(c, upload)
+		c.Set(headerUploadLength, strconv.FormatInt(upload.Length, 10))
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		c.Status(http.StatusOK)
+		return nil
+	})
+
+	tus.Patch("/:id", func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderContentType) != mimeOffsetOctetStream {
+			return apperr.ErrUnsupportedMediaType
+		}
+
+		offset, err := strconv.ParseInt(c.Get(headerUploadOffset), 10, 64)
+		if err != nil || offset < 0 {
+			return apperr.ErrBadRequest.WithMessage("Invalid Upload-Offset")
+		}
+
+		content := c.Context().RequestBodyStream()
+		if content == nil {
+			content = bytes.NewReader(c.Body())
+		}
+
+		upload, err := ht.resumableUploadService.AppendUpload(
+			c.UserContext(),
+			c.Params("id"),
+			offset,
+			content,
+			int64(c.Request().Header.ContentLength()),
+		)
+		if err != nil {
+			return err
+		}
+
+		ht.setUploadHeaders(c, upload)
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	tus.Delete("/:id", func(c *fiber.Ctx) error {
+		if err := ht.resumableUploadService.DeleteUpload(c.UserContext(), c.Params("id")); err != nil {
+			return err
+		}
+
+		return c.SendStatus(http.StatusNoContent)
+	})
+}
+
+func (ht *HttpTransport) tusMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(headerTusResumable, tusVersion)
+
+		if c.Method() == fiber.MethodOptions {
+			return c.Next()
+		}
+
+		if c.Get(headerTusResumable) != tusVersion {
+			c.Set(headerTusVersion, tusVersion)
+			return apperr.ErrPreconditionFailed.WithMessage("Unsupported tus version")
+		}
+
+		return c.Next()
+	}
+}
+
+func (ht *HttpTransport) setUploadHeaders(c *fiber.Ctx, upload *domain.ResumableUpload) {
+	c.Set(headerUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	c.Set(headerUploadExpires, upload.ExpiredAt.UTC().Format(http.TimeFormat))
+	if upload.IsCompleted() {
+		c.Set(headerFileUrl, fmt.Sprintf("%s/%s", ht.config.Origin(), upload.FileId))
+	}
+	if len(upload.ManagementToken) > 0 {
+		c.Set(headerManagementToken, upload.ManagementToken)
+	}
+}
+
+// tusUploadURL is the URL of an upload under the configured origin, which
+// is the URL of /file, so it is right behind a proxy.
+func (ht *HttpTransport) tusUploadURL(id string) string {
+	return fmt.Sprintf("%s/tus/%s", strings.TrimSuffix(ht.config.Origin(), "/file"), id)
+}
+
+func (ht *HttpTransport) tusMaxSize() int64 {
+	return int64(ht.config.HTTP().Tus().MaxSizeInMB()) * 1024 * 1024
+}
+
+// parseUploadMetadata decodes Upload-Metadata header: comma separated pairs
+// of a key and an optional base64 encoded value.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, rawValue, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, err
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
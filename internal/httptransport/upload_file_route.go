@@ -1,22 +1,82 @@
 package httptransport
 
 import (
+	"bytes"
 	"fmt"
-	"io"
+	"mime/multipart"
 	"strings"
 
 	"github.com/bruhabruh/file-hosting/internal/app/apperr"
 	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/service"
 	"github.com/bruhabruh/file-hosting/pkg/logging"
 	"github.com/gofiber/fiber/v2"
 )
 
+// multipartMaxMemory is how much of the files of a multipart form is kept in
+// memory, the rest is written to temporary files.
+const multipartMaxMemory = 8 * 1024 * 1024
+
+// uploadResult is the response to uploads accepting JSON, others get the
+// link as text.
+type uploadResult struct {
+	Url             string `json:"url"`
+	Id              string `json:"id"`
+	ManagementToken string `json:"management_token"`
+}
+
+// formFile reads the multipart form of an upload from the streamed body,
+// limited to the configured size, and returns its file key. The returned
+// function removes the temporary files of the form.
+func (ht *HttpTransport) formFile(c *fiber.Ctx, key string) (*multipart.FileHeader, func(), error) {
+	failGetFile := apperr.ErrBadRequest.WithMessage("Fail get file")
+
+	boundary := string(c.Request().Header.MultipartFormBoundary())
+	if len(boundary) == 0 {
+		return nil, nil, failGetFile
+	}
+	if encoding := c.Get(fiber.HeaderContentEncoding); len(encoding) > 0 {
+		return nil, nil, apperr.ErrUnsupportedMediaType.WithMessage(fmt.Sprintf("Unsupported Content-Encoding %s", encoding))
+	}
+
+	content := c.Context().RequestBodyStream()
+	if content == nil {
+		content = bytes.NewReader(c.Body())
+	}
+	body := &bodyLimitReader{reader: content, remaining: ht.maxBodySize()}
+
+	form, err := multipart.NewReader(body, boundary).ReadForm(multipartMaxMemory)
+	if body.exceeded {
+		if form != nil {
+			form.RemoveAll()
+		}
+		return nil, nil, apperr.ErrRequestEntityTooLarge
+	}
+	if err != nil {
+		logging.L(c.UserContext()).Warn("failed to get file from form", logging.ErrAttr(err))
+		return nil, nil, failGetFile
+	}
+	cleanup := func() { form.RemoveAll() }
+
+	files := form.File[key]
+	if len(files) == 0 {
+		cleanup()
+		return nil, nil, failGetFile
+	}
+
+	return files[0], cleanup, nil
+}
+
 func (ht *HttpTransport) uploadPublicRoute() {
-	ht.fiber.Post("/upload", func(c *fiber.Ctx) error {
-		fileHeader, err := c.FormFile("file")
+	ht.fiber.Post("/upload", ht.bodyLimitMiddleware(), func(c *fiber.Ctx) error {
+		fileHeader, cleanup, err := ht.formFile(c, "file")
 		if err != nil {
-			logging.L(c.UserContext()).Warn("failed to get file from form", logging.ErrAttr(err))
-			return apperr.ErrBadRequest.WithMessage("Fail get file")
+			return err
+		}
+		defer cleanup()
+
+		if fileHeader.Size > ht.maxBodySize() {
+			return apperr.ErrRequestEntityTooLarge
 		}
 
 		file, err := fileHeader.Open()
@@ -26,12 +86,6 @@ func (ht *HttpTransport) uploadPublicRoute() {
 		}
 		defer file.Close()
 
-		content, err := io.ReadAll(file)
-		if err != nil {
-			logging.L(c.UserContext()).Warn("failed to read file", logging.ErrAttr(err))
-			return apperr.ErrBadRequest.WithMessage("Fail to read file")
-		}
-
 		metadata := &domain.FileMetadata{
 			Name:     fileHeader.Filename,
 			MimeType: fileHeader.Header.Get(fiber.HeaderContentType),
@@ -46,23 +100,52 @@ func (ht *HttpTransport) uploadPublicRoute() {
 			}
 		}
 
-		fileName, _, err := ht.fileHostingService.UploadFileWithGenerativeName(c.UserContext(), content, metadata, c.Query("d"))
+		expiry, err := parseFileExpiry(c.Query("d"), c.Query("expires_at"))
+		if err != nil {
+			return err
+		}
+
+		metadata.MaxDownloads, err = parseMaxDownloads(c.Query("max_downloads"))
+		if err != nil {
+			return err
+		}
+
+		metadata.Private, err = parsePrivate(c.Query("private"))
+		if err != nil {
+			return err
+		}
+
+		metadata.PasswordHash, err = hashPassword(c, c.Get(headerFilePassword))
+		if err != nil {
+			return err
+		}
+
+		fileName, fileMetadata, err := ht.fileHostingService.UploadFileWithGenerativeName(c.UserContext(), file, fileHeader.Size, metadata, expiry)
 		if err != nil {
 			return err
 		}
 
 		link := fmt.Sprintf("%s/%s", ht.config.Origin(), fileName)
 
+		c.Set(headerManagementToken, fileMetadata.ManagementToken)
+		if c.Accepts(fiber.MIMETextPlain, fiber.MIMEApplicationJSON) == fiber.MIMEApplicationJSON {
+			return c.JSON(uploadResult{Url: link, Id: fileName, ManagementToken: fileMetadata.ManagementToken})
+		}
+
 		return c.SendString(link)
 	})
 }
 
 func (ht *HttpTransport) uploadPrivateRoute() {
-	ht.fiber.Post("/upload/:file", ht.authorizationMiddleware(), func(c *fiber.Ctx) error {
-		fileHeader, err := c.FormFile("file")
+	ht.fiber.Post("/upload/:file", ht.authorizationMiddleware(domain.ScopeUploadNamed), ht.bodyLimitMiddleware(), func(c *fiber.Ctx) error {
+		fileHeader, cleanup, err := ht.formFile(c, "file")
 		if err != nil {
-			logging.L(c.UserContext()).Warn("failed to get file from form", logging.ErrAttr(err))
-			return apperr.ErrBadRequest.WithMessage("Fail get file")
+			return err
+		}
+		defer cleanup()
+
+		if fileHeader.Size > ht.maxBodySize() {
+			return apperr.ErrRequestEntityTooLarge
 		}
 
 		file, err := fileHeader.Open()
@@ -72,16 +155,11 @@ func (ht *HttpTransport) uploadPrivateRoute() {
 		}
 		defer file.Close()
 
-		content, err := io.ReadAll(file)
-		if err != nil {
-			logging.L(c.UserContext()).Warn("failed to read file", logging.ErrAttr(err))
-			return apperr.ErrBadRequest.WithMessage("Fail to read file")
-		}
-
 		metadata := &domain.FileMetadata{
 			Name:     c.Params("file"),
 			MimeType: fileHeader.Header.Get(fiber.HeaderContentType),
 			Meta:     make(map[string][]string),
+			Owner:    service.OwnerFromContext(c.UserContext()),
 		}
 
 		for key, value := range c.GetReqHeaders() {
@@ -92,7 +170,31 @@ func (ht *HttpTransport) uploadPrivateRoute() {
 			}
 		}
 
-		fileName, _, err := ht.fileHostingService.UploadFile(c.UserContext(), content, metadata, c.Query("d"))
+		expiry, err := parseFileExpiry(c.Query("d"), c.Query("expires_at"))
+		if err != nil {
+			return err
+		}
+
+		if err := requireExpiryScope(c, expiry); err != nil {
+			return err
+		}
+
+		metadata.MaxDownloads, err = parseMaxDownloads(c.Query("max_downloads"))
+		if err != nil {
+			return err
+		}
+
+		metadata.Private, err = parsePrivate(c.Query("private"))
+		if err != nil {
+			return err
+		}
+
+		metadata.PasswordHash, err = hashPassword(c, c.Get(headerFilePassword))
+		if err != nil {
+			return err
+		}
+
+		fileName, _, err := ht.fileHostingService.UploadFile(c.UserContext(), file, fileHeader.Size, metadata, expiry)
 		if err != nil {
 			return err
 		}
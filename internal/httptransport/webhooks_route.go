@@ -0,0 +1,67 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+)
+
+type webhookCreation struct {
+	Url    string                    `json:"url"`
+	Secret string                    `json:"secret"`
+	Events []domain.WebhookEventType `json:"events"`
+}
+
+// webhookRoutes manage the subscriptions of webhooks to events of files.
+func (ht *HttpTransport) webhookRoutes() {
+	if ht.webhookService == nil {
+		return
+	}
+
+	ht.fiber.Get("/webhooks", ht.authorizationMiddleware(domain.ScopeAdmin), func(c *fiber.Ctx) error {
+		subscriptions, err := ht.webhookService.GetSubscriptions(c.UserContext())
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(subscriptions)
+	})
+
+	ht.fiber.Post("/webhooks", ht.authorizationMiddleware(domain.ScopeAdmin), func(c *fiber.Ctx) error {
+		var webhookCreation webhookCreation
+		if err := json.Unmarshal(c.BodyRaw(), &webhookCreation); err != nil {
+			return apperr.ErrBadRequest.WithMessage("invalid json")
+		}
+
+		subscription, err := ht.webhookService.CreateSubscription(c.UserContext(), &domain.WebhookSubscription{
+			Url:    webhookCreation.Url,
+			Secret: webhookCreation.Secret,
+			Events: webhookCreation.Events,
+		})
+		if err != nil {
+			return err
+		}
+
+		return c.Status(http.StatusCreated).JSON(subscription)
+	})
+
+	ht.fiber.Delete("/webhooks/:id", ht.authorizationMiddleware(domain.ScopeAdmin), func(c *fiber.Ctx) error {
+		if err := ht.webhookService.DeleteSubscription(c.UserContext(), c.Params("id")); err != nil {
+			return err
+		}
+
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	ht.fiber.Get("/webhooks/:id/deliveries", ht.authorizationMiddleware(domain.ScopeAdmin), func(c *fiber.Ctx) error {
+		deliveries, err := ht.webhookService.GetDeliveries(c.UserContext(), c.Params("id"))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(deliveries)
+	})
+}
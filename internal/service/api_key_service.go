@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// LegacyAPIKeyLabel is the label of the key set by the API_KEY variable,
+// which has all scopes.
+const LegacyAPIKeyLabel = "API_KEY"
+
+type APIKeyService interface {
+	// Authenticate returns the key of token, "<id>.<secret>" or the API_KEY
+	// variable, sent by client. Unknown, invalid and expired tokens are
+	// rejected with apperr.ErrUnauthorized, and clients which sent too many
+	// wrong secrets of a key with apperr.ErrTooManyRequests.
+	Authenticate(ctx context.Context, token string, client string) (*domain.APIKey, error)
+}
+
+type ctxAPIKey struct{}
+
+// ContextWithAPIKey adds the key which authorized a request to ctx.
+func ContextWithAPIKey(ctx context.Context, key *domain.APIKey) context.Context {
+	return context.WithValue(ctx, ctxAPIKey{}, key)
+}
+
+// APIKeyFromContext returns the key which authorized a request, nil for
+// anonymous requests.
+func APIKeyFromContext(ctx context.Context) *domain.APIKey {
+	key, _ := ctx.Value(ctxAPIKey{}).(*domain.APIKey)
+	return key
+}
+
+// OwnerFromContext returns the owner of the key which authorized a request,
+// empty for anonymous requests and keys without an owner.
+func OwnerFromContext(ctx context.Context) string {
+	if key := APIKeyFromContext(ctx); key != nil {
+		return key.Owner
+	}
+	return ""
+}
+
+// RequireScope rejects requests whose key lacks scope with
+// apperr.ErrForbidden.
+func RequireScope(ctx context.Context, scope domain.Scope) error {
+	key := APIKeyFromContext(ctx)
+	if key == nil {
+		return apperr.ErrUnauthorized
+	}
+	if !key.HasScope(scope) {
+		return apperr.ErrForbidden.WithMessage(fmt.Sprintf("API key %s lacks scope %s", key.Label, scope))
+	}
+	return nil
+}
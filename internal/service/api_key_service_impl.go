@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+)
+
+// apiKeySecretSize is the size of generated secrets in bytes.
+const apiKeySecretSize = 32
+
+type APIKeyServiceImpl struct {
+	legacy       *domain.APIKey
+	legacyDigest [sha256.Size]byte
+	keys         map[string]*domain.APIKey
+
+	// verified holds digests of tokens whose secret matched the hash, so a
+	// key costs a single slow hash verification.
+	verified   map[string][sha256.Size]byte
+	verifiedMu sync.RWMutex
+
+	// limiter counts slow hash verifications of a key by a client, so
+	// guessing secrets of a known id can not exhaust the CPU and memory.
+	limiter     PasswordAttemptLimiter
+	maxFailures int64
+}
+
+var _ APIKeyService = (*APIKeyServiceImpl)(nil)
+
+// NewAPIKeyService accepts legacyKey, when it is not empty, and keys. Keys
+// without a label are labeled by their id. A client can send maxFailures
+// wrong secrets of a key within the window of limiter.
+func NewAPIKeyService(legacyKey string, keys []*domain.APIKey, limiter PasswordAttemptLimiter, maxFailures int) (*APIKeyServiceImpl, error) {
+	s := &APIKeyServiceImpl{
+		keys:        make(map[string]*domain.APIKey, len(keys)),
+		verified:    make(map[string][sha256.Size]byte),
+		limiter:     limiter,
+		maxFailures: int64(maxFailures),
+	}
+
+	if len(legacyKey) > 0 {
+		s.legacy = &domain.APIKey{
+			Id:     LegacyAPIKeyLabel,
+			Label:  LegacyAPIKeyLabel,
+			Scopes: []domain.Scope{domain.ScopeAdmin},
+		}
+		s.legacyDigest = sha256.Sum256([]byte(legacyKey))
+	}
+
+	for _, key := range keys {
+		if len(key.Id) == 0 || strings.Contains(key.Id, ".") {
+			return nil, fmt.Errorf("invalid API key id: %q", key.Id)
+		}
+		if _, ok := s.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate API key id: %s", key.Id)
+		}
+		if !IsSecretHash(key.Hash) {
+			return nil, fmt.Errorf("invalid hash of API key %s", key.Id)
+		}
+		if len(key.Scopes) == 0 {
+			return nil, fmt.Errorf("API key %s has no scopes", key.Id)
+		}
+		for _, scope := range key.Scopes {
+			if !scope.IsValid() {
+				return nil, fmt.Errorf("invalid scope of API key %s: %s", key.Id, scope)
+			}
+		}
+		if len(key.Label) == 0 {
+			key.Label = key.Id
+		}
+		s.keys[key.Id] = key
+	}
+
+	return s, nil
+}
+
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, token string, client string) (*domain.APIKey, error) {
+	if len(token) == 0 {
+		return nil, apperr.ErrUnauthorized
+	}
+
+	digest := sha256.Sum256([]byte(token))
+
+	if s.legacy != nil && subtle.ConstantTimeCompare(digest[:], s.legacyDigest[:]) == 1 {
+		return s.legacy, nil
+	}
+
+	id, secret, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, apperr.ErrUnauthorized
+	}
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, apperr.ErrUnauthorized
+	}
+
+	if key.IsExpired(time.Now()) {
+		return nil, apperr.ErrUnauthorized
+	}
+
+	s.verifiedMu.RLock()
+	verified, ok := s.verified[id]
+	s.verifiedMu.RUnlock()
+	if ok && subtle.ConstantTimeCompare(digest[:], verified[:]) == 1 {
+		return key, nil
+	}
+
+	limiterKey := passwordAttemptKey(id, client)
+
+	failures, err := s.limiter.Attempt(ctx, limiterKey)
+	if err != nil {
+		logging.L(ctx).Error("Fail count API key attempt", logging.StringAttr("key", key.Label), logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail verify API key")
+	}
+	if failures > s.maxFailures {
+		return nil, apperr.ErrTooManyRequests.WithMessage("Too many wrong API keys, try again later")
+	}
+
+	if !VerifySecret(secret, key.Hash) {
+		return nil, apperr.ErrUnauthorized
+	}
+
+	if err := s.limiter.Reset(ctx, limiterKey); err != nil {
+		logging.L(ctx).Error("Fail reset API key attempts", logging.StringAttr("key", key.Label), logging.ErrAttr(err))
+	}
+
+	s.verifiedMu.Lock()
+	s.verified[id] = digest
+	s.verifiedMu.Unlock()
+
+	return key, nil
+}
+
+// GenerateAPIKey generates the token of a new key with id and the hash of
+// its secret to put into the config.
+func GenerateAPIKey(id string) (token string, hash string, err error) {
+	if len(id) == 0 || strings.Contains(id, ".") {
+		return "", "", fmt.Errorf("invalid API key id: %q", id)
+	}
+
+	secret := make([]byte, apiKeySecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	encoded := hex.EncodeToString(secret)
+
+	hash, err = HashSecret(encoded)
+	if err != nil {
+		return "", "", err
+	}
+
+	return id + "." + encoded, hash, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// Authenticator authenticates the bearer tokens of requests, client is the
+// address of the caller.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string, client string) (*domain.APIKey, error)
+}
+
+type authenticator struct {
+	apiKeyService APIKeyService
+	jwtService    JWTService
+}
+
+// NewAuthenticator accepts API keys and, when jwtService is not nil, JWTs.
+func NewAuthenticator(apiKeyService APIKeyService, jwtService JWTService) Authenticator {
+	return &authenticator{
+		apiKeyService: apiKeyService,
+		jwtService:    jwtService,
+	}
+}
+
+// Authenticate tells JWTs by their three parts, API keys have two. Rejected
+// JWTs are tried as API keys in case the API_KEY variable looks like one.
+func (a *authenticator) Authenticate(ctx context.Context, token string, client string) (*domain.APIKey, error) {
+	if a.jwtService != nil && strings.Count(token, ".") == 2 {
+		if key, err := a.jwtService.Authenticate(ctx, token); err == nil {
+			return key, nil
+		}
+	}
+
+	return a.apiKeyService.Authenticate(ctx, token, client)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrDeletionJobRejected is returned by a DeletionHandler for a job which
+// fails on every retry, e.g. of a file uploaded again since.
+var ErrDeletionJobRejected = errors.New("deletion job rejected")
+
+// DeletionJobKind tells what a job does when it is due.
+type DeletionJobKind string
+
+const (
+	// DeletionJobDelete deletes the file, it is the kind of jobs without one.
+	DeletionJobDelete DeletionJobKind = ""
+	// DeletionJobExpiring announces that the file expires soon.
+	DeletionJobExpiring DeletionJobKind = "expiring"
+)
+
+// DeletionJob asks to delete FileName at ExpiredAt, or to do what its Kind
+// tells at ExpiredAt. Sha1 guards a file uploaded again under the same name
+// from being deleted by an old job.
+type DeletionJob struct {
+	FileName  string          `json:"fileName"`
+	Sha1      string          `json:"sha1"`
+	ExpiredAt time.Time       `json:"expiredAt"`
+	Kind      DeletionJobKind `json:"kind,omitempty"`
+}
+
+// DeletionHandler handles a due job. The job is retried later when the
+// handler returns an error other than ErrDeletionJobRejected.
+type DeletionHandler func(ctx context.Context, job DeletionJob) error
+
+// DeletionScheduler keeps deletion jobs until they are due.
+type DeletionScheduler interface {
+	// Schedule adds job. Schedulers which identify jobs by file, sha1 and
+	// kind replace a job scheduled before, others add a duplicate, which is
+	// skipped when handled.
+	Schedule(ctx context.Context, job DeletionJob) error
+	// Consume passes due jobs to handler in background until ctx is done.
+	Consume(ctx context.Context, handler DeletionHandler) error
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// DownloadCounter counts downloads of files with MaxDownloads. Downloads are
+// counted per upload, so a file uploaded again under the same name starts
+// from zero, while a renamed file keeps its count.
+type DownloadCounter interface {
+	// Increment counts a download of the file and returns the downloads
+	// counted so far, including this one.
+	Increment(ctx context.Context, metadata *domain.FileMetadata) (int64, error)
+	// Count returns the downloads of the file counted so far.
+	Count(ctx context.Context, metadata *domain.FileMetadata) (int64, error)
+	// Reset forgets the downloads of the file.
+	Reset(ctx context.Context, metadata *domain.FileMetadata) error
+}
+
+// downloadCounterId identifies an upload of a file, it does not change when
+// the file is renamed.
+func downloadCounterId(metadata *domain.FileMetadata) string {
+	return fmt.Sprintf("%s:%d", metadata.Sha1, metadata.CreatedAt.UnixNano())
+}
@@ -2,13 +2,13 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/bruhabruh/file-hosting/internal/app/apperr"
 	"github.com/bruhabruh/file-hosting/internal/domain"
 	"github.com/bruhabruh/file-hosting/internal/storage"
 	"github.com/bruhabruh/file-hosting/pkg/logging"
-	"github.com/bruhabruh/file-hosting/pkg/rabbitmq"
 	"github.com/goccy/go-json"
 	"github.com/redis/go-redis/v9"
 )
@@ -22,8 +22,8 @@ type FileHostingCachedService struct {
 	rdb     *redis.Client
 }
 
-func NewFileHostingCachedService(ctx context.Context, fileStorage storage.FileStorage, mq *rabbitmq.RabbitMQ, rdb *redis.Client) (FileHostingService, error) {
-	service, err := NewFileHostingService(ctx, fileStorage, mq)
+func NewFileHostingCachedService(ctx context.Context, fileStorage storage.FileStorage, metadataStore storage.MetadataStore, scheduler DeletionScheduler, downloads DownloadCounter, webhooks WebhookNotifier, retention domain.VersionRetention, trashRetention time.Duration, tombstoneRetention time.Duration, expiry domain.ExpiryPolicies, rdb *redis.Client) (FileHostingService, error) {
+	service, err := NewFileHostingService(ctx, fileStorage, metadataStore, scheduler, downloads, webhooks, retention, trashRetention, tombstoneRetention, expiry)
 	if err != nil {
 		return nil, err
 	}
@@ -33,63 +33,23 @@ func NewFileHostingCachedService(ctx context.Context, fileStorage storage.FileSt
 	}, nil
 }
 
-func (s *FileHostingCachedService) GetFiles(ctx context.Context) ([]*domain.FileMetadata, error) {
-	val, err := s.rdb.Get(ctx, s.key("files")).Result()
-	if err == redis.Nil {
-		files, err := s.service.GetFiles(ctx)
-		if err != nil {
-			return nil, err
-		}
-		data, err := json.Marshal(files)
-		if err != nil {
-			logging.L(ctx).Error("fail marshal files", logging.ErrAttr(err))
-		} else {
-			if err := s.rdb.Set(ctx, s.key("files"), data, defaultTTL).Err(); err != nil {
-				logging.L(ctx).Error("fail cache files", logging.ErrAttr(err))
-			}
-		}
-		return files, nil
-	} else if err != nil {
-		logging.L(ctx).Error("fail get files", logging.ErrAttr(err))
-		return nil, apperr.ErrInternalServerError.WithMessage("fail get files")
-	}
-
-	var files []*domain.FileMetadata
-	if err := json.Unmarshal([]byte(val), &files); err != nil {
-		return nil, apperr.ErrInternalServerError.WithMessage("fail unmarshal files")
-	}
-
-	return files, nil
+// GetFiles is not cached: pages depend on the query and would have to be
+// invalidated on every change of any file.
+func (s *FileHostingCachedService) GetFiles(ctx context.Context, query *domain.FileQuery) (*domain.FilePage, error) {
+	return s.service.GetFiles(ctx, query)
 }
 
+// GetFile is not cached: content is streamed straight from the storage.
 func (s *FileHostingCachedService) GetFile(ctx context.Context, filename string) (*domain.File, error) {
-	rawFile, err := s.rdb.Get(ctx, s.key("file", filename)).Result()
-	if err == redis.Nil {
-		file, err := s.service.GetFile(ctx, filename)
-		if err != nil {
-			return nil, err
-		}
-
-		data, err := json.Marshal(file)
-		if err != nil {
-			logging.L(ctx).Error("fail marshal file", logging.StringAttr("file", filename), logging.ErrAttr(err))
-		} else {
-			if err := s.rdb.Set(ctx, s.key("file", filename), data, s.ttlOfExpiredAt(file.Metadata.ExpiredAt)).Err(); err != nil {
-				logging.L(ctx).Error("fail cache file", logging.StringAttr("file", filename), logging.ErrAttr(err))
-			}
-		}
-		return file, nil
-	} else if err != nil {
-		logging.L(ctx).Error("fail get file", logging.ErrAttr(err))
-		return nil, apperr.ErrInternalServerError.WithMessage("fail get file")
-	}
+	return s.service.GetFile(ctx, filename)
+}
 
-	file, err := domain.NewFileFromBytes([]byte(rawFile))
-	if err != nil {
-		return nil, apperr.ErrInternalServerError.WithMessage("fail unmarshal file")
-	}
+func (s *FileHostingCachedService) DownloadFile(ctx context.Context, filename string) (*domain.File, error) {
+	return s.service.DownloadFile(ctx, filename)
+}
 
-	return file, nil
+func (s *FileHostingCachedService) GetFileRange(ctx context.Context, filename string, offset int64, length int64) (io.ReadCloser, error) {
+	return s.service.GetFileRange(ctx, filename, offset, length)
 }
 
 func (s *FileHostingCachedService) GetFileMetadata(ctx context.Context, filename string) (*domain.FileMetadata, error) {
@@ -100,6 +60,11 @@ func (s *FileHostingCachedService) GetFileMetadata(ctx context.Context, filename
 			return nil, err
 		}
 
+		// The remaining downloads change with every download
+		if fileMetadata.IsDownloadLimited() {
+			return fileMetadata, nil
+		}
+
 		data, err := json.Marshal(fileMetadata)
 		if err != nil {
 			logging.L(ctx).Error("fail marshal file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
@@ -122,62 +87,37 @@ func (s *FileHostingCachedService) GetFileMetadata(ctx context.Context, filename
 	return fileMetadata, nil
 }
 
-func (s *FileHostingCachedService) UploadFile(ctx context.Context, content []byte, metadata *domain.FileMetadata, rawDuration string) (string, *domain.File, error) {
-	filename, file, err := s.service.UploadFile(ctx, content, metadata, rawDuration)
+func (s *FileHostingCachedService) UploadFile(ctx context.Context, content io.Reader, size int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error) {
+	filename, fileMetadata, err := s.service.UploadFile(ctx, content, size, metadata, expiry)
 	if err != nil {
 		return "", nil, err
 	}
 
-	data, err := json.Marshal(file)
-	if err != nil {
-		logging.L(ctx).Error("fail marshal file", logging.StringAttr("file", filename), logging.ErrAttr(err))
-	} else {
-		if err := s.rdb.Set(ctx, s.key("file", filename), data, s.ttlOfExpiredAt(file.Metadata.ExpiredAt)).Err(); err != nil {
-			logging.L(ctx).Error("fail cache file", logging.StringAttr("file", filename), logging.ErrAttr(err))
-		}
-	}
-	data, err = json.Marshal(file.Metadata)
-	if err != nil {
-		logging.L(ctx).Error("fail marshal file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
-	} else {
-		if err := s.rdb.Set(ctx, s.key("file", filename, "metadata"), data, s.ttlOfExpiredAt(file.Metadata.ExpiredAt)).Err(); err != nil {
-			logging.L(ctx).Error("fail cache file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
-		}
-	}
-	if err := s.rdb.Del(ctx, s.key("files")).Err(); err != nil {
-		logging.L(ctx).Error("fail delete files cache", logging.ErrAttr(err))
-	}
+	s.cacheUploadedFile(ctx, filename, fileMetadata)
 
-	return filename, file, nil
+	return filename, fileMetadata, nil
 }
 
-func (s *FileHostingCachedService) UploadFileWithGenerativeName(ctx context.Context, content []byte, metadata *domain.FileMetadata, rawDuration string) (string, *domain.File, error) {
-	filename, file, err := s.service.UploadFileWithGenerativeName(ctx, content, metadata, rawDuration)
+func (s *FileHostingCachedService) UploadFileWithGenerativeName(ctx context.Context, content io.Reader, size int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error) {
+	filename, fileMetadata, err := s.service.UploadFileWithGenerativeName(ctx, content, size, metadata, expiry)
 	if err != nil {
 		return "", nil, err
 	}
 
-	data, err := json.Marshal(file)
-	if err != nil {
-		logging.L(ctx).Error("fail marshal file", logging.StringAttr("file", filename), logging.ErrAttr(err))
-	} else {
-		if err := s.rdb.Set(ctx, s.key("file", filename), data, s.ttlOfExpiredAt(file.Metadata.ExpiredAt)).Err(); err != nil {
-			logging.L(ctx).Error("fail cache file", logging.StringAttr("file", filename), logging.ErrAttr(err))
-		}
-	}
-	data, err = json.Marshal(file.Metadata)
+	s.cacheUploadedFile(ctx, filename, fileMetadata)
+
+	return filename, fileMetadata, nil
+}
+
+func (s *FileHostingCachedService) ImportFile(ctx context.Context, file string, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error) {
+	filename, fileMetadata, err := s.service.ImportFile(ctx, file, metadata, expiry)
 	if err != nil {
-		logging.L(ctx).Error("fail marshal file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
-	} else {
-		if err := s.rdb.Set(ctx, s.key("file", filename, "metadata"), data, s.ttlOfExpiredAt(file.Metadata.ExpiredAt)).Err(); err != nil {
-			logging.L(ctx).Error("fail cache file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
-		}
-	}
-	if err := s.rdb.Del(ctx, s.key("files")).Err(); err != nil {
-		logging.L(ctx).Error("fail delete files cache", logging.ErrAttr(err))
+		return "", nil, err
 	}
 
-	return filename, file, nil
+	s.cacheUploadedFile(ctx, filename, fileMetadata)
+
+	return filename, fileMetadata, nil
 }
 
 func (s *FileHostingCachedService) RenameFile(ctx context.Context, oldName string, newName string) error {
@@ -185,15 +125,48 @@ func (s *FileHostingCachedService) RenameFile(ctx context.Context, oldName strin
 	if err != nil {
 		return err
 	}
-	if err := s.rdb.Del(ctx, s.key("file", oldName)).Err(); err != nil {
-		logging.L(ctx).Error("fail delete file", logging.StringAttr("file", oldName), logging.ErrAttr(err))
-	}
 	if err := s.rdb.Del(ctx, s.key("file", oldName, "metadata")).Err(); err != nil {
 		logging.L(ctx).Error("fail delete file metadata", logging.StringAttr("file", oldName), logging.ErrAttr(err))
 	}
-	if err := s.rdb.Del(ctx, s.key("files")).Err(); err != nil {
-		logging.L(ctx).Error("fail delete files cache", logging.ErrAttr(err))
+	return nil
+}
+
+// UpdateExpiry drops the cached metadata, it is cached for no longer than
+// the file would live.
+func (s *FileHostingCachedService) UpdateExpiry(ctx context.Context, file string, expiry domain.FileExpiry) (*domain.FileMetadata, error) {
+	fileMetadata, err := s.service.UpdateExpiry(ctx, file, expiry)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rdb.Del(ctx, s.key("file", file, "metadata")).Err(); err != nil {
+		logging.L(ctx).Error("fail delete file metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
 	}
+	return fileMetadata, nil
+}
+
+// UpdateMeta drops the cached metadata.
+func (s *FileHostingCachedService) UpdateMeta(ctx context.Context, file string, meta map[string][]string) (*domain.FileMetadata, error) {
+	fileMetadata, err := s.service.UpdateMeta(ctx, file, meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rdb.Del(ctx, s.key("file", file, "metadata")).Err(); err != nil {
+		logging.L(ctx).Error("fail delete file metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
+	}
+	return fileMetadata, nil
+}
+
+// AuthorizeManagementToken reads the metadata from the cache.
+func (s *FileHostingCachedService) AuthorizeManagementToken(ctx context.Context, file string, token string) error {
+	metadata, err := s.GetFileMetadata(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	if !verifyToken(token, metadata.ManagementTokenHash) {
+		return apperr.ErrUnauthorized
+	}
+
 	return nil
 }
 
@@ -202,18 +175,80 @@ func (s *FileHostingCachedService) DeleteFile(ctx context.Context, file string)
 	if err != nil {
 		return err
 	}
-	if err := s.rdb.Del(ctx, s.key("file", file)).Err(); err != nil {
-		logging.L(ctx).Error("fail delete file", logging.StringAttr("file", file), logging.ErrAttr(err))
-	}
 	if err := s.rdb.Del(ctx, s.key("file", file, "metadata")).Err(); err != nil {
 		logging.L(ctx).Error("fail delete file metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
 	}
-	if err := s.rdb.Del(ctx, s.key("files")).Err(); err != nil {
-		logging.L(ctx).Error("fail delete files cache", logging.ErrAttr(err))
+	return nil
+}
+
+func (s *FileHostingCachedService) Reconcile(ctx context.Context, reschedule bool) (*domain.ReconcileReport, error) {
+	return s.service.Reconcile(ctx, reschedule)
+}
+
+func (s *FileHostingCachedService) GetTrashedFiles(ctx context.Context) ([]*domain.TrashedFile, error) {
+	return s.service.GetTrashedFiles(ctx)
+}
+
+func (s *FileHostingCachedService) RestoreTrashedFile(ctx context.Context, id string) (*domain.FileMetadata, error) {
+	fileMetadata, err := s.service.RestoreTrashedFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheUploadedFile(ctx, fileMetadata.Id, fileMetadata)
+
+	return fileMetadata, nil
+}
+
+func (s *FileHostingCachedService) GetFileVersions(ctx context.Context, file string) ([]*domain.FileVersion, error) {
+	return s.service.GetFileVersions(ctx, file)
+}
+
+func (s *FileHostingCachedService) GetFileVersion(ctx context.Context, file string, version string) (*domain.File, error) {
+	return s.service.GetFileVersion(ctx, file, version)
+}
+
+func (s *FileHostingCachedService) RestoreFileVersion(ctx context.Context, file string, version string) (*domain.FileMetadata, error) {
+	fileMetadata, err := s.service.RestoreFileVersion(ctx, file, version)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheUploadedFile(ctx, file, fileMetadata)
+
+	return fileMetadata, nil
+}
+
+// DeleteFileVersion drops the cached metadata, the backup of the file may
+// move to another version.
+func (s *FileHostingCachedService) DeleteFileVersion(ctx context.Context, file string, version string) error {
+	if err := s.service.DeleteFileVersion(ctx, file, version); err != nil {
+		return err
+	}
+	if err := s.rdb.Del(ctx, s.key("file", file, "metadata")).Err(); err != nil {
+		logging.L(ctx).Error("fail delete file metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
 	}
 	return nil
 }
 
+func (s *FileHostingCachedService) cacheUploadedFile(ctx context.Context, filename string, fileMetadata *domain.FileMetadata) {
+	if fileMetadata.IsDownloadLimited() {
+		if err := s.rdb.Del(ctx, s.key("file", filename, "metadata")).Err(); err != nil {
+			logging.L(ctx).Error("fail delete file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
+		}
+		return
+	}
+
+	data, err := json.Marshal(fileMetadata)
+	if err != nil {
+		logging.L(ctx).Error("fail marshal file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
+	} else {
+		if err := s.rdb.Set(ctx, s.key("file", filename, "metadata"), data, s.ttlOfExpiredAt(fileMetadata.ExpiredAt)).Err(); err != nil {
+			logging.L(ctx).Error("fail cache file metadata", logging.StringAttr("file", filename), logging.ErrAttr(err))
+		}
+	}
+}
+
 func (s *FileHostingCachedService) key(key ...string) string {
 	result := redisKeyPrefix
 	for _, k := range key {
@@ -222,14 +257,6 @@ func (s *FileHostingCachedService) key(key ...string) string {
 	return result
 }
 
-func (s *FileHostingCachedService) ttl(rawDuration string, allowInfinite ...bool) time.Duration {
-	duration := parseDuration(rawDuration, allowInfinite...)
-	if duration > defaultTTL {
-		duration = defaultTTL
-	}
-	return duration
-}
-
 func (s *FileHostingCachedService) ttlOfExpiredAt(expiredAt time.Time) time.Duration {
 	duration := time.Until(expiredAt)
 	if duration > defaultTTL {
@@ -2,16 +2,66 @@ package service
 
 import (
 	"context"
+	"io"
 
 	"github.com/bruhabruh/file-hosting/internal/domain"
 )
 
 type FileHostingService interface {
-	GetFiles(ctx context.Context) ([]*domain.FileMetadata, error)
+	// GetFiles returns a page of files matching query. Zero Sort and Limit
+	// are replaced by defaults.
+	GetFiles(ctx context.Context, query *domain.FileQuery) (*domain.FilePage, error)
+	// GetFile opens file without counting a download, e.g. to answer HEAD.
 	GetFile(ctx context.Context, file string) (*domain.File, error)
+	// DownloadFile opens file and counts the download of files with
+	// MaxDownloads. The last allowed download deletes the file once its
+	// content is closed, later ones fail with apperr.ErrGone.
+	DownloadFile(ctx context.Context, file string) (*domain.File, error)
+	GetFileRange(ctx context.Context, file string, offset int64, length int64) (io.ReadCloser, error)
+	// GetFileMetadata fails with apperr.ErrGone for files deleted after their
+	// last download.
 	GetFileMetadata(ctx context.Context, file string) (*domain.FileMetadata, error)
-	UploadFile(ctx context.Context, content []byte, metadata *domain.FileMetadata, rawDuration string) (string, *domain.File, error)
-	UploadFileWithGenerativeName(ctx context.Context, content []byte, metadata *domain.FileMetadata, rawDuration string) (string, *domain.File, error)
+	UploadFile(ctx context.Context, content io.Reader, size int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error)
+	// UploadFileWithGenerativeName stores the file under a generated id and
+	// returns its management token in ManagementToken of the metadata.
+	UploadFileWithGenerativeName(ctx context.Context, content io.Reader, size int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error)
+	// ImportFile stores the file already written to the storage, e.g. by a
+	// client through a presigned URL, under metadata.Name like UploadFile.
+	ImportFile(ctx context.Context, file string, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error)
 	RenameFile(ctx context.Context, oldName string, newName string) error
+	// UpdateExpiry sets a new expiry of file, counted from now, and schedules
+	// its deletion again. The file is not uploaded again, so CreatedAt and the
+	// versions are kept.
+	// Requests without an API key, authorized by a management token, are
+	// limited by the anonymous expiry policy. Management tokens are issued
+	// only for files with generated names, which are uploaded under that
+	// policy also with an API key.
+	UpdateExpiry(ctx context.Context, file string, expiry domain.FileExpiry) (*domain.FileMetadata, error)
+	// UpdateMeta replaces the custom metadata of file.
+	UpdateMeta(ctx context.Context, file string, meta map[string][]string) (*domain.FileMetadata, error)
+	// AuthorizeManagementToken rejects a token which is not the management
+	// token of file with apperr.ErrUnauthorized.
+	AuthorizeManagementToken(ctx context.Context, file string, token string) error
+	// DeleteFile deletes the current version of file, previous versions are
+	// kept and can be restored. With the trash enabled the file is moved to
+	// the trash, like files deleted on expiration.
 	DeleteFile(ctx context.Context, file string) error
+	// GetTrashedFiles lists files in the trash, recently deleted first.
+	GetTrashedFiles(ctx context.Context) ([]*domain.TrashedFile, error)
+	// RestoreTrashedFile moves the trashed file back under its id. Expired
+	// files are restored with the default duration.
+	RestoreTrashedFile(ctx context.Context, id string) (*domain.FileMetadata, error)
+	// GetFileVersions lists the versions of file newest first, starting with
+	// the current one while the file exists.
+	GetFileVersions(ctx context.Context, file string) ([]*domain.FileVersion, error)
+	GetFileVersion(ctx context.Context, file string, version string) (*domain.File, error)
+	// RestoreFileVersion uploads the content of version as the current
+	// version of file, the replaced one becomes a previous version.
+	RestoreFileVersion(ctx context.Context, file string, version string) (*domain.FileMetadata, error)
+	DeleteFileVersion(ctx context.Context, file string, version string) error
+	// Reconcile deletes expired files and, with reschedule, schedules the
+	// deletions of the other files and their versions again, in case their
+	// jobs were lost. Files without metadata and metadata without files are
+	// reported and left in place.
+	Reconcile(ctx context.Context, reschedule bool) (*domain.ReconcileReport, error)
 }
@@ -1,10 +1,15 @@
 package service
 
 import (
+	"bufio"
 	"context"
 	"crypto/sha1"
 	"fmt"
+	"io"
 	"math/rand/v2"
+	"net/http"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,397 +18,1345 @@ import (
 	"github.com/bruhabruh/file-hosting/internal/domain"
 	"github.com/bruhabruh/file-hosting/internal/storage"
 	"github.com/bruhabruh/file-hosting/pkg/logging"
-	"github.com/bruhabruh/file-hosting/pkg/rabbitmq"
-	"github.com/goccy/go-json"
-	"github.com/streadway/amqp"
 )
 
-const fileDeletionQueueName = "file-hosting-service/delete-file"
-
-var infiniteTimeStamp = time.Unix(0, 0)
-
-type deleteFileMessage struct {
-	FileName  string    `json:"fileName"`
-	Sha1      string    `json:"sha1"`
-	ExpiredAt time.Time `json:"expiredAt"`
-}
+const (
+	trashPurgeInterval     = 10 * time.Minute
+	tombstonePurgeInterval = 10 * time.Minute
+	// tombstoneReasonKey holds the reason in the meta of a tombstone.
+	tombstoneReasonKey = "reason"
+	// burnedTombstoneRetention is how long files deleted after their last
+	// download are answered with 410 when tombstones are disabled.
+	burnedTombstoneRetention = 7 * 24 * time.Hour
+)
 
 type FileHostingServiceImpl struct {
-	ctx         context.Context
-	fileStorage storage.FileStorage
-	mq          *rabbitmq.RabbitMQ
+	ctx           context.Context
+	fileStorage   storage.FileStorage
+	metadataStore storage.MetadataStore
+	scheduler     DeletionScheduler
+	downloads     DownloadCounter
+	// webhooks is nil when webhooks are disabled.
+	webhooks  WebhookNotifier
+	retention domain.VersionRetention
+	// trashRetention is how long deleted files are kept, 0 disables the trash.
+	trashRetention time.Duration
+	// tombstoneRetention is how long gone files are answered with 410, 0
+	// disables tombstones except those of files deleted after their last
+	// download.
+	tombstoneRetention time.Duration
+	expiry             domain.ExpiryPolicies
 }
 
-func NewFileHostingService(ctx context.Context, fileStorage storage.FileStorage, mq *rabbitmq.RabbitMQ) (FileHostingService, error) {
+func NewFileHostingService(ctx context.Context, fileStorage storage.FileStorage, metadataStore storage.MetadataStore, scheduler DeletionScheduler, downloads DownloadCounter, webhooks WebhookNotifier, retention domain.VersionRetention, trashRetention time.Duration, tombstoneRetention time.Duration, expiry domain.ExpiryPolicies) (FileHostingService, error) {
 	service := &FileHostingServiceImpl{
-		ctx:         ctx,
-		fileStorage: fileStorage,
-		mq:          mq,
+		ctx:                ctx,
+		fileStorage:        fileStorage,
+		metadataStore:      metadataStore,
+		scheduler:          scheduler,
+		downloads:          downloads,
+		webhooks:           webhooks,
+		retention:          retention,
+		trashRetention:     trashRetention,
+		tombstoneRetention: tombstoneRetention,
+		expiry:             expiry,
 	}
 
-	err := service.mq.DeclareQueue(fileDeletionQueueName)
-	if err != nil {
+	if err := service.scheduler.Consume(service.ctx, service.handleDeletionJob); err != nil {
 		return nil, err
 	}
 
-	service.mq.Consume(service.ctx, fileDeletionQueueName, service.handleDeleteFileMessage)
+	if service.trashRetention > 0 {
+		go service.purgeTrash()
+	}
+	go service.purgeTombstones()
 
 	return service, nil
 }
 
-func (s *FileHostingServiceImpl) GetFiles(ctx context.Context) ([]*domain.FileMetadata, error) {
-	fileNames, err := s.fileStorage.Files(ctx)
-	if err != nil {
-		return nil, err
+func (s *FileHostingServiceImpl) GetFiles(ctx context.Context, query *domain.FileQuery) (*domain.FilePage, error) {
+	if len(query.Sort) == 0 {
+		query.Sort = domain.FileSortCreatedAt
 	}
-
-	files := make([]*domain.FileMetadata, len(fileNames))
-	for i, fileName := range fileNames {
-		file, err := s.GetFileMetadata(ctx, fileName)
-		if err != nil {
-			return nil, err
-		}
-		files[i] = file
+	if !query.Sort.IsValid() {
+		return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Unknown sort %s", query.Sort))
+	}
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultFileQueryLimit
+	}
+	if query.Limit < 0 || query.Limit > domain.MaxFileQueryLimit {
+		return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Limit must be between 1 and %d", domain.MaxFileQueryLimit))
 	}
 
-	return files, nil
+	return s.metadataStore.Query(ctx, query)
 }
 
 func (s *FileHostingServiceImpl) GetFile(ctx context.Context, file string) (*domain.File, error) {
-	data, err := s.fileStorage.Read(ctx, file)
+	metadata, err := s.GetFileMetadata(ctx, file)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.openFile(ctx, metadata)
+}
+
+func (s *FileHostingServiceImpl) GetFileRange(ctx context.Context, file string, offset int64, length int64) (io.ReadCloser, error) {
+	return s.fileStorage.ReadRange(ctx, file, offset, length)
+}
+
+// DownloadFile opens file like GetFile and counts the download of files
+// with MaxDownloads. The file is deleted once its last download is closed.
+func (s *FileHostingServiceImpl) DownloadFile(ctx context.Context, file string) (*domain.File, error) {
 	metadata, err := s.GetFileMetadata(ctx, file)
 	if err != nil {
 		return nil, err
 	}
+	if !metadata.IsDownloadLimited() {
+		return s.openFile(ctx, metadata)
+	}
+
+	count, err := s.downloads.Increment(ctx, metadata)
+	if err != nil {
+		logging.L(ctx).Error("Fail count download", logging.StringAttr("file", file), logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail count download")
+	}
+	if count > metadata.MaxDownloads {
+		return nil, apperr.ErrGone.WithMessage(fmt.Sprintf("File %s reached its download limit", file))
+	}
+	remaining := metadata.MaxDownloads - count
+	metadata.RemainingDownloads = &remaining
+
+	opened, err := s.openFile(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if remaining == 0 {
+		opened.Content = &burnOnClose{ReadSeekCloser: opened.Content, burn: func() {
+			if err := s.burnFile(s.ctx, metadata); err != nil {
+				logging.L(s.ctx).Error("Fail delete file after its last download", logging.StringAttr("file", file), logging.ErrAttr(err))
+			}
+		}}
+	}
+
+	return opened, nil
+}
+
+func (s *FileHostingServiceImpl) openFile(ctx context.Context, metadata *domain.FileMetadata) (*domain.File, error) {
+	content, err := s.fileStorage.Read(ctx, metadata.Id)
+	if err != nil {
+		return nil, err
+	}
 
 	return &domain.File{
-		Content:  data,
+		Content:  content,
 		Metadata: metadata,
 	}, nil
 }
 
+// burnFile deletes the file after its last download. The trash is skipped,
+// nothing is kept of a file meant to be read a limited number of times.
+func (s *FileHostingServiceImpl) burnFile(ctx context.Context, metadata *domain.FileMetadata) error {
+	current, err := s.metadataStore.Get(ctx, metadata.Id)
+	if err != nil {
+		return err
+	}
+	if current.Sha1 != metadata.Sha1 || !current.CreatedAt.Equal(metadata.CreatedAt) {
+		// Uploaded again during the download
+		return nil
+	}
+
+	if err := s.fileStorage.Delete(ctx, metadata.Id); err != nil {
+		return err
+	}
+	if err := s.metadataStore.Delete(ctx, metadata.Id); err != nil {
+		return err
+	}
+	if err := s.downloads.Reset(ctx, metadata); err != nil {
+		logging.L(ctx).Error("Fail reset download count", logging.StringAttr("file", metadata.Id), logging.ErrAttr(err))
+	}
+	now := time.Now()
+	s.tombstoneFile(ctx, metadata.Id, domain.TombstoneDownloadLimit, now)
+	s.notify(ctx, &domain.WebhookEvent{Type: domain.WebhookFileDeleted, FileId: metadata.Id, Reason: domain.TombstoneDownloadLimit, OccurredAt: now, Metadata: metadata.View()})
+
+	logging.L(ctx).Info("Delete file after its last download", logging.StringAttr("file", metadata.Id))
+
+	return nil
+}
+
+// GetFileMetadata fills RemainingDownloads of files with MaxDownloads and
+// fails with apperr.ErrGone for files with a tombstone.
 func (s *FileHostingServiceImpl) GetFileMetadata(ctx context.Context, file string) (*domain.FileMetadata, error) {
-	data, err := s.fileStorage.Read(ctx, s.metadataFile(file))
+	metadata, err := s.metadataStore.Get(ctx, file)
 	if err != nil {
+		if apperr.From(err).Code() == http.StatusNotFound {
+			if tombstone := s.getTombstone(ctx, file); tombstone != nil {
+				return nil, apperr.ErrGone.WithMessage(tombstone.Message())
+			}
+			// The lookup of the tombstone replaced the message of err
+			return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
+		}
 		return nil, err
 	}
-	metadata, err := domain.NewFileMetadataFromBytes(data)
-	if err != nil {
-		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read metadata of file %s", file))
+
+	if metadata.IsDownloadLimited() {
+		count, err := s.downloads.Count(ctx, metadata)
+		if err != nil {
+			logging.L(ctx).Error("Fail read download count", logging.StringAttr("file", file), logging.ErrAttr(err))
+			return nil, apperr.ErrInternalServerError.WithMessage("Fail read download count")
+		}
+		remaining := max(metadata.MaxDownloads-count, 0)
+		metadata.RemainingDownloads = &remaining
 	}
+
 	return metadata, nil
 }
 
-func (s *FileHostingServiceImpl) UploadFile(ctx context.Context, content []byte, metadata *domain.FileMetadata, rawDuration string) (string, *domain.File, error) {
+func (s *FileHostingServiceImpl) UploadFile(ctx context.Context, content io.Reader, size int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error) {
 	if strings.Contains(metadata.Name, "/") {
 		return "", nil, apperr.ErrBadRequest.WithMessage("File name cannot contain '/'")
 	}
+	if metadata.MaxDownloads < 0 {
+		return "", nil, apperr.ErrBadRequest.WithMessage("Max downloads cannot be negative")
+	}
 
 	now := time.Now()
 
-	metadata.UpdateContentType(content)
-	expiredAt := infiniteTimeStamp
-	if duration := parseDuration(rawDuration, true); duration != 0 {
-		expiredAt = now.Add(duration)
+	expiredAt, err := resolveExpiredAt(s.expiry.Authorized, expiry, now)
+	if err != nil {
+		return "", nil, err
 	}
 
-	if s.fileStorage.IsExist(ctx, metadata.Name) {
-		oldFileData, err := s.fileStorage.Read(ctx, metadata.Name)
-		if err == nil {
-			newSha1 := s.sha1(content)
-			oldSha1 := s.sha1(oldFileData)
-			if newSha1 == oldSha1 {
-				metadata, _ := s.GetFileMetadata(ctx, metadata.Name)
-				return metadata.Name, &domain.File{
-					Content:  content,
-					Metadata: metadata,
-				}, nil
-			}
-		}
-		newFileName := fmt.Sprintf("%s.%d", metadata.Name, now.UnixNano())
-		newMetadataFileName := fmt.Sprintf("%s.%d.metadata", metadata.Name, now.UnixNano())
-
-		oldMetadata, _ := s.GetFileMetadata(ctx, metadata.Name)
-		if oldMetadata != nil && oldMetadata.ExpiredAt != infiniteTimeStamp {
-			err = s.scheduleDeleteFile(newFileName, oldMetadata.Sha1, oldMetadata.ExpiredAt)
-			if err != nil {
-				return "", nil, err
-			}
-		}
+	// Content is streamed to a temporary file first, so the current version
+	// keeps being served until the new one is completely stored.
+	uploadFileName := s.uploadFile(metadata.Name, now)
+	sha1, written, err := s.writeContent(ctx, uploadFileName, content, size, metadata)
+	if err != nil {
+		return "", nil, err
+	}
 
-		newMetadata := &domain.FileMetadata{
-			Id:         newFileName,
-			Name:       oldMetadata.Name,
-			MimeType:   oldMetadata.MimeType,
-			Sha1:       oldMetadata.Sha1,
-			Meta:       oldMetadata.Meta,
-			CreatedAt:  oldMetadata.CreatedAt,
-			ExpiredAt:  oldMetadata.ExpiredAt,
-			BackupName: oldMetadata.BackupName,
+	name, newMetadata, err := s.commitFile(ctx, uploadFileName, sha1, written, metadata, expiredAt, now)
+	if err != nil {
+		if s.fileStorage.IsExist(ctx, uploadFileName) {
+			s.fileStorage.Delete(ctx, uploadFileName)
 		}
+		return "", nil, err
+	}
 
-		if err := s.fileStorage.Move(ctx, metadata.Name, newFileName); err != nil {
-			return "", nil, err
-		}
-		if s.fileStorage.IsExist(ctx, s.metadataFile(metadata.Name)) {
-			err = s.fileStorage.Delete(ctx, s.metadataFile(metadata.Name))
-			if err != nil {
-				return "", nil, err
-			}
+	return name, newMetadata, nil
+}
 
-			metadataInBytes, err := json.Marshal(newMetadata)
-			if err != nil {
-				return "", nil, apperr.ErrInternalServerError.WithMessage("Fail serialize old metadata")
-			}
-			err = s.fileStorage.Write(ctx, newMetadataFileName, metadataInBytes, "application/json")
-			if err != nil {
-				return "", nil, err
-			}
-		}
-		metadata.BackupName = newFileName
+func (s *FileHostingServiceImpl) ImportFile(ctx context.Context, file string, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error) {
+	if strings.Contains(metadata.Name, "/") {
+		return "", nil, apperr.ErrBadRequest.WithMessage("File name cannot contain '/'")
 	}
+	if metadata.MaxDownloads < 0 {
+		return "", nil, apperr.ErrBadRequest.WithMessage("Max downloads cannot be negative")
+	}
+	if !s.fileStorage.IsExist(ctx, file) {
+		return "", nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
+	}
+
+	now := time.Now()
 
-	newMetadata := &domain.FileMetadata{
-		Id:         metadata.Name,
-		Name:       metadata.Name,
-		MimeType:   metadata.MimeType,
-		Sha1:       s.sha1(content),
-		Meta:       metadata.Meta,
-		CreatedAt:  now,
-		ExpiredAt:  expiredAt,
-		BackupName: metadata.BackupName,
+	expiredAt, err := resolveExpiredAt(s.expiry.Authorized, expiry, now)
+	if err != nil {
+		return "", nil, err
 	}
 
-	metadataInBytes, err := json.Marshal(newMetadata)
+	sha1, size, err := s.hashContent(ctx, file, metadata)
 	if err != nil {
-		return "", nil, apperr.ErrInternalServerError.WithMessage("Fail serialize metadata")
+		return "", nil, err
+	}
+
+	return s.commitFile(ctx, file, sha1, size, metadata, expiredAt, now)
+}
+
+// commitFile moves the completely stored uploadFileName to metadata.Name,
+// keeping the current version of the file as a previous version.
+// uploadFileName is dropped when its content is the same as the current version.
+func (s *FileHostingServiceImpl) commitFile(ctx context.Context, uploadFileName string, sha1 string, size int64, metadata *domain.FileMetadata, expiredAt time.Time, now time.Time) (string, *domain.FileMetadata, error) {
+	var err error
+
+	eventType := domain.WebhookFileUploaded
+	if s.fileStorage.IsExist(ctx, metadata.Name) {
+		eventType = domain.WebhookFileOverwritten
+		oldMetadata, _ := s.metadataStore.Get(ctx, metadata.Name)
+		if oldMetadata != nil && oldMetadata.Sha1 == sha1 {
+			s.fileStorage.Delete(ctx, uploadFileName)
+			return oldMetadata.Name, oldMetadata, nil
+		}
+
+		versionFileName, err := s.archiveFile(ctx, metadata.Name, oldMetadata, now)
+		if err != nil {
+			return "", nil, err
+		}
+		metadata.BackupName = versionFileName
 	}
 
-	if newMetadata.ExpiredAt != infiniteTimeStamp {
-		err = s.scheduleDeleteFile(newMetadata.Name, newMetadata.Sha1, newMetadata.ExpiredAt)
+	newMetadata := metadata.Clone()
+	newMetadata.Id = metadata.Name
+	newMetadata.Sha1 = sha1
+	newMetadata.Size = size
+	newMetadata.CreatedAt = now
+	newMetadata.ExpiredAt = expiredAt
+
+	if !newMetadata.IsPermanent() {
+		err = s.scheduleDeleteFile(ctx, newMetadata.Name, newMetadata.Sha1, newMetadata.ExpiredAt)
 		if err != nil {
 			return "", nil, err
 		}
 	}
 
-	err = s.fileStorage.Write(ctx, newMetadata.Name, content, newMetadata.MimeType)
+	err = s.fileStorage.Move(ctx, uploadFileName, newMetadata.Name)
 	if err != nil {
 		return "", nil, err
 	}
 
-	err = s.fileStorage.Write(ctx, s.metadataFile(newMetadata.Name), metadataInBytes, "application/json")
+	err = s.metadataStore.Put(ctx, newMetadata)
 	if err != nil {
 		s.fileStorage.Delete(ctx, newMetadata.Name)
 		return "", nil, err
 	}
 
-	return newMetadata.Name, &domain.File{
-		Content:  content,
-		Metadata: newMetadata,
-	}, nil
+	if len(newMetadata.BackupName) > 0 {
+		s.pruneVersions(ctx, newMetadata.Name)
+	}
+
+	if !newMetadata.IsPermanent() {
+		s.scheduleExpiringFile(ctx, newMetadata.Name, newMetadata.Sha1, newMetadata.ExpiredAt)
+	}
+	s.notify(ctx, &domain.WebhookEvent{Type: eventType, FileId: newMetadata.Id, OccurredAt: now, Metadata: newMetadata.View()})
+
+	return newMetadata.Name, newMetadata, nil
 }
 
-func (s *FileHostingServiceImpl) UploadFileWithGenerativeName(ctx context.Context, content []byte, metadata *domain.FileMetadata, rawDuration string) (string, *domain.File, error) {
-	fileName := s.generateFileName()
-	for {
-		if s.fileStorage.IsExist(ctx, fileName) {
-			fileName = s.generateFileName()
-			continue
+// archiveFile moves the current version of file to the versions of the file
+// and returns its new name. The version is deleted when the file would have
+// expired or when it is older than the retention allows.
+func (s *FileHostingServiceImpl) archiveFile(ctx context.Context, file string, metadata *domain.FileMetadata, now time.Time) (string, error) {
+	if metadata == nil {
+		// Files stored without metadata cannot be listed as versions
+		versionFileName := s.versionFile(file, strconv.FormatInt(now.UnixNano(), 10))
+		return versionFileName, s.fileStorage.Move(ctx, file, versionFileName)
+	}
+
+	versionFileName := s.versionFile(file, versionOf(metadata))
+
+	deleteAt := s.versionDeleteAt(metadata, now)
+	if !deleteAt.Equal(domain.PermanentExpiredAt) {
+		if err := s.scheduleDeleteFile(ctx, versionFileName, metadata.Sha1, deleteAt); err != nil {
+			return "", err
 		}
-		break
 	}
 
-	now := time.Now()
-	expiredAt := now.Add(parseDuration(rawDuration))
+	if err := s.fileStorage.Move(ctx, file, versionFileName); err != nil {
+		return "", err
+	}
+
+	versionMetadata := metadata.Clone()
+	versionMetadata.Id = versionFileName
 
-	metadata.UpdateContentType(content)
+	if err := s.metadataStore.Delete(ctx, file); err != nil {
+		return "", err
+	}
+	if err := s.metadataStore.Put(ctx, versionMetadata); err != nil {
+		return "", err
+	}
+
+	return versionFileName, nil
+}
+
+// versionDeleteAt is when the version archived at archivedAt is deleted by
+// the retention or on expiration, domain.PermanentExpiredAt for never.
+func (s *FileHostingServiceImpl) versionDeleteAt(metadata *domain.FileMetadata, archivedAt time.Time) time.Time {
+	deleteAt := metadata.ExpiredAt
+	if s.retention.KeepFor > 0 {
+		retainedUntil := archivedAt.Add(s.retention.KeepFor)
+		if metadata.IsPermanent() || retainedUntil.Before(deleteAt) {
+			deleteAt = retainedUntil
+		}
+	}
+	return deleteAt
+}
 
-	newMetadata := &domain.FileMetadata{
-		Id:         fileName,
-		Name:       metadata.Name,
-		MimeType:   metadata.MimeType,
-		Sha1:       s.sha1(content),
-		Meta:       metadata.Meta,
-		CreatedAt:  now,
-		ExpiredAt:  expiredAt,
-		BackupName: metadata.BackupName,
+// pruneVersions deletes the oldest versions of file above the retention count.
+func (s *FileHostingServiceImpl) pruneVersions(ctx context.Context, file string) {
+	if s.retention.KeepLast <= 0 {
+		return
 	}
 
-	metadataInBytes, err := json.Marshal(newMetadata)
+	versions, err := s.listVersions(ctx, file)
 	if err != nil {
-		return "", nil, apperr.ErrInternalServerError.WithMessage("Fail serialize metadata")
+		logging.L(ctx).Error("Fail list versions", logging.StringAttr("file", file), logging.ErrAttr(err))
+		return
+	}
+
+	for _, version := range versions[min(s.retention.KeepLast, len(versions)):] {
+		if err := s.deleteVersion(ctx, version.Id); err != nil {
+			logging.L(ctx).Error("Fail delete version", logging.StringAttr("file", version.Id), logging.ErrAttr(err))
+			continue
+		}
+		logging.L(ctx).Info("Delete version", logging.StringAttr("file", version.Id))
+	}
+}
+
+func (s *FileHostingServiceImpl) UploadFileWithGenerativeName(ctx context.Context, content io.Reader, size int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (string, *domain.FileMetadata, error) {
+	if metadata.MaxDownloads < 0 {
+		return "", nil, apperr.ErrBadRequest.WithMessage("Max downloads cannot be negative")
 	}
 
-	err = s.scheduleDeleteFile(fileName, newMetadata.Sha1, newMetadata.ExpiredAt)
+	now := time.Now()
+	expiredAt, err := resolveExpiredAt(s.expiry.Anonymous, expiry, now)
 	if err != nil {
 		return "", nil, err
 	}
 
-	err = s.fileStorage.Write(ctx, fileName, content, newMetadata.MimeType)
+	// Ids of gone files are not reused while their tombstones are kept, so
+	// their links keep answering 410
+	fileName := s.generateFileName()
+	for {
+		if s.fileStorage.IsExist(ctx, fileName) || s.getTombstone(ctx, fileName) != nil {
+			fileName = s.generateFileName()
+			continue
+		}
+		break
+	}
+
+	sha1, written, err := s.writeContent(ctx, fileName, content, size, metadata)
 	if err != nil {
 		return "", nil, err
 	}
 
-	err = s.fileStorage.Write(ctx, s.metadataFile(fileName), metadataInBytes, "application/json")
+	token, tokenHash, err := generateToken()
+	if err != nil {
+		s.fileStorage.Delete(ctx, fileName)
+		return "", nil, apperr.ErrInternalServerError.WithMessage("Fail generate management token")
+	}
+
+	newMetadata := metadata.Clone()
+	newMetadata.Id = fileName
+	newMetadata.Sha1 = sha1
+	newMetadata.Size = written
+	newMetadata.CreatedAt = now
+	newMetadata.ExpiredAt = expiredAt
+	newMetadata.ManagementTokenHash = tokenHash
+
+	if !newMetadata.IsPermanent() {
+		err = s.scheduleDeleteFile(ctx, fileName, newMetadata.Sha1, newMetadata.ExpiredAt)
+		if err != nil {
+			s.fileStorage.Delete(ctx, fileName)
+			return "", nil, err
+		}
+	}
+
+	err = s.metadataStore.Put(ctx, newMetadata)
 	if err != nil {
 		s.fileStorage.Delete(ctx, fileName)
 		return "", nil, err
 	}
 
-	return fileName, &domain.File{
-		Content:  content,
-		Metadata: newMetadata,
-	}, nil
+	if !newMetadata.IsPermanent() {
+		s.scheduleExpiringFile(ctx, fileName, newMetadata.Sha1, newMetadata.ExpiredAt)
+	}
+	s.notify(ctx, &domain.WebhookEvent{Type: domain.WebhookFileUploaded, FileId: fileName, OccurredAt: now, Metadata: newMetadata.View()})
+
+	newMetadata.ManagementToken = token
+
+	return fileName, newMetadata, nil
 }
 
 func (s *FileHostingServiceImpl) RenameFile(ctx context.Context, oldName string, newName string) error {
-	_, err := s.fileStorage.Read(ctx, oldName)
-	if err != nil {
+	if strings.Contains(newName, "/") {
+		return apperr.ErrBadRequest.WithMessage("File name cannot contain '/'")
+	}
+	if !s.fileStorage.IsExist(ctx, oldName) {
 		return apperr.ErrInternalServerError.WithMessage("Fail read old file. Maybe it was deleted")
 	}
-	newMetadataFileName := s.metadataFile(newName)
+	var err error
 
-	oldMetadata, _ := s.GetFileMetadata(ctx, oldName)
-	if oldMetadata != nil && oldMetadata.ExpiredAt != infiniteTimeStamp {
-		err = s.scheduleDeleteFile(newName, oldMetadata.Sha1, oldMetadata.ExpiredAt)
+	oldMetadata, _ := s.metadataStore.Get(ctx, oldName)
+	if oldMetadata != nil && !oldMetadata.IsPermanent() {
+		err = s.scheduleDeleteFile(ctx, newName, oldMetadata.Sha1, oldMetadata.ExpiredAt)
 		if err != nil {
 			return err
 		}
 	}
 
-	newMetadata := &domain.FileMetadata{
-		Id:         newName,
-		Name:       oldMetadata.Name,
-		MimeType:   oldMetadata.MimeType,
-		Sha1:       oldMetadata.Sha1,
-		Meta:       oldMetadata.Meta,
-		CreatedAt:  oldMetadata.CreatedAt,
-		ExpiredAt:  oldMetadata.ExpiredAt,
-		BackupName: oldMetadata.BackupName,
+	var newMetadata *domain.FileMetadata
+	if oldMetadata != nil {
+		newMetadata = oldMetadata.Clone()
+		newMetadata.Id = newName
 	}
 
 	if err := s.fileStorage.Move(ctx, oldName, newName); err != nil {
 		return err
 	}
-	if s.fileStorage.IsExist(ctx, s.metadataFile(oldName)) {
-		err = s.fileStorage.Delete(ctx, s.metadataFile(oldName))
+	if oldMetadata != nil {
+		err = s.metadataStore.Delete(ctx, oldName)
 		if err != nil {
 			return err
 		}
 
-		metadataInBytes, err := json.Marshal(newMetadata)
-		if err != nil {
-			return apperr.ErrInternalServerError.WithMessage("Fail serialize old metadata")
-		}
-		err = s.fileStorage.Write(ctx, newMetadataFileName, metadataInBytes, "application/json")
+		err = s.metadataStore.Put(ctx, newMetadata)
 		if err != nil {
 			return err
 		}
+
+		if !newMetadata.IsPermanent() {
+			s.scheduleExpiringFile(ctx, newName, newMetadata.Sha1, newMetadata.ExpiredAt)
+		}
 	}
 
+	s.notify(ctx, &domain.WebhookEvent{Type: domain.WebhookFileRenamed, FileId: newName, OldFileId: oldName, Metadata: newMetadata.View()})
+
 	return nil
 }
 
-func (s *FileHostingServiceImpl) DeleteFile(ctx context.Context, fileName string) error {
-	if err := s.fileStorage.Delete(ctx, fileName); err != nil {
-		return apperr.ErrInternalServerError.WithMessage("Fail delete file")
+func (s *FileHostingServiceImpl) UpdateExpiry(ctx context.Context, file string, expiry domain.FileExpiry) (*domain.FileMetadata, error) {
+	if strings.Contains(file, "/") {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
 	}
 
-	if err := s.fileStorage.Delete(ctx, s.metadataFile(fileName)); err != nil {
-		return apperr.ErrInternalServerError.WithMessage("Fail delete file metadata")
+	metadata, err := s.metadataStore.Get(ctx, file)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	policy := s.expiry.Authorized
+	if APIKeyFromContext(ctx) == nil {
+		policy = s.expiry.Anonymous
+	}
+
+	expiredAt, err := resolveExpiredAt(policy, expiry, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	updatedMetadata := metadata.Clone()
+	updatedMetadata.ExpiredAt = expiredAt
+
+	// Jobs of the previous expiry are dropped when handled, as they do not
+	// match the new one
+	if !updatedMetadata.IsPermanent() {
+		if err := s.scheduleDeleteFile(ctx, updatedMetadata.Id, updatedMetadata.Sha1, updatedMetadata.ExpiredAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.metadataStore.Put(ctx, updatedMetadata); err != nil {
+		return nil, err
+	}
+
+	if !updatedMetadata.IsPermanent() {
+		s.scheduleExpiringFile(ctx, updatedMetadata.Id, updatedMetadata.Sha1, updatedMetadata.ExpiredAt)
+	}
+
+	return updatedMetadata, nil
 }
 
-func (s *FileHostingServiceImpl) scheduleDeleteFile(fileName string, sha1 string, expiredAt time.Time) error {
-	msg := deleteFileMessage{
-		FileName:  fileName,
-		Sha1:      sha1,
-		ExpiredAt: expiredAt,
+func (s *FileHostingServiceImpl) UpdateMeta(ctx context.Context, file string, meta map[string][]string) (*domain.FileMetadata, error) {
+	if strings.Contains(file, "/") {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
 	}
 
-	bytes, err := json.Marshal(msg)
+	metadata, err := s.metadataStore.Get(ctx, file)
 	if err != nil {
-		return apperr.ErrInternalServerError.WithMessage("Failed to marshal delete message")
+		return nil, err
 	}
 
-	err = s.mq.Publish(fileDeletionQueueName, bytes)
-	if err != nil {
-		return apperr.ErrInternalServerError.WithMessage("Fail schedule file deletion")
+	if meta == nil {
+		meta = make(map[string][]string)
 	}
 
-	return nil
+	updatedMetadata := metadata.Clone()
+	updatedMetadata.Meta = meta
+
+	if err := s.metadataStore.Put(ctx, updatedMetadata); err != nil {
+		return nil, err
+	}
+
+	return updatedMetadata, nil
 }
 
-func (s *FileHostingServiceImpl) handleDeleteFileMessage(msg amqp.Delivery) {
-	var delMsg deleteFileMessage
-	if err := json.Unmarshal(msg.Body, &delMsg); err != nil {
-		logging.L(s.ctx).Error("Failed to unmarshal delete file message", logging.ErrAttr(err))
-		msg.Nack(false, false)
-		return
+func (s *FileHostingServiceImpl) AuthorizeManagementToken(ctx context.Context, file string, token string) error {
+	metadata, err := s.GetFileMetadata(ctx, file)
+	if err != nil {
+		return err
 	}
 
-	if time.Now().Before(delMsg.ExpiredAt) {
-		msg.Nack(false, true)
-		return
+	if !verifyToken(token, metadata.ManagementTokenHash) {
+		return apperr.ErrUnauthorized
 	}
 
-	metadata, err := s.GetFileMetadata(s.ctx, delMsg.FileName)
+	return nil
+}
+
+func (s *FileHostingServiceImpl) DeleteFile(ctx context.Context, fileName string) error {
+	now := time.Now()
+
+	metadata, err := s.metadataStore.Get(ctx, fileName)
 	if err != nil {
-		logging.L(s.ctx).Warn("Failed to read metadata", logging.ErrAttr(err))
-		msg.Ack(false)
-		return
+		metadata = nil
 	}
-	if metadata.Sha1 != delMsg.Sha1 {
-		logging.L(s.ctx).Warn("SHA1 mismatch", logging.ErrAttr(err))
-		msg.Nack(false, false)
-		return
+	event := &domain.WebhookEvent{Type: domain.WebhookFileDeleted, FileId: fileName, Reason: domain.TombstoneDeleted, OccurredAt: now, Metadata: metadata.View()}
+
+	if s.trashRetention > 0 && metadata != nil {
+		if err := s.trashFile(ctx, metadata, now); err != nil {
+			return err
+		}
+		s.tombstoneFile(ctx, fileName, domain.TombstoneDeleted, now)
+		s.notify(ctx, event)
+		return nil
 	}
 
-	if time.Now().Before(metadata.ExpiredAt) {
-		msg.Nack(false, true)
-		return
+	if err := s.fileStorage.Delete(ctx, fileName); err != nil {
+		return apperr.ErrInternalServerError.WithMessage("Fail delete file")
 	}
 
-	err = s.fileStorage.Delete(s.ctx, delMsg.FileName)
-	if err != nil {
-		logging.L(s.ctx).Error("Failed to delete file", logging.ErrAttr(err))
-		msg.Nack(false, true)
-		return
+	if err := s.metadataStore.Delete(ctx, fileName); err != nil {
+		return apperr.ErrInternalServerError.WithMessage("Fail delete file metadata")
 	}
 
-	err = s.fileStorage.Delete(s.ctx, s.metadataFile(delMsg.FileName))
+	s.tombstoneFile(ctx, fileName, domain.TombstoneDeleted, now)
+	s.notify(ctx, event)
+
+	return nil
+}
+
+func (s *FileHostingServiceImpl) GetTrashedFiles(ctx context.Context) ([]*domain.TrashedFile, error) {
+	files, err := s.metadataStore.ListIn(ctx, storage.TrashDirectory)
 	if err != nil {
-		logging.L(s.ctx).Error("Failed to delete metadata file", logging.ErrAttr(err))
+		return nil, err
 	}
 
-	msg.Ack(false)
+	trashedFiles := make([]*domain.TrashedFile, 0, len(files))
+	for _, file := range files {
+		trashedFile, ok := s.toTrashedFile(file)
+		if !ok {
+			continue
+		}
+		trashedFiles = append(trashedFiles, trashedFile)
+	}
 
-	logging.L(s.ctx).Info("Delete file", logging.StringAttr("file", delMsg.FileName))
-}
+	slices.SortFunc(trashedFiles, func(a, b *domain.TrashedFile) int {
+		return b.DeletedAt.Compare(a.DeletedAt)
+	})
 
-func (s *FileHostingServiceImpl) metadataFile(file string) string {
-	return fmt.Sprintf("%s.metadata", file)
+	return trashedFiles, nil
 }
 
-func (s *FileHostingServiceImpl) generateFileName() string {
-	now := time.Now().UnixMilli()
+func (s *FileHostingServiceImpl) RestoreTrashedFile(ctx context.Context, id string) (*domain.FileMetadata, error) {
+	notFound := apperr.ErrNotFound.WithMessage(fmt.Sprintf("Trashed file %s not found", id))
+	if strings.Contains(id, "/") {
+		return nil, notFound
+	}
 
-	timePart := strconv.FormatInt(now, 36)
-	if len(timePart) > 4 {
-		timePart = timePart[len(timePart)-4:]
+	metadata, err := s.metadataStore.Get(ctx, s.trashFileName(id))
+	if err != nil {
+		return nil, notFound
+	}
+	trashedFile, ok := s.toTrashedFile(metadata)
+	if !ok {
+		return nil, notFound
 	}
 
-	randPart := fmt.Sprintf("%x", rand.Int32N(0x10000))
-	return timePart + randPart
-}
+	if s.fileStorage.IsExist(ctx, trashedFile.FileId) {
+		return nil, apperr.ErrConflict.WithMessage(fmt.Sprintf("File %s already exist", trashedFile.FileId))
+	}
+
+	restoredMetadata := metadata.Clone()
+	restoredMetadata.Id = trashedFile.FileId
+
+	// Expired files get the default duration, otherwise they would be
+	// trashed again right away
+	now := time.Now()
+	if !restoredMetadata.IsPermanent() && now.After(restoredMetadata.ExpiredAt) {
+		restoredMetadata.ExpiredAt = now.Add(s.expiry.Authorized.Default)
+	}
+	if !restoredMetadata.IsPermanent() {
+		if err := s.scheduleDeleteFile(ctx, restoredMetadata.Id, restoredMetadata.Sha1, restoredMetadata.ExpiredAt); err != nil {
+			return nil, err
+		}
+	}
 
-func (s *FileHostingServiceImpl) sha1(data []byte) string {
-	hash := sha1.Sum(data)
-	return fmt.Sprintf("%x", hash)
+	if err := s.fileStorage.Move(ctx, metadata.Id, restoredMetadata.Id); err != nil {
+		return nil, err
+	}
+	if err := s.metadataStore.Put(ctx, restoredMetadata); err != nil {
+		return nil, err
+	}
+	if err := s.metadataStore.Delete(ctx, metadata.Id); err != nil {
+		return nil, err
+	}
+	s.dropTombstone(ctx, restoredMetadata.Id)
+	if !restoredMetadata.IsPermanent() {
+		s.scheduleExpiringFile(ctx, restoredMetadata.Id, restoredMetadata.Sha1, restoredMetadata.ExpiredAt)
+	}
+
+	return restoredMetadata, nil
+}
+
+// trashFile moves file described by metadata to the trash, where it is kept
+// for the trash retention.
+func (s *FileHostingServiceImpl) trashFile(ctx context.Context, metadata *domain.FileMetadata, now time.Time) error {
+	trashedMetadata := metadata.Clone()
+	trashedMetadata.Id = s.trashFileName(fmt.Sprintf("%s.%d", metadata.Id, now.UnixNano()))
+
+	if err := s.fileStorage.Move(ctx, metadata.Id, trashedMetadata.Id); err != nil {
+		return err
+	}
+	if err := s.metadataStore.Put(ctx, trashedMetadata); err != nil {
+		return err
+	}
+
+	return s.metadataStore.Delete(ctx, metadata.Id)
+}
+
+// purgeTrash permanently deletes trashed files after the trash retention.
+func (s *FileHostingServiceImpl) purgeTrash() {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		trashedFiles, err := s.GetTrashedFiles(s.ctx)
+		if err != nil {
+			logging.L(s.ctx).Error("Fail list trashed files", logging.ErrAttr(err))
+			continue
+		}
+
+		now := time.Now()
+		for _, trashedFile := range trashedFiles {
+			if now.Before(trashedFile.PurgeAt) {
+				continue
+			}
+
+			file := s.trashFileName(trashedFile.Id)
+			if err := s.fileStorage.Delete(s.ctx, file); err != nil {
+				logging.L(s.ctx).Error("Fail purge trashed file", logging.StringAttr("file", file), logging.ErrAttr(err))
+				continue
+			}
+			if err := s.metadataStore.Delete(s.ctx, file); err != nil {
+				logging.L(s.ctx).Error("Fail purge trashed file metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
+				continue
+			}
+			logging.L(s.ctx).Info("Purge trashed file", logging.StringAttr("file", file))
+		}
+	}
+}
+
+// toTrashedFile describes the trashed file by its metadata, whose id is the
+// trash file name trash/<file id>.<unix nano of deletion>.
+func (s *FileHostingServiceImpl) toTrashedFile(metadata *domain.FileMetadata) (*domain.TrashedFile, bool) {
+	id := path.Base(metadata.Id)
+	dot := strings.LastIndex(id, ".")
+	if dot <= 0 {
+		return nil, false
+	}
+	deletedAtNano, err := strconv.ParseInt(id[dot+1:], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	deletedAt := time.Unix(0, deletedAtNano)
+	return &domain.TrashedFile{
+		Id:        id,
+		FileId:    id[:dot],
+		DeletedAt: deletedAt,
+		PurgeAt:   deletedAt.Add(s.trashRetention),
+		Metadata:  metadata,
+	}, true
+}
+
+// tombstoneFile records that file is gone for reason since goneAt, so it is
+// answered with 410 for the tombstone retention. Tombstones are metadata
+// without content kept as tombstones/<file id>. Files deleted after their
+// last download are always tombstoned, a burned link must not look like a
+// mistyped one.
+func (s *FileHostingServiceImpl) tombstoneFile(ctx context.Context, file string, reason domain.TombstoneReason, goneAt time.Time) {
+	retention := s.tombstoneRetention
+	if retention <= 0 {
+		if reason != domain.TombstoneDownloadLimit {
+			return
+		}
+		retention = burnedTombstoneRetention
+	}
+
+	tombstone := &domain.FileMetadata{
+		Id:        s.tombstoneFileName(file),
+		Name:      file,
+		Meta:      map[string][]string{tombstoneReasonKey: {string(reason)}},
+		CreatedAt: goneAt,
+		ExpiredAt: goneAt.Add(retention),
+	}
+	if err := s.metadataStore.Put(ctx, tombstone); err != nil {
+		logging.L(ctx).Error("Fail record tombstone", logging.StringAttr("file", file), logging.ErrAttr(err))
+	}
+}
+
+// getTombstone returns the tombstone of file, nil when it has none or it is
+// past the retention.
+func (s *FileHostingServiceImpl) getTombstone(ctx context.Context, file string) *domain.Tombstone {
+	if strings.Contains(file, "/") {
+		return nil
+	}
+
+	metadata, err := s.metadataStore.Get(ctx, s.tombstoneFileName(file))
+	if err != nil {
+		if apperr.From(err).Code() != http.StatusNotFound {
+			logging.L(ctx).Error("Fail read tombstone", logging.StringAttr("file", file), logging.ErrAttr(err))
+		}
+		return nil
+	}
+
+	tombstone := toTombstone(metadata)
+	if !time.Now().Before(tombstone.PurgeAt) {
+		return nil
+	}
+	return tombstone
+}
+
+func (s *FileHostingServiceImpl) dropTombstone(ctx context.Context, file string) {
+	if err := s.metadataStore.Delete(ctx, s.tombstoneFileName(file)); err != nil && apperr.From(err).Code() != http.StatusNotFound {
+		logging.L(ctx).Error("Fail delete tombstone", logging.StringAttr("file", file), logging.ErrAttr(err))
+	}
+}
+
+func (s *FileHostingServiceImpl) purgeTombstones() {
+	ticker := time.NewTicker(tombstonePurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		tombstones, err := s.metadataStore.ListIn(s.ctx, storage.TombstoneDirectory)
+		if err != nil {
+			logging.L(s.ctx).Error("Fail list tombstones", logging.ErrAttr(err))
+			continue
+		}
+
+		now := time.Now()
+		for _, metadata := range tombstones {
+			if now.Before(metadata.ExpiredAt) {
+				continue
+			}
+			if err := s.metadataStore.Delete(s.ctx, metadata.Id); err != nil {
+				logging.L(s.ctx).Error("Fail purge tombstone", logging.StringAttr("file", metadata.Id), logging.ErrAttr(err))
+			}
+		}
+	}
+}
+
+func toTombstone(metadata *domain.FileMetadata) *domain.Tombstone {
+	tombstone := &domain.Tombstone{
+		FileId:  path.Base(metadata.Id),
+		Reason:  domain.TombstoneDeleted,
+		GoneAt:  metadata.CreatedAt,
+		PurgeAt: metadata.ExpiredAt,
+	}
+	if reason := metadata.Meta[tombstoneReasonKey]; len(reason) > 0 {
+		tombstone.Reason = domain.TombstoneReason(reason[0])
+	}
+	return tombstone
+}
+
+func (s *FileHostingServiceImpl) GetFileVersions(ctx context.Context, file string) ([]*domain.FileVersion, error) {
+	if strings.Contains(file, "/") {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
+	}
+
+	versions, err := s.listVersions(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.FileVersion, 0, len(versions)+1)
+	if current, err := s.metadataStore.Get(ctx, file); err == nil {
+		result = append(result, &domain.FileVersion{Version: versionOf(current), Current: true, Metadata: current})
+	}
+	for _, version := range versions {
+		result = append(result, &domain.FileVersion{Version: path.Base(version.Id), Metadata: version})
+	}
+
+	if len(result) == 0 {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
+	}
+
+	return result, nil
+}
+
+func (s *FileHostingServiceImpl) GetFileVersion(ctx context.Context, file string, version string) (*domain.File, error) {
+	metadata, err := s.getVersion(ctx, file, version)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.fileStorage.Read(ctx, metadata.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.File{
+		Content:  content,
+		Metadata: metadata,
+	}, nil
+}
+
+func (s *FileHostingServiceImpl) RestoreFileVersion(ctx context.Context, file string, version string) (*domain.FileMetadata, error) {
+	versionMetadata, err := s.getVersion(ctx, file, version)
+	if err != nil {
+		return nil, err
+	}
+	if versionMetadata.Id == file {
+		return versionMetadata, nil
+	}
+
+	now := time.Now()
+	if !versionMetadata.IsPermanent() && now.After(versionMetadata.ExpiredAt) {
+		return nil, apperr.ErrGone.WithMessage(fmt.Sprintf("Version %s of file %s expired", version, file))
+	}
+
+	content, err := s.fileStorage.Read(ctx, versionMetadata.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	metadata := &domain.FileMetadata{
+		Name:                versionMetadata.Name,
+		MimeType:            versionMetadata.MimeType,
+		Meta:                versionMetadata.Meta,
+		MaxDownloads:        versionMetadata.MaxDownloads,
+		Owner:               versionMetadata.Owner,
+		ManagementTokenHash: versionMetadata.ManagementTokenHash,
+		Private:             versionMetadata.Private,
+		PasswordHash:        versionMetadata.PasswordHash,
+	}
+
+	// The content is copied, so the restored version stays in the history
+	// and the current one becomes a previous version
+	uploadFileName := s.uploadFile(file, now)
+	sha1, written, err := s.writeContent(ctx, uploadFileName, content, versionMetadata.Size, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.Name = file
+	_, newMetadata, err := s.commitFile(ctx, uploadFileName, sha1, written, metadata, versionMetadata.ExpiredAt, now)
+	if err != nil {
+		if s.fileStorage.IsExist(ctx, uploadFileName) {
+			s.fileStorage.Delete(ctx, uploadFileName)
+		}
+		return nil, err
+	}
+
+	return newMetadata, nil
+}
+
+func (s *FileHostingServiceImpl) DeleteFileVersion(ctx context.Context, file string, version string) error {
+	metadata, err := s.getVersion(ctx, file, version)
+	if err != nil {
+		return err
+	}
+	if metadata.Id == file {
+		return apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Version %s is the current version of file %s, delete the file instead", version, file))
+	}
+
+	return s.deleteVersion(ctx, metadata.Id)
+}
+
+// getVersion returns the metadata of version of file, which is the metadata
+// of the file itself for its current version.
+func (s *FileHostingServiceImpl) getVersion(ctx context.Context, file string, version string) (*domain.FileMetadata, error) {
+	notFound := apperr.ErrNotFound.WithMessage(fmt.Sprintf("Version %s of file %s not found", version, file))
+	if strings.Contains(file, "/") {
+		return nil, notFound
+	}
+	if _, err := strconv.ParseInt(version, 10, 64); err != nil {
+		return nil, notFound
+	}
+
+	if current, err := s.metadataStore.Get(ctx, file); err == nil && versionOf(current) == version {
+		return current, nil
+	}
+
+	metadata, err := s.metadataStore.Get(ctx, s.versionFile(file, version))
+	if err != nil {
+		return nil, notFound
+	}
+
+	return metadata, nil
+}
+
+// listVersions returns the previous versions of file, newest first.
+func (s *FileHostingServiceImpl) listVersions(ctx context.Context, file string) ([]*domain.FileMetadata, error) {
+	versions, err := s.metadataStore.ListIn(ctx, s.versionDirectory(file))
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(versions, func(a, b *domain.FileMetadata) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return versions, nil
+}
+
+// deleteVersion deletes versionFileName and moves the backup of the file to
+// the newest remaining version when it pointed to the deleted one.
+func (s *FileHostingServiceImpl) deleteVersion(ctx context.Context, versionFileName string) error {
+	if err := s.fileStorage.Delete(ctx, versionFileName); err != nil {
+		return err
+	}
+	if err := s.metadataStore.Delete(ctx, versionFileName); err != nil {
+		return err
+	}
+
+	file := path.Base(path.Dir(versionFileName))
+	current, err := s.metadataStore.Get(ctx, file)
+	if err != nil || current.BackupName != versionFileName {
+		return nil
+	}
+
+	current.BackupName = ""
+	versions, err := s.listVersions(ctx, file)
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 {
+		current.BackupName = versions[0].Id
+	}
+
+	return s.metadataStore.Put(ctx, current)
+}
+
+func (s *FileHostingServiceImpl) scheduleDeleteFile(ctx context.Context, fileName string, sha1 string, expiredAt time.Time) error {
+	job := DeletionJob{
+		FileName:  fileName,
+		Sha1:      sha1,
+		ExpiredAt: expiredAt,
+	}
+
+	if err := s.scheduler.Schedule(ctx, job); err != nil {
+		logging.L(ctx).Error("Fail schedule file deletion", logging.StringAttr("file", fileName), logging.ErrAttr(err))
+		return apperr.ErrInternalServerError.WithMessage("Fail schedule file deletion")
+	}
+
+	return nil
+}
+
+// scheduleExpiringFile schedules the announcement of the file the lead time
+// of webhooks before expiredAt. Files expiring sooner are not announced.
+func (s *FileHostingServiceImpl) scheduleExpiringFile(ctx context.Context, fileName string, sha1 string, expiredAt time.Time) {
+	if s.webhooks == nil || s.webhooks.LeadTime() <= 0 {
+		return
+	}
+
+	announceAt := expiredAt.Add(-s.webhooks.LeadTime())
+	if !time.Now().Before(announceAt) {
+		return
+	}
+
+	job := DeletionJob{
+		FileName:  fileName,
+		Sha1:      sha1,
+		ExpiredAt: announceAt,
+		Kind:      DeletionJobExpiring,
+	}
+	if err := s.scheduler.Schedule(ctx, job); err != nil {
+		logging.L(ctx).Error("Fail schedule file expiring event", logging.StringAttr("file", fileName), logging.ErrAttr(err))
+	}
+}
+
+// notify sends event to the webhooks, when they are enabled.
+func (s *FileHostingServiceImpl) notify(ctx context.Context, event *domain.WebhookEvent) {
+	if s.webhooks == nil {
+		return
+	}
+	s.webhooks.Notify(ctx, event)
+}
+
+func (s *FileHostingServiceImpl) Reconcile(ctx context.Context, reschedule bool) (*domain.ReconcileReport, error) {
+	files, err := s.fileStorage.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.metadataStore.Ids(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ReconcileReport{
+		FilesWithoutMetadata: []string{},
+		MetadataWithoutFiles: []string{},
+	}
+
+	hasMetadata := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		hasMetadata[id] = true
+	}
+	hasContent := make(map[string]bool, len(files))
+	for _, file := range files {
+		hasContent[file] = true
+		if !hasMetadata[file] {
+			report.FilesWithoutMetadata = append(report.FilesWithoutMetadata, file)
+		}
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		if !hasContent[id] {
+			report.MetadataWithoutFiles = append(report.MetadataWithoutFiles, id)
+			continue
+		}
+
+		metadata, err := s.metadataStore.Get(ctx, id)
+		if err != nil {
+			logging.L(ctx).Error("Fail read metadata", logging.StringAttr("file", id), logging.ErrAttr(err))
+			continue
+		}
+		report.Files++
+
+		s.reconcileVersions(ctx, metadata, now, reschedule, report)
+
+		if metadata.IsPermanent() {
+			continue
+		}
+		if !now.Before(metadata.ExpiredAt) {
+			job := DeletionJob{FileName: id, Sha1: metadata.Sha1, ExpiredAt: metadata.ExpiredAt}
+			if err := s.handleDeletionJob(ctx, job); err != nil {
+				logging.L(ctx).Error("Fail delete expired file", logging.StringAttr("file", id), logging.ErrAttr(err))
+				continue
+			}
+			report.Deleted++
+			continue
+		}
+		if reschedule {
+			if err := s.scheduleDeleteFile(ctx, id, metadata.Sha1, metadata.ExpiredAt); err != nil {
+				continue
+			}
+			s.scheduleExpiringFile(ctx, id, metadata.Sha1, metadata.ExpiredAt)
+			report.Rescheduled++
+		}
+	}
+
+	return report, nil
+}
+
+// reconcileVersions deletes the versions of file due by the retention and
+// optionally schedules the others again. A version is archived when the next
+// newer one is created.
+func (s *FileHostingServiceImpl) reconcileVersions(ctx context.Context, metadata *domain.FileMetadata, now time.Time, reschedule bool, report *domain.ReconcileReport) {
+	versions, err := s.listVersions(ctx, metadata.Id)
+	if err != nil {
+		logging.L(ctx).Error("Fail list versions", logging.StringAttr("file", metadata.Id), logging.ErrAttr(err))
+		return
+	}
+
+	archivedAt := metadata.CreatedAt
+	for _, version := range versions {
+		deleteAt := s.versionDeleteAt(version, archivedAt)
+		archivedAt = version.CreatedAt
+
+		if deleteAt.Equal(domain.PermanentExpiredAt) {
+			continue
+		}
+		if !now.Before(deleteAt) {
+			if err := s.deleteVersion(ctx, version.Id); err != nil {
+				logging.L(ctx).Error("Fail delete version", logging.StringAttr("file", version.Id), logging.ErrAttr(err))
+				continue
+			}
+			report.Deleted++
+			continue
+		}
+		if reschedule {
+			if err := s.scheduleDeleteFile(ctx, version.Id, version.Sha1, deleteAt); err != nil {
+				continue
+			}
+			report.Rescheduled++
+		}
+	}
+}
+
+func (s *FileHostingServiceImpl) handleDeletionJob(ctx context.Context, job DeletionJob) error {
+	switch job.Kind {
+	case DeletionJobDelete:
+	case DeletionJobExpiring:
+		return s.handleExpiringJob(ctx, job)
+	default:
+		return fmt.Errorf("%w: unknown kind %s of job of file %s", ErrDeletionJobRejected, job.Kind, job.FileName)
+	}
+
+	metadata, err := s.metadataStore.Get(ctx, job.FileName)
+	if err != nil {
+		if apperr.From(err).Code() == http.StatusNotFound {
+			logging.L(ctx).Warn("Skip deletion of file without metadata", logging.StringAttr("file", job.FileName))
+			return nil
+		}
+		return err
+	}
+	if metadata.Sha1 != job.Sha1 {
+		// The file was uploaded again, its own job deletes it
+		return fmt.Errorf("%w: sha1 mismatch of file %s", ErrDeletionJobRejected, job.FileName)
+	}
+
+	// Versions are deleted by the retention before the file would expire
+	if strings.HasPrefix(job.FileName, storage.VersionDirectory+"/") {
+		if err := s.deleteVersion(ctx, job.FileName); err != nil {
+			return err
+		}
+		logging.L(ctx).Info("Delete version", logging.StringAttr("file", job.FileName))
+		return nil
+	}
+
+	if metadata.IsPermanent() {
+		return nil
+	}
+	// The expiry was extended since, the job scheduled by the change deletes
+	// the file. Jobs of a shortened expiry, and jobs retried or claimed again
+	// later than their time, find the file due. Schedulers may keep the time
+	// with millisecond precision
+	if metadata.ExpiredAt.Sub(job.ExpiredAt) >= time.Second {
+		logging.L(ctx).Info("Skip deletion job of previous expiry", logging.StringAttr("file", job.FileName))
+		return nil
+	}
+	if time.Now().Before(metadata.ExpiredAt) {
+		return s.scheduleDeleteFile(ctx, job.FileName, metadata.Sha1, metadata.ExpiredAt)
+	}
+
+	if s.trashRetention > 0 {
+		if err := s.trashFile(ctx, metadata, time.Now()); err != nil {
+			return err
+		}
+		s.tombstoneFile(ctx, job.FileName, domain.TombstoneExpired, metadata.ExpiredAt)
+		s.notify(ctx, &domain.WebhookEvent{Type: domain.WebhookFileExpired, FileId: job.FileName, OccurredAt: metadata.ExpiredAt, Metadata: metadata.View()})
+		logging.L(ctx).Info("Trash expired file", logging.StringAttr("file", job.FileName))
+		return nil
+	}
+
+	if err := s.fileStorage.Delete(ctx, job.FileName); err != nil {
+		return err
+	}
+	if err := s.metadataStore.Delete(ctx, job.FileName); err != nil {
+		logging.L(ctx).Error("Failed to delete metadata file", logging.ErrAttr(err))
+	}
+	s.tombstoneFile(ctx, job.FileName, domain.TombstoneExpired, metadata.ExpiredAt)
+	s.notify(ctx, &domain.WebhookEvent{Type: domain.WebhookFileExpired, FileId: job.FileName, OccurredAt: metadata.ExpiredAt, Metadata: metadata.View()})
+
+	logging.L(ctx).Info("Delete file", logging.StringAttr("file", job.FileName))
+
+	return nil
+}
+
+// handleExpiringJob announces that the file expires within the lead time.
+// Jobs of files uploaded again or with another expiry since are skipped,
+// the jobs scheduled for them announce them.
+func (s *FileHostingServiceImpl) handleExpiringJob(ctx context.Context, job DeletionJob) error {
+	if s.webhooks == nil {
+		return nil
+	}
+
+	metadata, err := s.metadataStore.Get(ctx, job.FileName)
+	if err != nil {
+		if apperr.From(err).Code() == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	if metadata.Sha1 != job.Sha1 || metadata.IsPermanent() {
+		return nil
+	}
+	// Schedulers may keep the time with millisecond precision
+	if metadata.ExpiredAt.Add(-s.webhooks.LeadTime()).Sub(job.ExpiredAt).Abs() >= time.Second {
+		return nil
+	}
+
+	s.notify(ctx, &domain.WebhookEvent{Type: domain.WebhookFileExpiring, FileId: metadata.Id, Metadata: metadata.View()})
+
+	return nil
+}
+
+// writeContent streams content into file, detecting the mime type from the
+// first bytes and computing the sha1 and size on the fly.
+func (s *FileHostingServiceImpl) writeContent(ctx context.Context, file string, content io.Reader, size int64, metadata *domain.FileMetadata) (string, int64, error) {
+	reader := bufio.NewReaderSize(content, sniffLen)
+	head, _ := reader.Peek(sniffLen)
+	metadata.UpdateContentType(head)
+
+	hash := sha1.New()
+	counter := &countingReader{reader: io.TeeReader(reader, hash)}
+
+	if err := s.fileStorage.Write(ctx, file, counter, size, metadata.MimeType); err != nil {
+		return "", 0, err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), counter.count, nil
+}
+
+// hashContent reads the stored file to compute the sha1 and size of its
+// content, detecting the mime type from the first bytes.
+func (s *FileHostingServiceImpl) hashContent(ctx context.Context, file string, metadata *domain.FileMetadata) (string, int64, error) {
+	content, err := s.fileStorage.Read(ctx, file)
+	if err != nil {
+		return "", 0, err
+	}
+	defer content.Close()
+
+	reader := bufio.NewReaderSize(content, sniffLen)
+	head, _ := reader.Peek(sniffLen)
+	metadata.UpdateContentType(head)
+
+	hash := sha1.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return "", 0, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read file %s", file))
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), size, nil
+}
+
+func (s *FileHostingServiceImpl) trashFileName(id string) string {
+	return fmt.Sprintf("%s/%s", storage.TrashDirectory, id)
+}
+
+func (s *FileHostingServiceImpl) tombstoneFileName(id string) string {
+	return fmt.Sprintf("%s/%s", storage.TombstoneDirectory, id)
+}
+
+func (s *FileHostingServiceImpl) versionDirectory(file string) string {
+	return fmt.Sprintf("%s/%s", storage.VersionDirectory, file)
+}
+
+func (s *FileHostingServiceImpl) versionFile(file string, version string) string {
+	return fmt.Sprintf("%s/%s", s.versionDirectory(file), version)
+}
+
+// versionOf returns the version of metadata, the time it was uploaded.
+func versionOf(metadata *domain.FileMetadata) string {
+	return strconv.FormatInt(metadata.CreatedAt.UnixNano(), 10)
+}
+
+func (s *FileHostingServiceImpl) uploadFile(file string, now time.Time) string {
+	return fmt.Sprintf("%s.%d.upload", file, now.UnixNano())
+}
+
+func (s *FileHostingServiceImpl) generateFileName() string {
+	now := time.Now().UnixMilli()
+
+	timePart := strconv.FormatInt(now, 36)
+	if len(timePart) > 4 {
+		timePart = timePart[len(timePart)-4:]
+	}
+
+	randPart := fmt.Sprintf("%x", rand.Int32N(0x10000))
+	return timePart + randPart
 }
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// FilePasswordService authorizes reading files uploaded with a password.
+type FilePasswordService interface {
+	// VerifyPassword rejects a wrong password of the file entered by client
+	// with apperr.ErrForbidden, and clients which entered too many wrong
+	// passwords with apperr.ErrTooManyRequests. Attempts are limited per
+	// file and client only, so clients with many addresses can try more
+	// passwords.
+	VerifyPassword(ctx context.Context, metadata *domain.FileMetadata, password string, client string) error
+	// IssueCookie returns a cookie value which reads the file without the
+	// password until the returned time. Changing the password revokes it.
+	IssueCookie(metadata *domain.FileMetadata) (string, time.Time)
+	// VerifyCookie tells whether value was issued for the file and is not
+	// expired.
+	VerifyCookie(metadata *domain.FileMetadata, value string) bool
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+)
+
+type FilePasswordServiceImpl struct {
+	limiter        PasswordAttemptLimiter
+	maxAttempts    int64
+	cookieSecret   []byte
+	cookieLifetime time.Duration
+}
+
+var _ FilePasswordService = (*FilePasswordServiceImpl)(nil)
+
+// NewFilePasswordService signs cookies by cookieSecret, a random secret
+// when it is empty, which is not shared by replicas nor kept on restart.
+func NewFilePasswordService(limiter PasswordAttemptLimiter, maxAttempts int, cookieSecret string, cookieLifetime time.Duration) (*FilePasswordServiceImpl, error) {
+	secret := []byte(cookieSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, tokenSize)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return &FilePasswordServiceImpl{
+		limiter:        limiter,
+		maxAttempts:    int64(maxAttempts),
+		cookieSecret:   secret,
+		cookieLifetime: cookieLifetime,
+	}, nil
+}
+
+func (s *FilePasswordServiceImpl) VerifyPassword(ctx context.Context, metadata *domain.FileMetadata, password string, client string) error {
+	key := passwordAttemptKey(metadata.Id, client)
+
+	attempts, err := s.limiter.Attempt(ctx, key)
+	if err != nil {
+		logging.L(ctx).Error("Fail count password attempt", logging.StringAttr("file", metadata.Id), logging.ErrAttr(err))
+		return apperr.ErrInternalServerError.WithMessage("Fail verify password")
+	}
+	if attempts > s.maxAttempts {
+		return apperr.ErrTooManyRequests.WithMessage("Too many wrong passwords, try again later")
+	}
+
+	if VerifySecret(password, metadata.PasswordHash) {
+		if err := s.limiter.Reset(ctx, key); err != nil {
+			logging.L(ctx).Error("Fail reset password attempts", logging.StringAttr("file", metadata.Id), logging.ErrAttr(err))
+		}
+		return nil
+	}
+
+	return apperr.ErrForbidden.WithMessage("Wrong password")
+}
+
+func (s *FilePasswordServiceImpl) IssueCookie(metadata *domain.FileMetadata) (string, time.Time) {
+	expiresAt := time.Now().Add(s.cookieLifetime).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return expires + "." + s.signCookie(metadata, expires), expiresAt
+}
+
+func (s *FilePasswordServiceImpl) VerifyCookie(metadata *domain.FileMetadata, value string) bool {
+	expires, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(s.signCookie(metadata, expires)), []byte(signature)) {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	return err == nil && time.Now().Before(time.Unix(unix, 0))
+}
+
+// signCookie returns the HMAC-SHA256 of the file, its password hash and
+// expires.
+func (s *FilePasswordServiceImpl) signCookie(metadata *domain.FileMetadata, expires string) string {
+	mac := hmac.New(sha256.New, s.cookieSecret)
+	fmt.Fprintf(mac, "%s\n%s\n%s", metadata.Id, metadata.PasswordHash, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+type JWTService interface {
+	// Authenticate verifies the signature, issuer, audience and expiry of
+	// token and returns a key with the scopes and the owner of its claims.
+	// Invalid tokens are rejected with apperr.ErrUnauthorized.
+	Authenticate(ctx context.Context, token string) (*domain.APIKey, error)
+}
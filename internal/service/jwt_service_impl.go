@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/time/rate"
+)
+
+// jwtLeeway is the allowed clock skew with the identity provider.
+const jwtLeeway = 30 * time.Second
+
+// jwtAPIKeyId is the id of keys made of JWTs.
+const jwtAPIKeyId = "jwt"
+
+type JWTOptions struct {
+	JWKSURL  string
+	Issuer   string
+	Audience string
+	// RefreshInterval is how often the JWK Set is fetched again.
+	RefreshInterval time.Duration
+	// RefreshUnknownInterval limits fetches caused by unknown key ids.
+	RefreshUnknownInterval time.Duration
+	OwnerClaim             string
+	ScopesClaim            string
+	// ScopeMapping maps values of the scopes claim to scopes, values without
+	// a mapping are used as scopes when they are valid.
+	ScopeMapping map[string][]domain.Scope
+}
+
+type JWTServiceImpl struct {
+	options JWTOptions
+	keyfunc keyfunc.Keyfunc
+	parser  *jwt.Parser
+}
+
+var _ JWTService = (*JWTServiceImpl)(nil)
+
+// NewJWTService fetches the JWK Set in the background until ctx is done. An
+// unavailable identity provider does not fail the start, tokens are rejected
+// until the keys are fetched.
+func NewJWTService(ctx context.Context, options JWTOptions) (*JWTServiceImpl, error) {
+	for value, scopes := range options.ScopeMapping {
+		for _, scope := range scopes {
+			if !scope.IsValid() {
+				return nil, fmt.Errorf("invalid scope of %s: %s", value, scope)
+			}
+		}
+	}
+
+	kf, err := keyfunc.NewDefaultOverrideCtx(ctx, []string{options.JWKSURL}, keyfunc.Override{
+		RefreshInterval:   options.RefreshInterval,
+		RefreshUnknownKID: rate.NewLimiter(rate.Every(options.RefreshUnknownInterval), 1),
+		RefreshErrorHandlerFunc: func(u string) func(ctx context.Context, err error) {
+			return func(ctx context.Context, err error) {
+				logging.L(ctx).Error("Fail refresh JWK Set", logging.StringAttr("url", u), logging.ErrAttr(err))
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTServiceImpl{
+		options: options,
+		keyfunc: kf,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+			jwt.WithIssuer(options.Issuer),
+			jwt.WithAudience(options.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(jwtLeeway),
+		),
+	}, nil
+}
+
+func (s *JWTServiceImpl) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	claims := jwt.MapClaims{}
+	if _, err := s.parser.ParseWithClaims(token, claims, s.keyfunc.KeyfuncCtx(ctx)); err != nil {
+		logging.L(ctx).Warn("Invalid JWT", logging.ErrAttr(err))
+		return nil, apperr.ErrUnauthorized
+	}
+
+	owner, _ := claims[s.options.OwnerClaim].(string)
+	if len(owner) == 0 {
+		logging.L(ctx).Warn("JWT without owner", logging.StringAttr("claim", s.options.OwnerClaim))
+		return nil, apperr.ErrUnauthorized
+	}
+
+	key := &domain.APIKey{
+		Id:     jwtAPIKeyId,
+		Label:  fmt.Sprintf("%s:%s", jwtAPIKeyId, owner),
+		Scopes: s.scopes(claims[s.options.ScopesClaim]),
+		Owner:  owner,
+	}
+	if expiresAt, err := claims.GetExpirationTime(); err == nil && expiresAt != nil {
+		key.ExpiresAt = expiresAt.Time
+	}
+
+	return key, nil
+}
+
+// scopes maps the values of the scopes claim, a space separated string or a
+// list, to scopes. Unknown values are skipped.
+func (s *JWTServiceImpl) scopes(claim any) []domain.Scope {
+	var values []string
+	switch claim := claim.(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []any:
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	scopes := []domain.Scope{}
+	for _, value := range values {
+		if mapped, ok := s.options.ScopeMapping[value]; ok {
+			scopes = append(scopes, mapped...)
+		} else if scope := domain.Scope(value); scope.IsValid() {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/goccy/go-json"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testJWTIssuer   = "https://issuer.test"
+	testJWTAudience = "file-hosting"
+)
+
+type testJWK struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+func newTestJWK(t *testing.T, kid string) *testJWK {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return &testJWK{kid: kid, key: key}
+}
+
+func (k *testJWK) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// jwksServer serves the public keys of keys, which tests replace to rotate
+// them.
+type jwksServer struct {
+	mu   sync.Mutex
+	keys []*testJWK
+}
+
+func (s *jwksServer) setKeys(keys ...*testJWK) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{Keys: []jwk{}}
+	for _, key := range s.keys {
+		public := key.key.PublicKey
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: key.kid,
+			Alg: jwt.SigningMethodRS256.Alg(),
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(set)
+}
+
+func newTestJWTService(t *testing.T, jwks *jwksServer) *JWTServiceImpl {
+	t.Helper()
+
+	server := httptest.NewServer(jwks)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	service, err := NewJWTService(ctx, JWTOptions{
+		JWKSURL:                server.URL,
+		Issuer:                 testJWTIssuer,
+		Audience:               testJWTAudience,
+		RefreshInterval:        time.Hour,
+		RefreshUnknownInterval: time.Millisecond,
+		OwnerClaim:             "sub",
+		ScopesClaim:            "scope",
+		ScopeMapping:           map[string][]domain.Scope{"files:write": {domain.ScopeUploadNamed, domain.ScopeDelete}},
+	})
+	if err != nil {
+		t.Fatalf("NewJWTService: %v", err)
+	}
+	return service
+}
+
+func validTestClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testJWTIssuer,
+		"aud":   testJWTAudience,
+		"sub":   "alice",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "files:write read-private unknown",
+	}
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	key := newTestJWK(t, "key-1")
+	jwks := &jwksServer{}
+	jwks.setKeys(key)
+	service := newTestJWTService(t, jwks)
+
+	apiKey, err := service.Authenticate(context.Background(), key.sign(t, validTestClaims()))
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+
+	if apiKey.Owner != "alice" || apiKey.Label != "jwt:alice" {
+		t.Errorf("owner %q and label %q", apiKey.Owner, apiKey.Label)
+	}
+	wantScopes := []domain.Scope{domain.ScopeUploadNamed, domain.ScopeDelete, domain.ScopeReadPrivate}
+	if !slices.Equal(apiKey.Scopes, wantScopes) {
+		t.Errorf("scopes %v, want %v", apiKey.Scopes, wantScopes)
+	}
+	if apiKey.ExpiresAt.IsZero() {
+		t.Error("missing expiry")
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	oldKey := newTestJWK(t, "key-1")
+	newKey := newTestJWK(t, "key-2")
+	jwks := &jwksServer{}
+	jwks.setKeys(oldKey)
+	service := newTestJWTService(t, jwks)
+
+	if _, err := service.Authenticate(context.Background(), oldKey.sign(t, validTestClaims())); err != nil {
+		t.Fatalf("Authenticate by old key: %v", err)
+	}
+
+	// The unknown key id of the new key fetches the JWK Set again
+	jwks.setKeys(newKey)
+	if _, err := service.Authenticate(context.Background(), newKey.sign(t, validTestClaims())); err != nil {
+		t.Fatalf("Authenticate by new key: %v", err)
+	}
+
+	// The old key was dropped by the fetch
+	if _, err := service.Authenticate(context.Background(), oldKey.sign(t, validTestClaims())); !errors.Is(err, apperr.ErrUnauthorized) {
+		t.Errorf("Authenticate by removed key: %v, want unauthorized", err)
+	}
+}
+
+func TestJWTRejected(t *testing.T) {
+	key := newTestJWK(t, "key-1")
+	jwks := &jwksServer{}
+	jwks.setKeys(key)
+	service := newTestJWTService(t, jwks)
+
+	tests := []struct {
+		name   string
+		key    *testJWK
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "issuer", key: key, modify: func(claims jwt.MapClaims) { claims["iss"] = "https://other.test" }},
+		{name: "audience", key: key, modify: func(claims jwt.MapClaims) { claims["aud"] = "other" }},
+		{name: "expired", key: key, modify: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no expiry", key: key, modify: func(claims jwt.MapClaims) { delete(claims, "exp") }},
+		{name: "no owner", key: key, modify: func(claims jwt.MapClaims) { delete(claims, "sub") }},
+		{name: "unknown key", key: newTestJWK(t, "key-2"), modify: func(claims jwt.MapClaims) {}},
+		{name: "foreign key with known id", key: newTestJWK(t, "key-1"), modify: func(claims jwt.MapClaims) {}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validTestClaims()
+			tt.modify(claims)
+
+			_, err := service.Authenticate(context.Background(), tt.key.sign(t, claims))
+			if !errors.Is(err, apperr.ErrUnauthorized) {
+				t.Errorf("Authenticate: %v, want unauthorized", err)
+			}
+		})
+	}
+}
+
+func TestJWTExpiryLeeway(t *testing.T) {
+	key := newTestJWK(t, "key-1")
+	jwks := &jwksServer{}
+	jwks.setKeys(key)
+	service := newTestJWTService(t, jwks)
+
+	claims := validTestClaims()
+	claims["exp"] = time.Now().Add(-jwtLeeway / 2).Unix()
+	if _, err := service.Authenticate(context.Background(), key.sign(t, claims)); err != nil {
+		t.Errorf("Authenticate within leeway: %v", err)
+	}
+}
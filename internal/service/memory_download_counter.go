@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// MemoryDownloadCounter counts downloads in process, for deployments without
+// Redis. Counts are lost on restart and are not shared between replicas.
+type MemoryDownloadCounter struct {
+	mu     sync.Mutex
+	counts map[string]memoryDownloadCount
+}
+
+type memoryDownloadCount struct {
+	count int64
+	// expiredAt is when the file expires and the count is dropped, zero for
+	// permanent files.
+	expiredAt time.Time
+}
+
+func NewMemoryDownloadCounter() DownloadCounter {
+	return &MemoryDownloadCounter{
+		counts: make(map[string]memoryDownloadCount),
+	}
+}
+
+func (c *MemoryDownloadCounter) Increment(ctx context.Context, metadata *domain.FileMetadata) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(time.Now())
+
+	id := downloadCounterId(metadata)
+	count := c.counts[id]
+	count.count++
+	if !metadata.IsPermanent() {
+		count.expiredAt = metadata.ExpiredAt
+	}
+	c.counts[id] = count
+
+	return count.count, nil
+}
+
+func (c *MemoryDownloadCounter) Count(ctx context.Context, metadata *domain.FileMetadata) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[downloadCounterId(metadata)].count, nil
+}
+
+func (c *MemoryDownloadCounter) Reset(ctx context.Context, metadata *domain.FileMetadata) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.counts, downloadCounterId(metadata))
+
+	return nil
+}
+
+// prune drops counts of expired files, it must be called with mu held.
+func (c *MemoryDownloadCounter) prune(now time.Time) {
+	for id, count := range c.counts {
+		if !count.expiredAt.IsZero() && now.After(count.expiredAt) {
+			delete(c.counts, id)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryPasswordAttemptLimiter counts attempts in process, for deployments
+// without Redis. Counts are lost on restart and are not shared between
+// replicas.
+type MemoryPasswordAttemptLimiter struct {
+	window   time.Duration
+	mu       sync.Mutex
+	attempts map[string]memoryPasswordAttempts
+}
+
+type memoryPasswordAttempts struct {
+	count int64
+	// windowEnd is when the count is dropped.
+	windowEnd time.Time
+}
+
+func NewMemoryPasswordAttemptLimiter(window time.Duration) PasswordAttemptLimiter {
+	return &MemoryPasswordAttemptLimiter{
+		window:   window,
+		attempts: make(map[string]memoryPasswordAttempts),
+	}
+}
+
+func (l *MemoryPasswordAttemptLimiter) Attempt(ctx context.Context, key string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	attempts, ok := l.attempts[key]
+	if !ok {
+		attempts.windowEnd = now.Add(l.window)
+	}
+	attempts.count++
+	l.attempts[key] = attempts
+
+	return attempts.count, nil
+}
+
+func (l *MemoryPasswordAttemptLimiter) Reset(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.attempts, key)
+
+	return nil
+}
+
+// prune drops counts of ended windows, it must be called with mu held.
+func (l *MemoryPasswordAttemptLimiter) prune(now time.Time) {
+	for key, attempts := range l.attempts {
+		if !now.Before(attempts.windowEnd) {
+			delete(l.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// PasswordAttemptLimiter counts passwords entered by a client for a file
+// within a window, the count is dropped when the window ends.
+type PasswordAttemptLimiter interface {
+	// Attempt counts an attempt of key and returns the attempts of key in
+	// the current window including this one. Counting before the password
+	// is verified keeps concurrent attempts from passing the limit.
+	Attempt(ctx context.Context, key string) (int64, error)
+	// Reset drops the attempts of key, after a correct password.
+	Reset(ctx context.Context, key string) error
+}
+
+// passwordAttemptKey identifies the attempts of client to read file.
+func passwordAttemptKey(file string, client string) string {
+	return fmt.Sprintf("%s:%s", file, client)
+}
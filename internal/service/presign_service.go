@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+type PresignService interface {
+	// GetFileURL returns a short-lived URL to download the file directly
+	// from the storage.
+	GetFileURL(ctx context.Context, metadata *domain.FileMetadata) (string, error)
+	// CreateUpload returns a short-lived URL to upload a file directly to
+	// the storage. The file is hosted only after FinalizeUpload.
+	CreateUpload(ctx context.Context, metadata *domain.FileMetadata, expiry domain.FileExpiry) (*domain.PresignedUpload, error)
+	FinalizeUpload(ctx context.Context, id string) (*domain.PresignedUpload, error)
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/storage"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+const (
+	presignedUploadDirectory       = "presigned"
+	presignedUploadCleanupInterval = 10 * time.Minute
+	// presignedUploadFinalizeWindow is how long an upload can be finalized
+	// after its URL expired, a transfer started in time may still be running.
+	presignedUploadFinalizeWindow = time.Hour
+)
+
+// PresignServiceImpl lets clients transfer content directly to and from
+// the storage. Uploaded content is kept in the file storage next to the
+// state of the upload until it is finalized and passed to FileHostingService.
+type PresignServiceImpl struct {
+	ctx                context.Context
+	fileStorage        storage.FileStorage
+	presignedStorage   storage.PresignedFileStorage
+	fileHostingService FileHostingService
+	expiration         time.Duration
+	// maxSize limits the content uploaded by a presigned URL, which the URL
+	// itself cannot do.
+	maxSize int64
+	// expiryPolicy validates the expiry of an upload when it is created.
+	expiryPolicy domain.ExpiryPolicy
+	locks        sync.Map
+}
+
+func NewPresignService(ctx context.Context, fileStorage storage.FileStorage, fileHostingService FileHostingService, expiration time.Duration, maxSize int64, expiryPolicy domain.ExpiryPolicy) (PresignService, error) {
+	presignedStorage, ok := fileStorage.(storage.PresignedFileStorage)
+	if !ok {
+		return nil, errors.New("file storage does not support presigned urls")
+	}
+
+	service := &PresignServiceImpl{
+		ctx:                ctx,
+		fileStorage:        fileStorage,
+		presignedStorage:   presignedStorage,
+		fileHostingService: fileHostingService,
+		expiration:         expiration,
+		maxSize:            maxSize,
+		expiryPolicy:       expiryPolicy,
+	}
+
+	go service.deleteExpiredUploads()
+
+	return service, nil
+}
+
+func (s *PresignServiceImpl) GetFileURL(ctx context.Context, metadata *domain.FileMetadata) (string, error) {
+	return s.presignedStorage.PresignRead(
+		ctx,
+		metadata.Id,
+		s.expiration,
+		metadata.MimeType,
+		fmt.Sprintf("inline; filename=\"%s\"", metadata.Name),
+	)
+}
+
+func (s *PresignServiceImpl) CreateUpload(ctx context.Context, metadata *domain.FileMetadata, expiry domain.FileExpiry) (*domain.PresignedUpload, error) {
+	if strings.Contains(metadata.Name, "/") {
+		return nil, apperr.ErrBadRequest.WithMessage("File name cannot contain '/'")
+	}
+	if _, err := resolveExpiredAt(s.expiryPolicy, expiry, time.Now()); err != nil {
+		return nil, err
+	}
+
+	id := strings.ReplaceAll(uuid.NewString(), "-", "")
+	url, err := s.presignedStorage.PresignWrite(ctx, s.contentFile(id), s.expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	upload := &domain.PresignedUpload{
+		Id:           id,
+		Url:          url,
+		UrlExpiredAt: now.Add(s.expiration),
+		Metadata:     metadata,
+		Duration:     expiry.Duration,
+		ExpiresAt:    expiry.ExpiresAt,
+		CreatedAt:    now,
+		ExpiredAt:    now.Add(s.expiration + presignedUploadFinalizeWindow),
+	}
+
+	if err := s.writeUpload(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (s *PresignServiceImpl) FinalizeUpload(ctx context.Context, id string) (*domain.PresignedUpload, error) {
+	unlock := s.lock(id)
+	defer unlock()
+
+	upload, err := s.readUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.IsFinalized() {
+		return upload, nil
+	}
+
+	if time.Now().After(upload.ExpiredAt) {
+		s.deleteUpload(ctx, upload)
+		return nil, apperr.ErrGone.WithMessage(fmt.Sprintf("Upload %s expired", id))
+	}
+
+	if !s.fileStorage.IsExist(ctx, s.contentFile(id)) {
+		return nil, apperr.ErrConflict.WithMessage(fmt.Sprintf("Content of upload %s is not uploaded", id))
+	}
+
+	size, err := s.presignedStorage.Size(ctx, s.contentFile(id))
+	if err != nil {
+		return nil, err
+	}
+	if size > s.maxSize {
+		// The URL is still usable to upload smaller content until it expires
+		if err := s.fileStorage.Delete(ctx, s.contentFile(id)); err != nil {
+			return nil, err
+		}
+		return nil, apperr.ErrRequestEntityTooLarge.WithMessage(fmt.Sprintf("Content of upload %s exceeds %d bytes", id, s.maxSize))
+	}
+
+	metadata := &domain.FileMetadata{
+		Name:         upload.Metadata.Name,
+		MimeType:     upload.Metadata.MimeType,
+		Meta:         upload.Metadata.Meta,
+		MaxDownloads: upload.Metadata.MaxDownloads,
+		Owner:        upload.Metadata.Owner,
+		Private:      upload.Metadata.Private,
+		PasswordHash: upload.Metadata.PasswordHash,
+	}
+
+	fileName, _, err := s.fileHostingService.ImportFile(ctx, s.contentFile(id), metadata, domain.FileExpiry{Duration: upload.Duration, ExpiresAt: upload.ExpiresAt})
+	if err != nil {
+		return nil, err
+	}
+
+	// The state is kept until expiration to answer repeated finalize calls
+	upload.FileId = fileName
+	if err := s.writeUpload(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (s *PresignServiceImpl) deleteUpload(ctx context.Context, upload *domain.PresignedUpload) error {
+	if s.fileStorage.IsExist(ctx, s.contentFile(upload.Id)) {
+		if err := s.fileStorage.Delete(ctx, s.contentFile(upload.Id)); err != nil {
+			return err
+		}
+	}
+
+	return s.fileStorage.Delete(ctx, s.uploadFile(upload.Id))
+}
+
+func (s *PresignServiceImpl) deleteExpiredUploads() {
+	ticker := time.NewTicker(presignedUploadCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		files, err := s.fileStorage.FilesIn(s.ctx, presignedUploadDirectory)
+		if err != nil {
+			logging.L(s.ctx).Error("Fail list presigned uploads", logging.ErrAttr(err))
+			continue
+		}
+
+		for _, file := range files {
+			id, ok := strings.CutSuffix(file, ".info")
+			if !ok {
+				continue
+			}
+
+			unlock := s.lock(id)
+			upload, err := s.readUpload(s.ctx, id)
+			if err == nil && time.Now().After(upload.ExpiredAt) {
+				if err := s.deleteUpload(s.ctx, upload); err != nil {
+					logging.L(s.ctx).Error("Fail delete expired presigned upload", logging.StringAttr("upload", id), logging.ErrAttr(err))
+				} else {
+					s.locks.Delete(id)
+					logging.L(s.ctx).Info("Delete expired presigned upload", logging.StringAttr("upload", id))
+				}
+			}
+			unlock()
+		}
+	}
+}
+
+func (s *PresignServiceImpl) readUpload(ctx context.Context, id string) (*domain.PresignedUpload, error) {
+	if strings.Contains(id, "/") || !s.fileStorage.IsExist(ctx, s.uploadFile(id)) {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("Upload %s not found", id))
+	}
+
+	reader, err := s.fileStorage.Read(ctx, s.uploadFile(id))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read upload %s", id))
+	}
+
+	upload, err := domain.NewPresignedUploadFromBytes(data)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read upload %s", id))
+	}
+
+	return upload, nil
+}
+
+func (s *PresignServiceImpl) writeUpload(ctx context.Context, upload *domain.PresignedUpload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return apperr.ErrInternalServerError.WithMessage("Fail serialize upload")
+	}
+
+	if s.fileStorage.IsExist(ctx, s.uploadFile(upload.Id)) {
+		if err := s.fileStorage.Delete(ctx, s.uploadFile(upload.Id)); err != nil {
+			return err
+		}
+	}
+
+	return s.fileStorage.Write(ctx, s.uploadFile(upload.Id), bytes.NewReader(data), int64(len(data)), "application/json")
+}
+
+// lock serializes requests to the same upload within the process.
+func (s *PresignServiceImpl) lock(id string) func() {
+	value, _ := s.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func (s *PresignServiceImpl) uploadFile(id string) string {
+	return fmt.Sprintf("%s/%s.info", presignedUploadDirectory, id)
+}
+
+func (s *PresignServiceImpl) contentFile(id string) string {
+	return fmt.Sprintf("%s/%s", presignedUploadDirectory, id)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/bruhabruh/file-hosting/pkg/rabbitmq"
+	"github.com/goccy/go-json"
+	"github.com/streadway/amqp"
+)
+
+const fileDeletionQueueName = "file-hosting-service/delete-file"
+
+// RabbitMQDeletionScheduler publishes jobs to a durable queue. Jobs which
+// are not due yet are requeued until they are. Failed jobs are retried with
+// backoff and then dead-lettered, rejected jobs are dead-lettered at once.
+type RabbitMQDeletionScheduler struct {
+	mq     *rabbitmq.RabbitMQ
+	policy rabbitmq.RetryPolicy
+}
+
+// DeadDeletionJob is a dead-lettered job with the reason of its last failure.
+type DeadDeletionJob struct {
+	Job DeletionJob `json:"job"`
+	// Body is the raw message when it is not a job.
+	Body     string    `json:"body,omitempty"`
+	Retries  int       `json:"retries"`
+	Error    string    `json:"error,omitempty"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+func NewRabbitMQDeletionScheduler(mq *rabbitmq.RabbitMQ, policy rabbitmq.RetryPolicy) (*RabbitMQDeletionScheduler, error) {
+	if err := mq.DeclareQueueWithRetry(fileDeletionQueueName, policy); err != nil {
+		return nil, err
+	}
+
+	return &RabbitMQDeletionScheduler{mq: mq, policy: policy}, nil
+}
+
+func (s *RabbitMQDeletionScheduler) Schedule(ctx context.Context, job DeletionJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return s.mq.Publish(fileDeletionQueueName, data)
+}
+
+func (s *RabbitMQDeletionScheduler) Consume(ctx context.Context, handler DeletionHandler) error {
+	return s.mq.Consume(ctx, fileDeletionQueueName, func(msg amqp.Delivery) {
+		var job DeletionJob
+		if err := json.Unmarshal(msg.Body, &job); err != nil {
+			logging.L(ctx).Error("Failed to unmarshal delete file message", logging.ErrAttr(err))
+			s.reject(ctx, msg, err)
+			return
+		}
+
+		if time.Now().Before(job.ExpiredAt) {
+			msg.Nack(false, true)
+			return
+		}
+
+		err := handler(ctx, job)
+		if errors.Is(err, ErrDeletionJobRejected) {
+			logging.L(ctx).Warn("Dead-letter delete file message", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+			s.reject(ctx, msg, err)
+			return
+		}
+		if err != nil {
+			logging.L(ctx).Error("Failed to handle delete file message", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+			if err := s.mq.Retry(fileDeletionQueueName, s.policy, msg, err); err != nil {
+				logging.L(ctx).Error("Failed to retry delete file message", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+				msg.Nack(false, true)
+			}
+			return
+		}
+
+		msg.Ack(false)
+	})
+}
+
+func (s *RabbitMQDeletionScheduler) reject(ctx context.Context, msg amqp.Delivery, cause error) {
+	if err := s.mq.Reject(fileDeletionQueueName, msg, cause); err != nil {
+		logging.L(ctx).Error("Failed to dead-letter delete file message", logging.ErrAttr(err))
+		msg.Nack(false, true)
+	}
+}
+
+// DeadLetters returns up to limit dead-lettered jobs, oldest first.
+func (s *RabbitMQDeletionScheduler) DeadLetters(limit int) ([]DeadDeletionJob, error) {
+	deadLetters, err := s.mq.DeadLetters(fileDeletionQueueName, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]DeadDeletionJob, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		job := DeadDeletionJob{
+			Retries:  deadLetter.Retries,
+			Error:    deadLetter.Error,
+			FailedAt: deadLetter.FailedAt,
+		}
+		if err := json.Unmarshal(deadLetter.Body, &job.Job); err != nil {
+			job.Body = string(deadLetter.Body)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// ReplayDeadLetters schedules up to limit dead-lettered jobs again and
+// returns how many were scheduled.
+func (s *RabbitMQDeletionScheduler) ReplayDeadLetters(limit int) (int, error) {
+	return s.mq.ReplayDeadLetters(fileDeletionQueueName, limit)
+}
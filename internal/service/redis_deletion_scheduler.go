@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/goccy/go-json"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisDeletionPollInterval = time.Second
+	redisDeletionBatchSize    = 100
+	// redisDeletionLease hides a claimed job from other pollers. A job which
+	// is not handled in time, e.g. after a crash, is claimed again.
+	redisDeletionLease      = 5 * time.Minute
+	redisDeletionRetryDelay = time.Minute
+)
+
+// claimDueJobsScript returns up to ARGV[2] jobs due at ARGV[1] with their
+// scores and moves them to ARGV[3], so concurrent pollers never claim the
+// same job.
+var claimDueJobsScript = redis.NewScript(`
+local jobs = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'WITHSCORES', 'LIMIT', 0, ARGV[2])
+for i = 1, #jobs, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[3], jobs[i])
+end
+return jobs
+`)
+
+// releaseJobScript removes the job ARGV[1] claimed with the lease score
+// ARGV[2], or moves it to ARGV[3] when given. A job scheduled again while it
+// was handled has another score and is kept.
+var releaseJobScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score or tonumber(score) ~= tonumber(ARGV[2]) then
+	return 0
+end
+if ARGV[3] == '' then
+	return redis.call('ZREM', KEYS[1], ARGV[1])
+end
+return redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+`)
+
+// redisDeletionMember identifies a job in the sorted set, so scheduling a
+// job of the same file, sha1 and kind again only moves it to the new time.
+type redisDeletionMember struct {
+	FileName string          `json:"fileName"`
+	Sha1     string          `json:"sha1"`
+	Kind     DeletionJobKind `json:"kind,omitempty"`
+}
+
+// RedisDeletionScheduler keeps jobs in a sorted set scored by their time in
+// unix milliseconds, which is polled for due jobs.
+type RedisDeletionScheduler struct {
+	rdb *redis.Client
+	key string
+}
+
+func NewRedisDeletionScheduler(rdb *redis.Client) DeletionScheduler {
+	return &RedisDeletionScheduler{
+		rdb: rdb,
+		key: fmt.Sprintf("%s:deletions", redisKeyPrefix),
+	}
+}
+
+func (s *RedisDeletionScheduler) Schedule(ctx context.Context, job DeletionJob) error {
+	data, err := json.Marshal(redisDeletionMember{FileName: job.FileName, Sha1: job.Sha1, Kind: job.Kind})
+	if err != nil {
+		return err
+	}
+
+	return s.rdb.ZAdd(ctx, s.key, redis.Z{
+		Score:  float64(job.ExpiredAt.UnixMilli()),
+		Member: data,
+	}).Err()
+}
+
+func (s *RedisDeletionScheduler) Consume(ctx context.Context, handler DeletionHandler) error {
+	go func() {
+		ticker := time.NewTicker(redisDeletionPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// A full batch means more jobs may be due already
+			for s.handleDueJobs(ctx, handler) == redisDeletionBatchSize && ctx.Err() == nil {
+			}
+		}
+	}()
+
+	return nil
+}
+
+// handleDueJobs claims a batch of due jobs and returns its size.
+func (s *RedisDeletionScheduler) handleDueJobs(ctx context.Context, handler DeletionHandler) int {
+	now := time.Now()
+	lease := now.Add(redisDeletionLease).UnixMilli()
+	claimed, err := claimDueJobsScript.Run(
+		ctx,
+		s.rdb,
+		[]string{s.key},
+		now.UnixMilli(),
+		redisDeletionBatchSize,
+		lease,
+	).StringSlice()
+	if err != nil {
+		logging.L(ctx).Error("Fail claim deletion jobs", logging.ErrAttr(err))
+		return 0
+	}
+
+	for i := 0; i+1 < len(claimed); i += 2 {
+		data := claimed[i]
+		var member redisDeletionMember
+		if err := json.Unmarshal([]byte(data), &member); err != nil {
+			logging.L(ctx).Error("Fail unmarshal deletion job", logging.ErrAttr(err))
+			s.rdb.ZRem(ctx, s.key, data)
+			continue
+		}
+		job := DeletionJob{
+			FileName:  member.FileName,
+			Sha1:      member.Sha1,
+			ExpiredAt: now,
+			Kind:      member.Kind,
+		}
+		if score, err := strconv.ParseFloat(claimed[i+1], 64); err == nil {
+			job.ExpiredAt = time.UnixMilli(int64(score))
+		}
+
+		err := handler(ctx, job)
+		if errors.Is(err, ErrDeletionJobRejected) {
+			logging.L(ctx).Warn("Drop deletion job", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+			err = nil
+		}
+		retryAt := ""
+		if err != nil {
+			logging.L(ctx).Error("Fail handle deletion job", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+			retryAt = strconv.FormatInt(time.Now().Add(redisDeletionRetryDelay).UnixMilli(), 10)
+		}
+
+		if err := releaseJobScript.Run(ctx, s.rdb, []string{s.key}, data, lease, retryAt).Err(); err != nil {
+			logging.L(ctx).Error("Fail release deletion job", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+		}
+	}
+
+	return len(claimed) / 2
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisDownloadCounter counts downloads by INCR, so the count is shared by
+// all replicas and each download gets its own number.
+type RedisDownloadCounter struct {
+	rdb *redis.Client
+}
+
+func NewRedisDownloadCounter(rdb *redis.Client) DownloadCounter {
+	return &RedisDownloadCounter{rdb: rdb}
+}
+
+func (c *RedisDownloadCounter) Increment(ctx context.Context, metadata *domain.FileMetadata) (int64, error) {
+	key := c.countKey(metadata)
+
+	var incr *redis.IntCmd
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		// The count is dropped with the file
+		if !metadata.IsPermanent() {
+			pipe.ExpireAt(ctx, key, metadata.ExpiredAt)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (c *RedisDownloadCounter) Count(ctx context.Context, metadata *domain.FileMetadata) (int64, error) {
+	count, err := c.rdb.Get(ctx, c.countKey(metadata)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+func (c *RedisDownloadCounter) Reset(ctx context.Context, metadata *domain.FileMetadata) error {
+	return c.rdb.Del(ctx, c.countKey(metadata)).Err()
+}
+
+func (c *RedisDownloadCounter) countKey(metadata *domain.FileMetadata) string {
+	return fmt.Sprintf("%s:downloads:%s", redisKeyPrefix, downloadCounterId(metadata))
+}
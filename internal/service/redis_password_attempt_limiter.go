@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisPasswordAttemptLimiter counts attempts by INCR, so the count is
+// shared by all replicas. The window starts with the first attempt.
+type RedisPasswordAttemptLimiter struct {
+	rdb *redis.Client
+	// name separates the counts of limiters sharing rdb.
+	name   string
+	window time.Duration
+}
+
+func NewRedisPasswordAttemptLimiter(rdb *redis.Client, name string, window time.Duration) PasswordAttemptLimiter {
+	return &RedisPasswordAttemptLimiter{rdb: rdb, name: name, window: window}
+}
+
+func (l *RedisPasswordAttemptLimiter) Attempt(ctx context.Context, key string) (int64, error) {
+	attemptsKey := l.attemptsKey(key)
+
+	var count *redis.IntCmd
+	_, err := l.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, attemptsKey)
+		pipe.ExpireNX(ctx, attemptsKey, l.window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+func (l *RedisPasswordAttemptLimiter) Reset(ctx context.Context, key string) error {
+	return l.rdb.Del(ctx, l.attemptsKey(key)).Err()
+}
+
+func (l *RedisPasswordAttemptLimiter) attemptsKey(key string) string {
+	return fmt.Sprintf("%s:%s:%s", redisKeyPrefix, l.name, key)
+}
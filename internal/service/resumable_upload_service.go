@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+type ResumableUploadService interface {
+	CreateUpload(ctx context.Context, length int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (*domain.ResumableUpload, error)
+	GetUpload(ctx context.Context, id string) (*domain.ResumableUpload, error)
+	// ResumeUpload returns the upload to a client resuming it. The
+	// management token of a completed upload is dropped after it is returned.
+	ResumeUpload(ctx context.Context, id string) (*domain.ResumableUpload, error)
+	AppendUpload(ctx context.Context, id string, offset int64, content io.Reader, size int64) (*domain.ResumableUpload, error)
+	DeleteUpload(ctx context.Context, id string) error
+}
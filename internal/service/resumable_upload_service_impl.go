@@ -0,0 +1,394 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/storage"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+const (
+	resumableUploadDirectory       = "tus"
+	resumableUploadCleanupInterval = 10 * time.Minute
+)
+
+// ResumableUploadServiceImpl keeps the state and the received chunks of
+// unfinished uploads in the file storage. A completed upload is passed to
+// FileHostingService as a regular upload with generative name.
+type ResumableUploadServiceImpl struct {
+	ctx                context.Context
+	fileStorage        storage.FileStorage
+	fileHostingService FileHostingService
+	expiration         time.Duration
+	// expiryPolicy validates the expiry of an upload when it is created.
+	expiryPolicy domain.ExpiryPolicy
+	locks        sync.Map
+}
+
+func NewResumableUploadService(ctx context.Context, fileStorage storage.FileStorage, fileHostingService FileHostingService, expiration time.Duration, expiryPolicy domain.ExpiryPolicy) ResumableUploadService {
+	service := &ResumableUploadServiceImpl{
+		ctx:                ctx,
+		fileStorage:        fileStorage,
+		fileHostingService: fileHostingService,
+		expiration:         expiration,
+		expiryPolicy:       expiryPolicy,
+	}
+
+	go service.deleteExpiredUploads()
+
+	return service
+}
+
+func (s *ResumableUploadServiceImpl) CreateUpload(ctx context.Context, length int64, metadata *domain.FileMetadata, expiry domain.FileExpiry) (*domain.ResumableUpload, error) {
+	if length < 0 {
+		return nil, apperr.ErrBadRequest.WithMessage("Upload length cannot be negative")
+	}
+
+	now := time.Now()
+	if _, err := resolveExpiredAt(s.expiryPolicy, expiry, now); err != nil {
+		return nil, err
+	}
+	upload := &domain.ResumableUpload{
+		Id:        strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Length:    length,
+		Offset:    0,
+		Chunks:    []int64{},
+		Metadata:  metadata,
+		Duration:  expiry.Duration,
+		ExpiresAt: expiry.ExpiresAt,
+		CreatedAt: now,
+		ExpiredAt: now.Add(s.expiration),
+	}
+
+	if err := s.writeUpload(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (s *ResumableUploadServiceImpl) GetUpload(ctx context.Context, id string) (*domain.ResumableUpload, error) {
+	upload, err := s.readUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(upload.ExpiredAt) {
+		s.deleteUpload(ctx, upload)
+		return nil, apperr.ErrGone.WithMessage(fmt.Sprintf("Upload %s expired", id))
+	}
+
+	return upload, nil
+}
+
+func (s *ResumableUploadServiceImpl) ResumeUpload(ctx context.Context, id string) (*domain.ResumableUpload, error) {
+	unlock := s.lock(id)
+	defer unlock()
+
+	upload, err := s.GetUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !upload.IsCompleted() || len(upload.ManagementToken) == 0 {
+		return upload, nil
+	}
+
+	token := upload.ManagementToken
+	upload.ManagementToken = ""
+	if err := s.writeUpload(ctx, upload); err != nil {
+		return nil, err
+	}
+	upload.ManagementToken = token
+
+	return upload, nil
+}
+
+func (s *ResumableUploadServiceImpl) AppendUpload(ctx context.Context, id string, offset int64, content io.Reader, size int64) (*domain.ResumableUpload, error) {
+	unlock := s.lock(id)
+	defer unlock()
+
+	upload, err := s.GetUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Offset != offset {
+		return nil, apperr.ErrConflict.WithMessage(fmt.Sprintf("Upload offset is %d", upload.Offset))
+	}
+
+	remaining := upload.Length - upload.Offset
+	if size > remaining {
+		return nil, apperr.ErrRequestEntityTooLarge.WithMessage("Chunk exceeds upload length")
+	}
+
+	if remaining > 0 {
+		// The size is not passed to the storage, an interrupted request
+		// stores fewer bytes than announced.
+		chunk := s.chunkFile(id, upload.Offset)
+		interrupted := &interruptibleReader{reader: io.LimitReader(content, remaining)}
+		counter := &countingReader{reader: interrupted}
+		if err := s.fileStorage.Write(ctx, chunk, counter, -1, "application/octet-stream"); err != nil {
+			return nil, err
+		}
+		if interrupted.err != nil {
+			logging.L(ctx).Warn("Upload request interrupted", logging.StringAttr("upload", id), logging.ErrAttr(interrupted.err))
+		}
+
+		if counter.count == 0 {
+			s.fileStorage.Delete(ctx, chunk)
+		} else {
+			upload.Chunks = append(upload.Chunks, upload.Offset)
+			upload.Offset += counter.count
+
+			if err := s.writeUpload(ctx, upload); err != nil {
+				s.fileStorage.Delete(ctx, chunk)
+				return nil, err
+			}
+		}
+	}
+
+	if upload.Offset == upload.Length && !upload.IsCompleted() {
+		if err := s.completeUpload(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+
+	return upload, nil
+}
+
+func (s *ResumableUploadServiceImpl) DeleteUpload(ctx context.Context, id string) error {
+	unlock := s.lock(id)
+	defer unlock()
+
+	upload, err := s.readUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.deleteUpload(ctx, upload); err != nil {
+		return err
+	}
+	s.locks.Delete(id)
+
+	return nil
+}
+
+// completeUpload passes the received chunks to FileHostingService. The chunks
+// are dropped afterwards, the state is kept until expiration to answer
+// offset requests of the client and to return the management token to it.
+func (s *ResumableUploadServiceImpl) completeUpload(ctx context.Context, upload *domain.ResumableUpload) error {
+	chunks := make([]string, len(upload.Chunks))
+	for i, offset := range upload.Chunks {
+		chunks[i] = s.chunkFile(upload.Id, offset)
+	}
+
+	content := &chunksReader{ctx: ctx, fileStorage: s.fileStorage, files: chunks}
+	defer content.Close()
+
+	metadata := &domain.FileMetadata{
+		Name:         upload.Metadata.Name,
+		MimeType:     upload.Metadata.MimeType,
+		Meta:         upload.Metadata.Meta,
+		MaxDownloads: upload.Metadata.MaxDownloads,
+		Owner:        upload.Metadata.Owner,
+		Private:      upload.Metadata.Private,
+		PasswordHash: upload.Metadata.PasswordHash,
+	}
+
+	fileName, fileMetadata, err := s.fileHostingService.UploadFileWithGenerativeName(ctx, content, upload.Length, metadata, domain.FileExpiry{Duration: upload.Duration, ExpiresAt: upload.ExpiresAt})
+	if err != nil {
+		return err
+	}
+
+	upload.FileId = fileName
+	upload.ManagementToken = fileMetadata.ManagementToken
+	if err := s.writeUpload(ctx, upload); err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		if err := s.fileStorage.Delete(ctx, chunk); err != nil {
+			logging.L(ctx).Warn("Fail delete upload chunk", logging.StringAttr("chunk", chunk), logging.ErrAttr(err))
+		}
+	}
+
+	return nil
+}
+
+func (s *ResumableUploadServiceImpl) deleteUpload(ctx context.Context, upload *domain.ResumableUpload) error {
+	for _, offset := range upload.Chunks {
+		chunk := s.chunkFile(upload.Id, offset)
+		if !s.fileStorage.IsExist(ctx, chunk) {
+			continue
+		}
+		if err := s.fileStorage.Delete(ctx, chunk); err != nil {
+			return err
+		}
+	}
+
+	return s.fileStorage.Delete(ctx, s.uploadFile(upload.Id))
+}
+
+func (s *ResumableUploadServiceImpl) deleteExpiredUploads() {
+	ticker := time.NewTicker(resumableUploadCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		files, err := s.fileStorage.FilesIn(s.ctx, resumableUploadDirectory)
+		if err != nil {
+			logging.L(s.ctx).Error("Fail list uploads", logging.ErrAttr(err))
+			continue
+		}
+
+		for _, file := range files {
+			id, ok := strings.CutSuffix(file, ".info")
+			if !ok {
+				continue
+			}
+
+			unlock := s.lock(id)
+			upload, err := s.readUpload(s.ctx, id)
+			if err == nil && time.Now().After(upload.ExpiredAt) {
+				if err := s.deleteUpload(s.ctx, upload); err != nil {
+					logging.L(s.ctx).Error("Fail delete expired upload", logging.StringAttr("upload", id), logging.ErrAttr(err))
+				} else {
+					s.locks.Delete(id)
+					logging.L(s.ctx).Info("Delete expired upload", logging.StringAttr("upload", id))
+				}
+			}
+			unlock()
+		}
+	}
+}
+
+func (s *ResumableUploadServiceImpl) readUpload(ctx context.Context, id string) (*domain.ResumableUpload, error) {
+	if strings.Contains(id, "/") || !s.fileStorage.IsExist(ctx, s.uploadFile(id)) {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("Upload %s not found", id))
+	}
+
+	reader, err := s.fileStorage.Read(ctx, s.uploadFile(id))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read upload %s", id))
+	}
+
+	upload, err := domain.NewResumableUploadFromBytes(data)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read upload %s", id))
+	}
+
+	return upload, nil
+}
+
+func (s *ResumableUploadServiceImpl) writeUpload(ctx context.Context, upload *domain.ResumableUpload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return apperr.ErrInternalServerError.WithMessage("Fail serialize upload")
+	}
+
+	if s.fileStorage.IsExist(ctx, s.uploadFile(upload.Id)) {
+		if err := s.fileStorage.Delete(ctx, s.uploadFile(upload.Id)); err != nil {
+			return err
+		}
+	}
+
+	return s.fileStorage.Write(ctx, s.uploadFile(upload.Id), bytes.NewReader(data), int64(len(data)), "application/json")
+}
+
+// lock serializes requests to the same upload within the process.
+func (s *ResumableUploadServiceImpl) lock(id string) func() {
+	value, _ := s.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func (s *ResumableUploadServiceImpl) uploadFile(id string) string {
+	return fmt.Sprintf("%s/%s.info", resumableUploadDirectory, id)
+}
+
+func (s *ResumableUploadServiceImpl) chunkFile(id string, offset int64) string {
+	return fmt.Sprintf("%s/%s.%d", resumableUploadDirectory, id, offset)
+}
+
+// interruptibleReader ends the content at a read error, so the bytes
+// received before a request is interrupted are kept and the client resumes
+// from the offset after them.
+type interruptibleReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *interruptibleReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// chunksReader reads the chunks of an upload one after another, opening
+// each of them only when the previous one is exhausted.
+type chunksReader struct {
+	ctx         context.Context
+	fileStorage storage.FileStorage
+	files       []string
+	current     io.ReadCloser
+}
+
+func (r *chunksReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.files) == 0 {
+				return 0, io.EOF
+			}
+			current, err := r.fileStorage.Read(r.ctx, r.files[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current = current
+			r.files = r.files[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *chunksReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	return r.current.Close()
+}
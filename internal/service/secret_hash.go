@@ -0,0 +1,110 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Parameters of argon2id hashes made by HashSecret, the OWASP recommendation.
+const (
+	argon2Time    = 2
+	argon2Memory  = 19 * 1024
+	argon2Threads = 1
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// HashSecret hashes secret by argon2id in the PHC string format
+// "$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>".
+func HashSecret(secret string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	hash := argon2.IDKey([]byte(secret), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		argon2Memory,
+		argon2Time,
+		argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// VerifySecret tells whether secret matches hash, an argon2id PHC string or
+// a bcrypt hash. Hashes are compared in constant time.
+func VerifySecret(secret string, hash string) bool {
+	if strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$") {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
+	}
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	actual := argon2.IDKey([]byte(secret), salt, time, memory, threads, uint32(len(expected)))
+
+	return subtle.ConstantTimeCompare(actual, expected) == 1
+}
+
+// IsSecretHash tells whether hash is in a format VerifySecret accepts.
+func IsSecretHash(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$") || strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// tokenSize is the size of generated tokens in bytes.
+const tokenSize = 32
+
+// generateToken returns a random token with its hash. Tokens have enough
+// entropy to be hashed by SHA-256 instead of a slow hash.
+func generateToken() (string, string, error) {
+	token := make([]byte, tokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return "", "", err
+	}
+	encoded := hex.EncodeToString(token)
+	return encoded, hashToken(encoded), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// verifyToken tells whether token matches hash, compared in constant time.
+func verifyToken(token string, hash string) bool {
+	return len(hash) > 0 && subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(hash)) == 1
+}
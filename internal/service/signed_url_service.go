@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+type SignedURLService interface {
+	// Sign returns a URL to read file for lifetime, a duration like of the
+	// expiry, or the default lifetime when it is empty. Not empty ip and
+	// method bind the URL to the client and the method of the request.
+	Sign(ctx context.Context, file string, lifetime string, ip string, method string) (*domain.SignedURL, error)
+	// Verify rejects the query of a request reading file which has no valid
+	// signature, an expired one or one bound to another ip or method with
+	// apperr.ErrForbidden.
+	Verify(ctx context.Context, file string, query url.Values, ip string, method string) error
+}
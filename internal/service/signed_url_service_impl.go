@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// Query parameters of signed URLs.
+const (
+	signedURLExpires   = "expires"
+	signedURLIP        = "ip"
+	signedURLMethod    = "method"
+	signedURLKeyId     = "key_id"
+	signedURLSignature = "signature"
+)
+
+type SignedURLServiceImpl struct {
+	fileHostingService FileHostingService
+	origin             string
+	// keys sign by the first key and verify by any of them.
+	keys            []*domain.SigningKey
+	defaultLifetime time.Duration
+	maxLifetime     time.Duration
+}
+
+var _ SignedURLService = (*SignedURLServiceImpl)(nil)
+
+// NewSignedURLService signs URLs of files under origin.
+func NewSignedURLService(fileHostingService FileHostingService, origin string, keys []*domain.SigningKey, defaultLifetime time.Duration, maxLifetime time.Duration) (*SignedURLServiceImpl, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("signing key is required")
+	}
+
+	return &SignedURLServiceImpl{
+		fileHostingService: fileHostingService,
+		origin:             origin,
+		keys:               keys,
+		defaultLifetime:    defaultLifetime,
+		maxLifetime:        maxLifetime,
+	}, nil
+}
+
+// IsSignedURL tells whether query carries a signature.
+func IsSignedURL(query url.Values) bool {
+	return query.Has(signedURLSignature)
+}
+
+func (s *SignedURLServiceImpl) Sign(ctx context.Context, file string, rawLifetime string, ip string, method string) (*domain.SignedURL, error) {
+	lifetime := s.defaultLifetime
+	if len(rawLifetime) > 0 {
+		var err error
+		lifetime, err = parseDuration(rawLifetime)
+		if err != nil {
+			return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Invalid lifetime: %s", rawLifetime))
+		}
+	}
+	if lifetime < time.Second || lifetime > s.maxLifetime {
+		return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Lifetime must be from 1s to %s", s.maxLifetime))
+	}
+
+	if len(ip) > 0 {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return nil, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Invalid ip: %s", ip))
+		}
+		ip = parsed.String()
+	}
+
+	method = strings.ToUpper(method)
+	if len(method) > 0 && method != http.MethodGet && method != http.MethodHead {
+		return nil, apperr.ErrBadRequest.WithMessage("Method must be GET or HEAD")
+	}
+
+	if _, err := s.fileHostingService.GetFileMetadata(ctx, file); err != nil {
+		return nil, err
+	}
+
+	key := s.keys[0]
+	expiresAt := time.Now().Add(lifetime).Truncate(time.Second)
+
+	query := url.Values{}
+	query.Set(signedURLExpires, strconv.FormatInt(expiresAt.Unix(), 10))
+	if len(ip) > 0 {
+		query.Set(signedURLIP, ip)
+	}
+	if len(method) > 0 {
+		query.Set(signedURLMethod, method)
+	}
+	query.Set(signedURLKeyId, key.Id)
+	query.Set(signedURLSignature, signURL(key, file, query))
+
+	return &domain.SignedURL{
+		Url:       fmt.Sprintf("%s/%s?%s", s.origin, url.PathEscape(file), query.Encode()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *SignedURLServiceImpl) Verify(ctx context.Context, file string, query url.Values, ip string, method string) error {
+	var key *domain.SigningKey
+	for _, k := range s.keys {
+		if k.Id == query.Get(signedURLKeyId) {
+			key = k
+			break
+		}
+	}
+	if key == nil || !hmac.Equal([]byte(signURL(key, file, query)), []byte(query.Get(signedURLSignature))) {
+		return apperr.ErrForbidden.WithMessage("Invalid signature")
+	}
+
+	expires, err := strconv.ParseInt(query.Get(signedURLExpires), 10, 64)
+	if err != nil || !time.Now().Before(time.Unix(expires, 0)) {
+		return apperr.ErrForbidden.WithMessage("Signed url expired")
+	}
+
+	if signedIP := query.Get(signedURLIP); len(signedIP) > 0 && !net.ParseIP(signedIP).Equal(net.ParseIP(ip)) {
+		return apperr.ErrForbidden.WithMessage("Signed url is bound to another ip")
+	}
+
+	if signedMethod := query.Get(signedURLMethod); len(signedMethod) > 0 && signedMethod != method {
+		return apperr.ErrForbidden.WithMessage("Signed url is bound to another method")
+	}
+
+	return nil
+}
+
+// signURL returns the HMAC-SHA256 by key of file and the signed parameters
+// of query.
+func signURL(key *domain.SigningKey, file string, query url.Values) string {
+	mac := hmac.New(sha256.New, []byte(key.Secret))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", file, query.Get(signedURLExpires), query.Get(signedURLIP), query.Get(signedURLMethod))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
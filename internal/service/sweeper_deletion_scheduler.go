@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/storage"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+)
+
+// SweeperDeletionScheduler runs in process without a broker. Every interval
+// it scans the metadata for expired files, so deletions of files survive
+// restarts. Other jobs, e.g. of versions retained for a while, are kept in
+// memory and are lost on restart.
+type SweeperDeletionScheduler struct {
+	metadataStore storage.MetadataStore
+	interval      time.Duration
+	mu            sync.Mutex
+	// jobs are keyed by file, sha1 and kind, so a job scheduled again
+	// replaces the previous one.
+	jobs map[sweeperJobKey]DeletionJob
+}
+
+type sweeperJobKey struct {
+	fileName string
+	sha1     string
+	kind     DeletionJobKind
+}
+
+func NewSweeperDeletionScheduler(metadataStore storage.MetadataStore, interval time.Duration) DeletionScheduler {
+	return &SweeperDeletionScheduler{
+		metadataStore: metadataStore,
+		interval:      interval,
+		jobs:          make(map[sweeperJobKey]DeletionJob),
+	}
+}
+
+func (s *SweeperDeletionScheduler) Schedule(ctx context.Context, job DeletionJob) error {
+	// Deletions of hosted files are found by the scan
+	if strings.Contains(job.FileName, "/") || job.Kind != DeletionJobDelete {
+		s.mu.Lock()
+		s.jobs[sweeperJobKey{fileName: job.FileName, sha1: job.Sha1, kind: job.Kind}] = job
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (s *SweeperDeletionScheduler) Consume(ctx context.Context, handler DeletionHandler) error {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.sweep(ctx, handler)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *SweeperDeletionScheduler) sweep(ctx context.Context, handler DeletionHandler) {
+	now := time.Now()
+
+	query := &domain.FileQuery{
+		ExpiredBefore: now,
+		Sort:          domain.FileSortCreatedAt,
+		Limit:         domain.MaxFileQueryLimit,
+	}
+	for {
+		page, err := s.metadataStore.Query(ctx, query)
+		if err != nil {
+			logging.L(ctx).Error("Fail query expired files", logging.ErrAttr(err))
+			break
+		}
+
+		for _, metadata := range page.Files {
+			job := DeletionJob{FileName: metadata.Id, Sha1: metadata.Sha1, ExpiredAt: metadata.ExpiredAt}
+			if err := handler(ctx, job); err != nil {
+				logging.L(ctx).Error("Fail handle deletion job", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+			}
+		}
+
+		if len(page.NextCursor) == 0 {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	s.mu.Lock()
+	dueJobs := []DeletionJob{}
+	for key, job := range s.jobs {
+		if !now.Before(job.ExpiredAt) {
+			dueJobs = append(dueJobs, job)
+			delete(s.jobs, key)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, job := range dueJobs {
+		err := handler(ctx, job)
+		if errors.Is(err, ErrDeletionJobRejected) {
+			logging.L(ctx).Warn("Drop deletion job", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+			continue
+		}
+		if err != nil {
+			logging.L(ctx).Error("Fail handle deletion job", logging.StringAttr("file", job.FileName), logging.ErrAttr(err))
+			s.Schedule(ctx, job)
+		}
+	}
+}
@@ -1,28 +1,144 @@
 package service
 
-import "time"
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-const defaultFileDuration = time.Hour
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
 
-func parseDuration(raw string, allowInfinite ...bool) time.Duration {
-	isAllowedInfinite := false
-	if len(allowInfinite) > 0 {
-		isAllowedInfinite = allowInfinite[0]
+var (
+	isoDurationRegexp = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)Y)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	// isoDurationUnits are the units of the groups of isoDurationRegexp,
+	// years and months are approximated by 365 and 30 days.
+	isoDurationUnits = []time.Duration{
+		365 * 24 * time.Hour,
+		30 * 24 * time.Hour,
+		7 * 24 * time.Hour,
+		24 * time.Hour,
+		time.Hour,
+		time.Minute,
+		time.Second,
 	}
+	daysRegexp = regexp.MustCompile(`^(\d+)([dw])$`)
+)
 
-	if raw == "-1" && isAllowedInfinite {
-		return 0
-	} else if raw == "5m" {
-		return 5 * time.Minute
-	} else if raw == "30m" {
-		return 30 * time.Minute
-	} else if raw == "1h" || raw == "60m" {
-		return time.Hour
-	} else if raw == "1d" || raw == "24h" {
-		return 24 * time.Hour
-	} else if raw == "1w" || raw == "7d" {
-		return 7 * 24 * time.Hour
+// parseDuration parses a Go duration, an ISO 8601 duration or whole days or
+// weeks.
+func parseDuration(raw string) (time.Duration, error) {
+	if matches := daysRegexp.FindStringSubmatch(raw); matches != nil {
+		count, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		unit := 24 * time.Hour
+		if matches[2] == "w" {
+			unit *= 7
+		}
+		if count > math.MaxInt64/int64(unit) {
+			return 0, fmt.Errorf("duration %s is too long", raw)
+		}
+		return time.Duration(count) * unit, nil
 	}
 
-	return defaultFileDuration
+	if strings.HasPrefix(raw, "P") {
+		matches := isoDurationRegexp.FindStringSubmatch(raw)
+		if matches == nil || raw == "P" || strings.HasSuffix(raw, "T") {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %s", raw)
+		}
+		var duration time.Duration
+		for i, unit := range isoDurationUnits {
+			if len(matches[i+1]) == 0 {
+				continue
+			}
+			value, err := strconv.ParseFloat(matches[i+1], 64)
+			if err != nil {
+				return 0, err
+			}
+			// float64(math.MaxInt64) rounds up to 2^63, which overflows
+			part := value * float64(unit)
+			if part >= float64(math.MaxInt64) || time.Duration(part) > math.MaxInt64-duration {
+				return 0, fmt.Errorf("duration %s is too long", raw)
+			}
+			duration += time.Duration(part)
+		}
+		return duration, nil
+	}
+
+	return time.ParseDuration(raw)
+}
+
+// resolveExpiredAt resolves expiry requested at now within the limits of policy.
+// Invalid and out of limits values are rejected with apperr.ErrBadRequest.
+func resolveExpiredAt(policy domain.ExpiryPolicy, expiry domain.FileExpiry, now time.Time) (time.Time, error) {
+	var duration time.Duration
+	switch {
+	case !expiry.ExpiresAt.IsZero():
+		if len(expiry.Duration) > 0 {
+			return time.Time{}, apperr.ErrBadRequest.WithMessage("Set either duration or expires_at")
+		}
+		duration = expiry.ExpiresAt.Sub(now)
+		if duration <= 0 {
+			return time.Time{}, apperr.ErrBadRequest.WithMessage("expires_at must be in the future")
+		}
+	case expiry.Duration == domain.PermanentDuration:
+		if policy.Max > 0 {
+			return time.Time{}, apperr.ErrBadRequest.WithMessage("Permanent files are not allowed")
+		}
+		return domain.PermanentExpiredAt, nil
+	case len(expiry.Duration) == 0:
+		duration = policy.Default
+	default:
+		parsed, err := parseDuration(expiry.Duration)
+		if err != nil || parsed <= 0 {
+			return time.Time{}, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Invalid duration %s", expiry.Duration))
+		}
+		duration = parsed
+	}
+
+	if duration < policy.Min {
+		return time.Time{}, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Duration must be at least %s", policy.Min))
+	}
+	if policy.Max > 0 && duration > policy.Max {
+		return time.Time{}, apperr.ErrBadRequest.WithMessage(fmt.Sprintf("Duration must be at most %s", policy.Max))
+	}
+
+	if !expiry.ExpiresAt.IsZero() {
+		return expiry.ExpiresAt, nil
+	}
+	return now.Add(duration), nil
+}
+
+// sniffLen is the amount of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// burnOnClose calls burn once after the content is closed.
+type burnOnClose struct {
+	io.ReadSeekCloser
+	burn func()
+	once sync.Once
+}
+
+func (b *burnOnClose) Close() error {
+	err := b.ReadSeekCloser.Close()
+	b.once.Do(b.burn)
+	return err
 }
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// WebhookNotifier sends events of files to webhooks.
+type WebhookNotifier interface {
+	// Notify queues event for the subscriptions of its type and returns
+	// without waiting for the deliveries.
+	Notify(ctx context.Context, event *domain.WebhookEvent)
+	// LeadTime is how long before a file expires domain.WebhookFileExpiring
+	// is sent, 0 for never.
+	LeadTime() time.Duration
+}
+
+type WebhookService interface {
+	WebhookNotifier
+	// GetSubscriptions returns the subscriptions without their secrets.
+	GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	// CreateSubscription generates the secret when it is empty. The secret is
+	// returned only by this call.
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	// GetDeliveries returns the latest deliveries to the subscription, newest
+	// first.
+	GetDeliveries(ctx context.Context, id string) ([]*domain.WebhookDelivery, error)
+}
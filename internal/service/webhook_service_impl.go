@@ -0,0 +1,479 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/storage"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+const (
+	webhookDirectory = "webhooks"
+	// webhookRefreshInterval is how often subscriptions created by other
+	// instances are loaded.
+	webhookRefreshInterval = time.Minute
+	webhookQueueSize       = 1024
+	// webhookDeliveryLogSize is how many deliveries are kept per subscription.
+	webhookDeliveryLogSize = 100
+	webhookSecretSize      = 32
+
+	WebhookIdHeader        = "X-Webhook-Id"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader holds "sha256=" and the hex HMAC-SHA256 of
+	// "<timestamp>.<body>" by the secret of the subscription.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookRetryPolicy bounds the retries of a failed delivery. The delay
+// before retry n is Backoff doubled n-1 times, up to MaxBackoff.
+type WebhookRetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func (p WebhookRetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxBackoff)
+}
+
+// WebhookServiceImpl sends events by a queue drained by workers. Failed
+// deliveries are retried in memory, so retries pending on shutdown are
+// lost. Subscriptions created by the API are kept in the file storage, the
+// delivery log is kept in memory of the instance which sent them.
+type WebhookServiceImpl struct {
+	ctx         context.Context
+	fileStorage storage.FileStorage
+	client      *http.Client
+	leadTime    time.Duration
+	retry       WebhookRetryPolicy
+	static      []*domain.WebhookSubscription
+	queue       chan *webhookDelivery
+
+	mu            sync.RWMutex
+	subscriptions []*domain.WebhookSubscription
+	deliveries    map[string][]*domain.WebhookDelivery
+}
+
+type webhookDelivery struct {
+	subscription *domain.WebhookSubscription
+	event        *domain.WebhookEvent
+	body         []byte
+	attempt      int
+}
+
+// NewWebhookService sends events to the static subscriptions of the config
+// and to the ones created by the API.
+func NewWebhookService(ctx context.Context, fileStorage storage.FileStorage, static []*domain.WebhookSubscription, workers int, timeout time.Duration, leadTime time.Duration, retry WebhookRetryPolicy) (WebhookService, error) {
+	now := time.Now()
+	for i, subscription := range static {
+		if err := validateWebhookEvents(subscription.Events); err != nil {
+			return nil, fmt.Errorf("subscription %s: %w", subscription.Url, err)
+		}
+		subscription.Id = fmt.Sprintf("static-%d", i+1)
+		subscription.Static = true
+		subscription.CreatedAt = now
+	}
+
+	service := &WebhookServiceImpl{
+		ctx:         ctx,
+		fileStorage: fileStorage,
+		client:      &http.Client{Timeout: timeout},
+		leadTime:    leadTime,
+		retry:       retry,
+		static:      static,
+		queue:       make(chan *webhookDelivery, webhookQueueSize),
+		deliveries:  make(map[string][]*domain.WebhookDelivery),
+	}
+
+	if err := service.loadSubscriptions(ctx); err != nil {
+		return nil, err
+	}
+
+	for range workers {
+		go service.work()
+	}
+	go service.refreshSubscriptions()
+
+	return service, nil
+}
+
+func (s *WebhookServiceImpl) LeadTime() time.Duration {
+	return s.leadTime
+}
+
+func (s *WebhookServiceImpl) Notify(ctx context.Context, event *domain.WebhookEvent) {
+	event.Id = uuid.NewString()
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	var subscriptions []*domain.WebhookSubscription
+	s.mu.RLock()
+	for _, subscription := range s.subscriptions {
+		if subscription.Accepts(event.Type) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	s.mu.RUnlock()
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		logging.L(ctx).Error("Fail serialize webhook event", logging.StringAttr("event", string(event.Type)), logging.ErrAttr(err))
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		s.enqueue(ctx, &webhookDelivery{
+			subscription: subscription,
+			event:        event,
+			body:         body,
+			attempt:      1,
+		})
+	}
+}
+
+func (s *WebhookServiceImpl) enqueue(ctx context.Context, delivery *webhookDelivery) {
+	select {
+	case s.queue <- delivery:
+	default:
+		logging.L(ctx).Error(
+			"Drop webhook delivery, the queue is full",
+			logging.StringAttr("subscription", delivery.subscription.Id),
+			logging.StringAttr("event", string(delivery.event.Type)),
+		)
+	}
+}
+
+func (s *WebhookServiceImpl) work() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case delivery := <-s.queue:
+			s.deliver(delivery)
+		}
+	}
+}
+
+// deliver sends delivery once, records the attempt and schedules a retry
+// when it failed.
+func (s *WebhookServiceImpl) deliver(delivery *webhookDelivery) {
+	attemptedAt := time.Now()
+	statusCode, err := s.send(delivery)
+
+	record := &domain.WebhookDelivery{
+		Id:             uuid.NewString(),
+		SubscriptionId: delivery.subscription.Id,
+		EventId:        delivery.event.Id,
+		EventType:      delivery.event.Type,
+		FileId:         delivery.event.FileId,
+		Attempt:        delivery.attempt,
+		StatusCode:     statusCode,
+		Succeeded:      err == nil,
+		Duration:       time.Since(attemptedAt).String(),
+		AttemptedAt:    attemptedAt,
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+		if delivery.attempt <= s.retry.MaxRetries {
+			delay := s.retry.delay(delivery.attempt)
+			nextAttemptAt := time.Now().Add(delay)
+			record.NextAttemptAt = &nextAttemptAt
+			time.AfterFunc(delay, func() {
+				if s.ctx.Err() != nil || !s.hasSubscription(delivery.subscription.Id) {
+					return
+				}
+				s.enqueue(s.ctx, &webhookDelivery{
+					subscription: delivery.subscription,
+					event:        delivery.event,
+					body:         delivery.body,
+					attempt:      delivery.attempt + 1,
+				})
+			})
+		} else {
+			logging.L(s.ctx).Error(
+				"Give up webhook delivery",
+				logging.StringAttr("subscription", delivery.subscription.Id),
+				logging.StringAttr("event", string(delivery.event.Type)),
+				logging.ErrAttr(err),
+			)
+		}
+	}
+
+	s.mu.Lock()
+	deliveries := append(s.deliveries[delivery.subscription.Id], record)
+	if len(deliveries) > webhookDeliveryLogSize {
+		deliveries = slices.Clone(deliveries[len(deliveries)-webhookDeliveryLogSize:])
+	}
+	s.deliveries[delivery.subscription.Id] = deliveries
+	s.mu.Unlock()
+}
+
+// send posts the event of delivery and returns the status code of the
+// response. Responses other than 2xx fail the delivery.
+func (s *WebhookServiceImpl) send(delivery *webhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, delivery.subscription.Url, bytes.NewReader(delivery.body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "File-Hosting")
+	req.Header.Set(WebhookIdHeader, delivery.event.Id)
+	req.Header.Set(WebhookEventHeader, string(delivery.event.Type))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(delivery.subscription.Secret, timestamp, delivery.body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drained, so the connection is reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>" by secret,
+// receivers compare it with WebhookSignatureHeader.
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *WebhookServiceImpl) GetSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscriptions := make([]*domain.WebhookSubscription, 0, len(s.subscriptions))
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, withoutSecret(subscription))
+	}
+
+	return subscriptions, nil
+}
+
+func (s *WebhookServiceImpl) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	u, err := url.Parse(subscription.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return nil, apperr.ErrBadRequest.WithMessage("Url must be an absolute http or https url")
+	}
+	if err := validateWebhookEvents(subscription.Events); err != nil {
+		return nil, apperr.ErrBadRequest.WithMessage(err.Error())
+	}
+
+	secret := subscription.Secret
+	if len(secret) == 0 {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, apperr.ErrInternalServerError.WithMessage("Fail generate secret")
+		}
+	}
+
+	created := &domain.WebhookSubscription{
+		Id:        uuid.NewString(),
+		Url:       subscription.Url,
+		Secret:    secret,
+		Events:    slices.Compact(slices.Sorted(slices.Values(subscription.Events))),
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail serialize subscription")
+	}
+	if err := s.fileStorage.Write(ctx, s.subscriptionFile(created.Id), bytes.NewReader(data), int64(len(data)), "application/json"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.subscriptions = append(s.subscriptions, created)
+	s.mu.Unlock()
+
+	return created, nil
+}
+
+func (s *WebhookServiceImpl) DeleteSubscription(ctx context.Context, id string) error {
+	subscription := s.getSubscription(id)
+	if subscription == nil {
+		return apperr.ErrNotFound.WithMessage(fmt.Sprintf("Subscription %s not found", id))
+	}
+	if subscription.Static {
+		return apperr.ErrForbidden.WithMessage(fmt.Sprintf("Subscription %s is set in the config", id))
+	}
+
+	if err := s.fileStorage.Delete(ctx, s.subscriptionFile(id)); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.subscriptions = slices.DeleteFunc(s.subscriptions, func(subscription *domain.WebhookSubscription) bool {
+		return subscription.Id == id
+	})
+	delete(s.deliveries, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *WebhookServiceImpl) GetDeliveries(ctx context.Context, id string) ([]*domain.WebhookDelivery, error) {
+	if !s.hasSubscription(id) {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("Subscription %s not found", id))
+	}
+
+	s.mu.RLock()
+	deliveries := slices.Clone(s.deliveries[id])
+	s.mu.RUnlock()
+
+	slices.Reverse(deliveries)
+	if deliveries == nil {
+		deliveries = []*domain.WebhookDelivery{}
+	}
+
+	return deliveries, nil
+}
+
+func (s *WebhookServiceImpl) getSubscription(id string) *domain.WebhookSubscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, subscription := range s.subscriptions {
+		if subscription.Id == id {
+			return subscription
+		}
+	}
+	return nil
+}
+
+func (s *WebhookServiceImpl) hasSubscription(id string) bool {
+	return s.getSubscription(id) != nil
+}
+
+func (s *WebhookServiceImpl) refreshSubscriptions() {
+	ticker := time.NewTicker(webhookRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.loadSubscriptions(s.ctx); err != nil {
+			logging.L(s.ctx).Error("Fail load webhook subscriptions", logging.ErrAttr(err))
+		}
+	}
+}
+
+// loadSubscriptions reads the subscriptions created by the API, oldest
+// first, after the static ones.
+func (s *WebhookServiceImpl) loadSubscriptions(ctx context.Context) error {
+	files, err := s.fileStorage.FilesIn(ctx, webhookDirectory)
+	if err != nil {
+		return err
+	}
+
+	stored := []*domain.WebhookSubscription{}
+	for _, file := range files {
+		id, ok := strings.CutSuffix(file, ".json")
+		if !ok {
+			continue
+		}
+
+		subscription, err := s.readSubscription(ctx, id)
+		if err != nil {
+			logging.L(ctx).Error("Fail read webhook subscription", logging.StringAttr("subscription", id), logging.ErrAttr(err))
+			continue
+		}
+		stored = append(stored, subscription)
+	}
+
+	slices.SortFunc(stored, func(a, b *domain.WebhookSubscription) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	s.mu.Lock()
+	s.subscriptions = append(slices.Clone(s.static), stored...)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *WebhookServiceImpl) readSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	reader, err := s.fileStorage.Read(ctx, s.subscriptionFile(id))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewWebhookSubscriptionFromBytes(data)
+}
+
+func (s *WebhookServiceImpl) subscriptionFile(id string) string {
+	return fmt.Sprintf("%s/%s.json", webhookDirectory, id)
+}
+
+func withoutSecret(subscription *domain.WebhookSubscription) *domain.WebhookSubscription {
+	copied := *subscription
+	copied.Secret = ""
+	return &copied
+}
+
+func validateWebhookEvents(events []domain.WebhookEventType) error {
+	for _, event := range events {
+		if !event.IsValid() {
+			return fmt.Errorf("unknown event %s", event)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
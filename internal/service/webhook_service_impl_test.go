@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/internal/storage"
+)
+
+type webhookRequest struct {
+	header     http.Header
+	body       []byte
+	receivedAt time.Time
+}
+
+// webhookReceiver records requests and answers them with the status codes of
+// statuses in order, the last one repeats.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []webhookRequest
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	status := r.statuses[min(len(r.requests), len(r.statuses)-1)]
+	r.requests = append(r.requests, webhookRequest{header: req.Header.Clone(), body: body, receivedAt: time.Now()})
+	r.mu.Unlock()
+
+	w.WriteHeader(status)
+}
+
+func (r *webhookReceiver) received() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]webhookRequest(nil), r.requests...)
+}
+
+func newTestWebhookService(t *testing.T, url string, secret string, retry WebhookRetryPolicy) WebhookService {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	static := []*domain.WebhookSubscription{{Url: url, Secret: secret}}
+	service, err := NewWebhookService(ctx, storage.NewBasicFileStorage(t.TempDir()), static, 1, time.Second, time.Hour, retry)
+	if err != nil {
+		t.Fatalf("NewWebhookService: %v", err)
+	}
+	return service
+}
+
+// waitDeliveries polls the delivery log of the first static subscription
+// until it has count deliveries.
+func waitDeliveries(t *testing.T, service WebhookService, count int) []*domain.WebhookDelivery {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := service.GetDeliveries(context.Background(), "static-1")
+		if err != nil {
+			t.Fatalf("GetDeliveries: %v", err)
+		}
+		if len(deliveries) >= count {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d deliveries, want %d", len(deliveries), count)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	receiver := &webhookReceiver{statuses: []int{http.StatusOK}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	service := newTestWebhookService(t, server.URL, "secret", WebhookRetryPolicy{})
+	service.Notify(context.Background(), &domain.WebhookEvent{Type: domain.WebhookFileUploaded, FileId: "a.txt"})
+	waitDeliveries(t, service, 1)
+
+	requests := receiver.received()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	request := requests[0]
+
+	timestamp := request.header.Get(WebhookTimestampHeader)
+	if len(timestamp) == 0 {
+		t.Fatal("missing timestamp header")
+	}
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(request.body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := request.header.Get(WebhookSignatureHeader); got != want {
+		t.Errorf("signature %q, want %q", got, want)
+	}
+
+	if got := request.header.Get(WebhookEventHeader); got != string(domain.WebhookFileUploaded) {
+		t.Errorf("event header %q, want %q", got, domain.WebhookFileUploaded)
+	}
+	if len(request.header.Get(WebhookIdHeader)) == 0 {
+		t.Error("missing id header")
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	receiver := &webhookReceiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	retry := WebhookRetryPolicy{MaxRetries: 3, Backoff: 50 * time.Millisecond, MaxBackoff: time.Second}
+	service := newTestWebhookService(t, server.URL, "secret", retry)
+	service.Notify(context.Background(), &domain.WebhookEvent{Type: domain.WebhookFileDeleted, FileId: "a.txt"})
+
+	deliveries := waitDeliveries(t, service, 3)
+	if len(deliveries) != 3 {
+		t.Fatalf("got %d deliveries, want 3", len(deliveries))
+	}
+
+	// The log is newest first
+	wantStatuses := []int{http.StatusNoContent, http.StatusBadGateway, http.StatusInternalServerError}
+	for i, delivery := range deliveries {
+		if delivery.Attempt != 3-i {
+			t.Errorf("delivery %d: attempt %d, want %d", i, delivery.Attempt, 3-i)
+		}
+		if delivery.StatusCode != wantStatuses[i] {
+			t.Errorf("delivery %d: status %d, want %d", i, delivery.StatusCode, wantStatuses[i])
+		}
+		if delivery.EventType != domain.WebhookFileDeleted || delivery.FileId != "a.txt" {
+			t.Errorf("delivery %d: event %s of %s", i, delivery.EventType, delivery.FileId)
+		}
+		succeeded := i == 0
+		if delivery.Succeeded != succeeded {
+			t.Errorf("delivery %d: succeeded %t, want %t", i, delivery.Succeeded, succeeded)
+		}
+		if (delivery.NextAttemptAt != nil) == succeeded {
+			t.Errorf("delivery %d: next attempt %v", i, delivery.NextAttemptAt)
+		}
+		if delivery.EventId != deliveries[0].EventId {
+			t.Errorf("delivery %d: event id %s, want %s", i, delivery.EventId, deliveries[0].EventId)
+		}
+	}
+
+	// Retries wait for the doubled backoff
+	requests := receiver.received()
+	if gap := requests[1].receivedAt.Sub(requests[0].receivedAt); gap < retry.Backoff {
+		t.Errorf("first retry after %s, want at least %s", gap, retry.Backoff)
+	}
+	if gap := requests[2].receivedAt.Sub(requests[1].receivedAt); gap < 2*retry.Backoff {
+		t.Errorf("second retry after %s, want at least %s", gap, 2*retry.Backoff)
+	}
+}
+
+func TestWebhookGiveUp(t *testing.T) {
+	receiver := &webhookReceiver{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	retry := WebhookRetryPolicy{MaxRetries: 1, Backoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	service := newTestWebhookService(t, server.URL, "secret", retry)
+	service.Notify(context.Background(), &domain.WebhookEvent{Type: domain.WebhookFileExpired, FileId: "a.txt"})
+
+	deliveries := waitDeliveries(t, service, 2)
+	time.Sleep(100 * time.Millisecond)
+
+	if got := len(receiver.received()); got != 2 {
+		t.Fatalf("got %d requests, want 2", got)
+	}
+	last := deliveries[0]
+	if last.Succeeded || last.Attempt != 2 || last.NextAttemptAt != nil || len(last.Error) == 0 {
+		t.Errorf("last delivery %+v, want a failed second attempt without retry", last)
+	}
+}
+
+func TestWebhookRetryPolicyDelay(t *testing.T) {
+	policy := WebhookRetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+
+	for i, delay := range want {
+		if got := policy.delay(i + 1); got != delay {
+			t.Errorf("delay of retry %d is %s, want %s", i+1, got, delay)
+		}
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"log"
 	"os"
 	"path"
-	"strings"
 
 	"github.com/bruhabruh/file-hosting/internal/app/apperr"
 	"github.com/bruhabruh/file-hosting/pkg/logging"
@@ -51,7 +50,7 @@ func (s *BasicFileStorage) Files(ctx context.Context) ([]string, error) {
 		if entry.IsDir() {
 			continue
 		}
-		if strings.HasSuffix(entry.Name(), ".metadata") {
+		if isInternalFile(entry.Name()) {
 			continue
 		}
 		files = append(files, entry.Name())
@@ -60,7 +59,28 @@ func (s *BasicFileStorage) Files(ctx context.Context) ([]string, error) {
 	return files, nil
 }
 
-func (s *BasicFileStorage) Read(ctx context.Context, file string) ([]byte, error) {
+func (s *BasicFileStorage) FilesIn(ctx context.Context, directory string) ([]string, error) {
+	entries, err := os.ReadDir(s.path(directory))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read directory")
+	}
+
+	files := []string{}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		files = append(files, entry.Name())
+	}
+
+	return files, nil
+}
+
+func (s *BasicFileStorage) Read(ctx context.Context, file string) (io.ReadSeekCloser, error) {
 	if !s.IsExist(ctx, file) {
 		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
 	}
@@ -70,22 +90,38 @@ func (s *BasicFileStorage) Read(ctx context.Context, file string) ([]byte, error
 		logging.L(ctx).Error(fmt.Sprintf("Fail open file %s", file), logging.ErrAttr(err))
 		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail open file %s", file))
 	}
-	defer f.Close()
 
-	data, err := io.ReadAll(f)
+	return f, nil
+}
+
+func (s *BasicFileStorage) ReadRange(ctx context.Context, file string, offset int64, length int64) (io.ReadCloser, error) {
+	f, err := s.Read(ctx, file)
 	if err != nil {
-		logging.L(ctx).Error(fmt.Sprintf("Fail read file %s", file), logging.ErrAttr(err))
+		return nil, err
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		logging.L(ctx).Error(fmt.Sprintf("Fail seek file %s", file), logging.ErrAttr(err))
 		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read file %s", file))
 	}
 
-	return data, nil
+	return &limitedReadCloser{
+		Reader: io.LimitReader(f, length),
+		Closer: f,
+	}, nil
 }
 
-func (s *BasicFileStorage) Write(ctx context.Context, file string, data []byte, contentType string) error {
+func (s *BasicFileStorage) Write(ctx context.Context, file string, reader io.Reader, size int64, contentType string) error {
 	if s.IsExist(ctx, file) {
 		return apperr.ErrConflict.WithMessage(fmt.Sprintf("File %s already exist", file))
 	}
 
+	if err := os.MkdirAll(path.Dir(s.path(file)), os.ModePerm); err != nil {
+		logging.L(ctx).Error(fmt.Sprintf("Fail create directory of file %s", file), logging.ErrAttr(err))
+		return apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail create file %s", file))
+	}
+
 	f, err := os.Create(s.path(file))
 	if err != nil {
 		logging.L(ctx).Error(fmt.Sprintf("Fail create file %s", file), logging.ErrAttr(err))
@@ -93,9 +129,11 @@ func (s *BasicFileStorage) Write(ctx context.Context, file string, data []byte,
 	}
 	defer f.Close()
 
-	_, err = f.Write(data)
+	_, err = io.Copy(f, reader)
 	if err != nil {
 		logging.L(ctx).Error(fmt.Sprintf("Fail write file %s", file), logging.ErrAttr(err))
+		f.Close()
+		os.Remove(s.path(file))
 		return apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail write to file %s", file))
 	}
 
@@ -107,6 +145,11 @@ func (s *BasicFileStorage) Move(ctx context.Context, file string, newFile string
 		return apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
 	}
 
+	if err := os.MkdirAll(path.Dir(s.path(newFile)), os.ModePerm); err != nil {
+		logging.L(ctx).Error(fmt.Sprintf("Fail create directory of file %s", newFile), logging.ErrAttr(err))
+		return apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail move file %s to %s", file, newFile))
+	}
+
 	err := os.Rename(s.path(file), s.path(newFile))
 	if err != nil {
 		logging.L(ctx).Error(fmt.Sprintf("Fail move file %s to %s", file, newFile), logging.ErrAttr(err))
@@ -0,0 +1,450 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/google/uuid"
+)
+
+const (
+	blobDirectory = "blobs"
+	refDirectory  = "refs"
+)
+
+// ContentAddressedFileStorage stores the content of hosted files once per
+// sha256 in blobs/. A hosted file name is a reference in refs/ holding the
+// hash of its blob, every blob counts its references and is dropped with the
+// last one. Metadata sidecars and files in directories are stored as is, as
+// well as files written before the storage was enabled.
+//
+// Reference counts are updated under locks held in the process, the storage
+// must not be written by several replicas at once.
+type ContentAddressedFileStorage struct {
+	storage FileStorage
+	locksMu sync.Mutex
+	locks   map[string]*blobLock
+}
+
+// blobLock serializes reference counting of a blob, it is dropped when no
+// one holds or waits for it.
+type blobLock struct {
+	mu      sync.Mutex
+	holders int
+}
+
+func NewContentAddressedFileStorage(storage FileStorage) FileStorage {
+	return &ContentAddressedFileStorage{
+		storage: storage,
+		locks:   make(map[string]*blobLock),
+	}
+}
+
+var (
+	_ FileStorage          = (*ContentAddressedFileStorage)(nil)
+	_ PresignedFileStorage = (*ContentAddressedFileStorage)(nil)
+)
+
+func (s *ContentAddressedFileStorage) IsExist(ctx context.Context, file string) bool {
+	if isContentFile(file) && s.storage.IsExist(ctx, s.refFile(file)) {
+		return true
+	}
+	return s.storage.IsExist(ctx, file)
+}
+
+func (s *ContentAddressedFileStorage) Files(ctx context.Context) ([]string, error) {
+	files, err := s.storage.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.storage.FilesIn(ctx, refDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range refs {
+		if isInternalFile(ref) {
+			continue
+		}
+		files = append(files, ref)
+	}
+
+	return files, nil
+}
+
+func (s *ContentAddressedFileStorage) FilesIn(ctx context.Context, directory string) ([]string, error) {
+	files, err := s.storage.FilesIn(ctx, directory)
+	if err != nil {
+		return nil, err
+	}
+	// Previous versions and deleted files are references like current files
+	if !isContentFile(strings.TrimSuffix(directory, "/") + "/") {
+		return files, nil
+	}
+
+	refs, err := s.storage.FilesIn(ctx, s.refFile(directory))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, refs...), nil
+}
+
+func (s *ContentAddressedFileStorage) Read(ctx context.Context, file string) (io.ReadSeekCloser, error) {
+	hash, ok, err := s.readRef(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return s.storage.Read(ctx, file)
+	}
+
+	return s.storage.Read(ctx, s.blobFile(hash))
+}
+
+func (s *ContentAddressedFileStorage) ReadRange(ctx context.Context, file string, offset int64, length int64) (io.ReadCloser, error) {
+	hash, ok, err := s.readRef(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return s.storage.ReadRange(ctx, file, offset, length)
+	}
+
+	return s.storage.ReadRange(ctx, s.blobFile(hash), offset, length)
+}
+
+func (s *ContentAddressedFileStorage) Write(ctx context.Context, file string, reader io.Reader, size int64, contentType string) error {
+	if !isContentFile(file) {
+		return s.storage.Write(ctx, file, reader, size, contentType)
+	}
+
+	if s.IsExist(ctx, file) {
+		return apperr.ErrConflict.WithMessage(fmt.Sprintf("File %s already exist", file))
+	}
+
+	// The hash is known only after the whole content is read, so it is
+	// written to a temporary blob first
+	tmpFile := s.blobFile(strings.ReplaceAll(uuid.NewString(), "-", "") + ".tmp")
+	hash := sha256.New()
+	if err := s.storage.Write(ctx, tmpFile, io.TeeReader(reader, hash), size, contentType); err != nil {
+		return err
+	}
+
+	return s.addRef(ctx, file, tmpFile, fmt.Sprintf("%x", hash.Sum(nil)))
+}
+
+func (s *ContentAddressedFileStorage) Move(ctx context.Context, file string, newFile string) error {
+	hash, ok, err := s.readRef(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	// Replacing a reference must release its blob
+	if isContentFile(newFile) && s.storage.IsExist(ctx, s.refFile(newFile)) {
+		if err := s.Delete(ctx, newFile); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case ok && isContentFile(newFile):
+		return s.storage.Move(ctx, s.refFile(file), s.refFile(newFile))
+	case ok:
+		return s.moveOut(ctx, file, newFile, hash)
+	case isContentFile(newFile):
+		return s.moveIn(ctx, file, newFile)
+	default:
+		return s.storage.Move(ctx, file, newFile)
+	}
+}
+
+func (s *ContentAddressedFileStorage) Delete(ctx context.Context, file string) error {
+	hash, ok, err := s.readRef(ctx, file)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.storage.Delete(ctx, file)
+	}
+
+	unlock := s.lock(hash)
+	defer unlock()
+
+	if err := s.storage.Delete(ctx, s.refFile(file)); err != nil {
+		return err
+	}
+
+	return s.releaseBlob(ctx, hash)
+}
+
+func (s *ContentAddressedFileStorage) PresignRead(ctx context.Context, file string, expiration time.Duration, contentType string, contentDisposition string) (string, error) {
+	presignedStorage, ok := s.storage.(PresignedFileStorage)
+	if !ok {
+		return "", apperr.ErrNotImplemented.WithMessage("File storage does not support presigned urls")
+	}
+
+	hash, ok, err := s.readRef(ctx, file)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		file = s.blobFile(hash)
+	}
+
+	return presignedStorage.PresignRead(ctx, file, expiration, contentType, contentDisposition)
+}
+
+func (s *ContentAddressedFileStorage) PresignWrite(ctx context.Context, file string, expiration time.Duration) (string, error) {
+	presignedStorage, ok := s.storage.(PresignedFileStorage)
+	if !ok {
+		return "", apperr.ErrNotImplemented.WithMessage("File storage does not support presigned urls")
+	}
+	if isContentFile(file) {
+		// Content written bypassing the storage cannot be referenced
+		return "", apperr.ErrBadRequest.WithMessage(fmt.Sprintf("File %s cannot be uploaded directly", file))
+	}
+
+	return presignedStorage.PresignWrite(ctx, file, expiration)
+}
+
+// Size reads the size of a file written by a URL of PresignWrite, which is
+// stored as is.
+func (s *ContentAddressedFileStorage) Size(ctx context.Context, file string) (int64, error) {
+	presignedStorage, ok := s.storage.(PresignedFileStorage)
+	if !ok {
+		return 0, apperr.ErrNotImplemented.WithMessage("File storage does not support presigned urls")
+	}
+
+	return presignedStorage.Size(ctx, file)
+}
+
+// addRef makes file a reference to the blob with the hash, the content of
+// tmpFile becomes the blob unless it is already stored.
+func (s *ContentAddressedFileStorage) addRef(ctx context.Context, file string, tmpFile string, hash string) error {
+	unlock := s.lock(hash)
+	defer unlock()
+
+	count, err := s.readRefCount(ctx, hash)
+	if err != nil {
+		s.storage.Delete(ctx, tmpFile)
+		return err
+	}
+
+	if count > 0 && s.storage.IsExist(ctx, s.blobFile(hash)) {
+		if err := s.storage.Delete(ctx, tmpFile); err != nil {
+			logging.L(ctx).Warn("Fail delete temporary blob", logging.StringAttr("file", tmpFile), logging.ErrAttr(err))
+		}
+	} else {
+		count = 0
+		if s.storage.IsExist(ctx, s.blobFile(hash)) {
+			// Left by an interrupted release, the content is the same
+			s.storage.Delete(ctx, s.blobFile(hash))
+		}
+		if err := s.storage.Move(ctx, tmpFile, s.blobFile(hash)); err != nil {
+			s.storage.Delete(ctx, tmpFile)
+			return err
+		}
+	}
+
+	if err := s.writeRefCount(ctx, hash, count+1); err != nil {
+		if count == 0 {
+			s.storage.Delete(ctx, s.blobFile(hash))
+		}
+		return err
+	}
+
+	data := []byte(hash)
+	if err := s.storage.Write(ctx, s.refFile(file), bytes.NewReader(data), int64(len(data)), "text/plain"); err != nil {
+		s.releaseBlob(ctx, hash)
+		return err
+	}
+
+	return nil
+}
+
+// releaseBlob drops a reference to the blob with the hash and deletes the
+// blob with its last reference. The caller must hold the lock of the hash.
+func (s *ContentAddressedFileStorage) releaseBlob(ctx context.Context, hash string) error {
+	count, err := s.readRefCount(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if count > 1 {
+		return s.writeRefCount(ctx, hash, count-1)
+	}
+
+	if s.storage.IsExist(ctx, s.blobFile(hash)) {
+		if err := s.storage.Delete(ctx, s.blobFile(hash)); err != nil {
+			return err
+		}
+	}
+	if s.storage.IsExist(ctx, s.refCountFile(hash)) {
+		return s.storage.Delete(ctx, s.refCountFile(hash))
+	}
+
+	return nil
+}
+
+// moveIn turns the plain file into a reference named newFile.
+func (s *ContentAddressedFileStorage) moveIn(ctx context.Context, file string, newFile string) error {
+	if !s.storage.IsExist(ctx, file) {
+		return apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
+	}
+
+	content, err := s.storage.Read(ctx, file)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(hash, content)
+	content.Close()
+	if err != nil {
+		logging.L(ctx).Error(fmt.Sprintf("Fail read file %s", file), logging.ErrAttr(err))
+		return apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read file %s", file))
+	}
+
+	return s.addRef(ctx, newFile, file, fmt.Sprintf("%x", hash.Sum(nil)))
+}
+
+// moveOut turns the reference into the plain file newFile.
+func (s *ContentAddressedFileStorage) moveOut(ctx context.Context, file string, newFile string, hash string) error {
+	unlock := s.lock(hash)
+	defer unlock()
+
+	content, err := s.storage.Read(ctx, s.blobFile(hash))
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if err := s.storage.Write(ctx, newFile, content, -1, "application/octet-stream"); err != nil {
+		return err
+	}
+
+	if err := s.storage.Delete(ctx, s.refFile(file)); err != nil {
+		return err
+	}
+
+	return s.releaseBlob(ctx, hash)
+}
+
+// readRef returns the blob hash of file, ok is false when file is not a
+// reference.
+func (s *ContentAddressedFileStorage) readRef(ctx context.Context, file string) (string, bool, error) {
+	if !isContentFile(file) || !s.storage.IsExist(ctx, s.refFile(file)) {
+		return "", false, nil
+	}
+
+	data, err := s.readAll(ctx, s.refFile(file))
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+func (s *ContentAddressedFileStorage) readRefCount(ctx context.Context, hash string) (int, error) {
+	if !s.storage.IsExist(ctx, s.refCountFile(hash)) {
+		return 0, nil
+	}
+
+	data, err := s.readAll(ctx, s.refCountFile(hash))
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read references of blob %s", hash))
+	}
+
+	return count, nil
+}
+
+func (s *ContentAddressedFileStorage) writeRefCount(ctx context.Context, hash string, count int) error {
+	if s.storage.IsExist(ctx, s.refCountFile(hash)) {
+		if err := s.storage.Delete(ctx, s.refCountFile(hash)); err != nil {
+			return err
+		}
+	}
+
+	data := []byte(strconv.Itoa(count))
+	return s.storage.Write(ctx, s.refCountFile(hash), bytes.NewReader(data), int64(len(data)), "text/plain")
+}
+
+func (s *ContentAddressedFileStorage) readAll(ctx context.Context, file string) ([]byte, error) {
+	reader, err := s.storage.Read(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		logging.L(ctx).Error(fmt.Sprintf("Fail read file %s", file), logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read file %s", file))
+	}
+
+	return data, nil
+}
+
+// lock serializes reference counting of the same blob within the process.
+func (s *ContentAddressedFileStorage) lock(hash string) func() {
+	s.locksMu.Lock()
+	l, ok := s.locks[hash]
+	if !ok {
+		l = &blobLock{}
+		s.locks[hash] = l
+	}
+	l.holders++
+	s.locksMu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		s.locksMu.Lock()
+		l.holders--
+		if l.holders == 0 {
+			delete(s.locks, hash)
+		}
+		s.locksMu.Unlock()
+	}
+}
+
+func (s *ContentAddressedFileStorage) refFile(file string) string {
+	return fmt.Sprintf("%s/%s", refDirectory, file)
+}
+
+func (s *ContentAddressedFileStorage) blobFile(hash string) string {
+	return fmt.Sprintf("%s/%s", blobDirectory, hash)
+}
+
+func (s *ContentAddressedFileStorage) refCountFile(hash string) string {
+	return fmt.Sprintf("%s/%s.refs", blobDirectory, hash)
+}
+
+// isContentFile reports whether file holds the content of a hosted file,
+// its current or previous version, backup, upload in progress or deleted
+// file in the trash.
+func isContentFile(file string) bool {
+	if strings.HasSuffix(file, ".metadata") {
+		return false
+	}
+	return !strings.Contains(file, "/") ||
+		strings.HasPrefix(file, VersionDirectory+"/") ||
+		strings.HasPrefix(file, TrashDirectory+"/")
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"cmp"
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/goccy/go-json"
+)
+
+// fileCursor is the position after the last file of a page: its sort key and
+// id, which breaks ties between files with the same key.
+type fileCursor struct {
+	Sort       domain.FileSort `json:"s"`
+	Descending bool            `json:"d,omitempty"`
+	Name       string          `json:"n,omitempty"`
+	Number     int64           `json:"v,omitempty"`
+	Id         string          `json:"i"`
+}
+
+func newFileCursor(query *domain.FileQuery, metadata *domain.FileMetadata) fileCursor {
+	cursor := fileCursor{Sort: query.Sort, Descending: query.Descending, Id: metadata.Id}
+	switch query.Sort {
+	case domain.FileSortName:
+		cursor.Name = metadata.Name
+	case domain.FileSortSize:
+		cursor.Number = metadata.Size
+	default:
+		cursor.Number = metadata.CreatedAt.UnixNano()
+	}
+	return cursor
+}
+
+func (c fileCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeFileCursor returns nil for an empty cursor and apperr.ErrBadRequest
+// for a malformed one or one of a page with another order.
+func decodeFileCursor(query *domain.FileQuery) (*fileCursor, error) {
+	if len(query.Cursor) == 0 {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return nil, apperr.ErrBadRequest.WithMessage("Invalid cursor")
+	}
+	var cursor fileCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, apperr.ErrBadRequest.WithMessage("Invalid cursor")
+	}
+	if cursor.Sort != query.Sort || cursor.Descending != query.Descending {
+		return nil, apperr.ErrBadRequest.WithMessage("Cursor does not match the sort order")
+	}
+
+	return &cursor, nil
+}
+
+// compareFiles orders a before b by the sort key of query and then by id.
+func compareFiles(query *domain.FileQuery, a fileCursor, b fileCursor) int {
+	var result int
+	if query.Sort == domain.FileSortName {
+		result = cmp.Compare(a.Name, b.Name)
+	} else {
+		result = cmp.Compare(a.Number, b.Number)
+	}
+	if result == 0 {
+		result = cmp.Compare(a.Id, b.Id)
+	}
+	if query.Descending {
+		result = -result
+	}
+	return result
+}
+
+func matchFile(query *domain.FileQuery, metadata *domain.FileMetadata) bool {
+	if !strings.HasPrefix(metadata.Name, query.NamePrefix) {
+		return false
+	}
+	if mimeType, ok := strings.CutSuffix(query.MimeType, "*"); ok {
+		if !strings.HasPrefix(metadata.MimeType, mimeType) {
+			return false
+		}
+	} else if len(query.MimeType) > 0 && metadata.MimeType != query.MimeType {
+		return false
+	}
+	if !query.CreatedAfter.IsZero() && !metadata.CreatedAt.After(query.CreatedAfter) {
+		return false
+	}
+	if !query.CreatedBefore.IsZero() && !metadata.CreatedAt.Before(query.CreatedBefore) {
+		return false
+	}
+	if !query.ExpiredAfter.IsZero() && (metadata.IsPermanent() || !metadata.ExpiredAt.After(query.ExpiredAfter)) {
+		return false
+	}
+	if !query.ExpiredBefore.IsZero() && (metadata.IsPermanent() || !metadata.ExpiredAt.Before(query.ExpiredBefore)) {
+		return false
+	}
+	if query.HasBackup != nil && *query.HasBackup != (len(metadata.BackupName) > 0) {
+		return false
+	}
+	if len(query.Owner) > 0 && metadata.Owner != query.Owner {
+		return false
+	}
+	for key, value := range query.Meta {
+		if !slices.Contains(metadata.Meta[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// queryFiles selects the page of query from all files in memory.
+func queryFiles(query *domain.FileQuery, files []*domain.FileMetadata) (*domain.FilePage, error) {
+	after, err := decodeFileCursor(query)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []*domain.FileMetadata{}
+	for _, file := range files {
+		if matchFile(query, file) {
+			matched = append(matched, file)
+		}
+	}
+	slices.SortFunc(matched, func(a, b *domain.FileMetadata) int {
+		return compareFiles(query, newFileCursor(query, a), newFileCursor(query, b))
+	})
+
+	page := &domain.FilePage{Total: int64(len(matched))}
+	start := 0
+	if after != nil {
+		start, _ = slices.BinarySearchFunc(matched, *after, func(file *domain.FileMetadata, cursor fileCursor) int {
+			if compareFiles(query, newFileCursor(query, file), cursor) <= 0 {
+				return -1
+			}
+			return 1
+		})
+	}
+	end := min(start+query.Limit, len(matched))
+	page.Files = matched[start:end]
+	if end < len(matched) {
+		page.NextCursor = newFileCursor(query, matched[end-1]).encode()
+	}
+
+	return page, nil
+}
@@ -1,12 +1,58 @@
 package storage
 
-import "context"
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
 
 type FileStorage interface {
 	IsExist(ctx context.Context, file string) bool
 	Files(ctx context.Context) ([]string, error)
-	Read(ctx context.Context, file string) ([]byte, error)
-	Write(ctx context.Context, file string, data []byte, contentType string) error
+	// FilesIn lists service files stored in directory, e.g. "tus/", or in
+	// the root directory for "". Names are returned relative to the directory.
+	FilesIn(ctx context.Context, directory string) ([]string, error)
+	Read(ctx context.Context, file string) (io.ReadSeekCloser, error)
+	// ReadRange reads length bytes of file starting at offset.
+	ReadRange(ctx context.Context, file string, offset int64, length int64) (io.ReadCloser, error)
+	Write(ctx context.Context, file string, reader io.Reader, size int64, contentType string) error
 	Move(ctx context.Context, file string, newFile string) error
 	Delete(ctx context.Context, file string) error
 }
+
+// PresignedFileStorage is implemented by storages which can give clients
+// direct access to files by short-lived URLs, so the content does not flow
+// through the service.
+type PresignedFileStorage interface {
+	// PresignRead returns a URL to download file. The response is served
+	// with the given content type and disposition.
+	PresignRead(ctx context.Context, file string, expiration time.Duration, contentType string, contentDisposition string) (string, error)
+	// PresignWrite returns a URL to upload file by a single PUT request.
+	PresignWrite(ctx context.Context, file string, expiration time.Duration) (string, error)
+	// Size returns the size of file uploaded by a URL of PresignWrite, the
+	// URL does not limit it.
+	Size(ctx context.Context, file string) (int64, error)
+}
+
+const (
+	// VersionDirectory keeps the previous versions of hosted files as
+	// versions/<name>/<version>.
+	VersionDirectory = "versions"
+	// TrashDirectory keeps deleted files until they are purged.
+	TrashDirectory = "trash"
+	// TombstoneDirectory keeps the metadata of tombstones of gone files, it
+	// has no content.
+	TombstoneDirectory = "tombstones"
+)
+
+// isInternalFile reports whether file is a service file (metadata sidecar,
+// in-progress upload) that must not be listed as a hosted file.
+func isInternalFile(file string) bool {
+	return strings.HasSuffix(file, ".metadata") || strings.HasSuffix(file, ".upload")
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/bruhabruh/file-hosting/internal/domain"
+)
+
+// MetadataStore keeps the metadata of hosted files by file id.
+type MetadataStore interface {
+	// Get returns apperr.ErrNotFound when file has no metadata.
+	Get(ctx context.Context, file string) (*domain.FileMetadata, error)
+	// List returns the metadata of hosted files, without files in directories.
+	List(ctx context.Context) ([]*domain.FileMetadata, error)
+	// Ids returns the ids of hosted files having metadata, also of files
+	// whose content is missing.
+	Ids(ctx context.Context) ([]string, error)
+	// ListIn returns the metadata of files placed directly in directory,
+	// e.g. versions of a file.
+	ListIn(ctx context.Context, directory string) ([]*domain.FileMetadata, error)
+	// Query returns a page of files matching query, query.Limit must be positive.
+	Query(ctx context.Context, query *domain.FileQuery) (*domain.FilePage, error)
+	// Put creates or replaces the metadata of metadata.Id.
+	Put(ctx context.Context, metadata *domain.FileMetadata) error
+	Delete(ctx context.Context, file string) error
+	Close() error
+}
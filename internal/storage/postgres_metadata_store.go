@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const postgresMetadataSchema = `
+CREATE TABLE IF NOT EXISTS file_metadata (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	mime_type TEXT NOT NULL,
+	sha1 TEXT NOT NULL,
+	size BIGINT NOT NULL,
+	meta JSONB NOT NULL,
+	created_at BIGINT NOT NULL,
+	expired_at BIGINT NOT NULL,
+	backup_name TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS file_metadata_created_at_idx ON file_metadata (created_at);
+CREATE INDEX IF NOT EXISTS file_metadata_expired_at_idx ON file_metadata (expired_at);
+CREATE INDEX IF NOT EXISTS file_metadata_name_idx ON file_metadata (name, id);
+CREATE INDEX IF NOT EXISTS file_metadata_size_idx ON file_metadata (size, id);
+CREATE INDEX IF NOT EXISTS file_metadata_meta_idx ON file_metadata USING GIN (meta jsonb_path_ops);
+`
+
+const postgresMetaMatch = "meta @> jsonb_build_object(%s::text, jsonb_build_array(%s::text))"
+
+// NewPostgresMetadataStore connects to PostgreSQL by url, creating the
+// schema when needed.
+func NewPostgresMetadataStore(ctx context.Context, url string) (MetadataStore, error) {
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, postgresMetadataSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+	if err := migrateColumns(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate schema: %w", err)
+	}
+
+	return &sqlMetadataStore{db: db, metaMatch: postgresMetaMatch}, nil
+}
@@ -1,11 +1,12 @@
 package storage
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/bruhabruh/file-hosting/internal/app/apperr"
 	"github.com/bruhabruh/file-hosting/pkg/logging"
@@ -20,7 +21,10 @@ func NewS3FileStorage(s3 *s3.S3) FileStorage {
 	return &S3FileStorage{s3: s3}
 }
 
-var _ FileStorage = (*S3FileStorage)(nil)
+var (
+	_ FileStorage          = (*S3FileStorage)(nil)
+	_ PresignedFileStorage = (*S3FileStorage)(nil)
+)
 
 func (s *S3FileStorage) IsExist(ctx context.Context, file string) bool {
 	return s.s3.Exists(ctx, file)
@@ -35,7 +39,7 @@ func (s *S3FileStorage) Files(ctx context.Context) ([]string, error) {
 	files := []string{}
 
 	for _, entry := range objects {
-		if strings.HasSuffix(entry, ".metadata") {
+		if isInternalFile(entry) || strings.Contains(entry, "/") {
 			continue
 		}
 		files = append(files, entry)
@@ -44,7 +48,16 @@ func (s *S3FileStorage) Files(ctx context.Context) ([]string, error) {
 	return files, nil
 }
 
-func (s *S3FileStorage) Read(ctx context.Context, file string) ([]byte, error) {
+func (s *S3FileStorage) FilesIn(ctx context.Context, directory string) ([]string, error) {
+	objects, err := s.s3.ObjectsIn(ctx, directory)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail get objects of %s in s3", directory))
+	}
+
+	return append([]string{}, objects...), nil
+}
+
+func (s *S3FileStorage) Read(ctx context.Context, file string) (io.ReadSeekCloser, error) {
 	if !s.IsExist(ctx, file) {
 		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
 	}
@@ -54,25 +67,30 @@ func (s *S3FileStorage) Read(ctx context.Context, file string) ([]byte, error) {
 		logging.L(ctx).Error(fmt.Sprintf("Fail download file %s", file), logging.ErrAttr(err))
 		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail download file %s", file))
 	}
-	defer object.Close()
 
-	data, err := io.ReadAll(object)
+	return object, nil
+}
+
+func (s *S3FileStorage) ReadRange(ctx context.Context, file string, offset int64, length int64) (io.ReadCloser, error) {
+	if !s.IsExist(ctx, file) {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s not found", file))
+	}
+
+	object, err := s.s3.DownloadRange(ctx, file, offset, length)
 	if err != nil {
-		logging.L(ctx).Error(fmt.Sprintf("Fail read file %s", file), logging.ErrAttr(err))
-		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read file %s", file))
+		logging.L(ctx).Error(fmt.Sprintf("Fail download file %s", file), logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail download file %s", file))
 	}
 
-	return data, nil
+	return object, nil
 }
 
-func (s *S3FileStorage) Write(ctx context.Context, file string, data []byte, contentType string) error {
+func (s *S3FileStorage) Write(ctx context.Context, file string, reader io.Reader, size int64, contentType string) error {
 	if s.IsExist(ctx, file) {
 		return apperr.ErrNotFound.WithMessage(fmt.Sprintf("File %s already exist", file))
 	}
 
-	reader := bytes.NewReader(data)
-
-	err := s.s3.Upload(ctx, file, reader, reader.Size(), contentType)
+	err := s.s3.Upload(ctx, file, reader, size, contentType)
 	if err != nil {
 		logging.L(ctx).Error(fmt.Sprintf("Fail upload file %s", file), logging.ErrAttr(err))
 		return apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail upload to file %s", file))
@@ -101,3 +119,41 @@ func (s *S3FileStorage) Delete(ctx context.Context, file string) error {
 	}
 	return s.s3.Delete(ctx, file)
 }
+
+func (s *S3FileStorage) PresignRead(ctx context.Context, file string, expiration time.Duration, contentType string, contentDisposition string) (string, error) {
+	reqParams := make(url.Values)
+	if contentType != "" {
+		reqParams.Set("response-content-type", contentType)
+	}
+	if contentDisposition != "" {
+		reqParams.Set("response-content-disposition", contentDisposition)
+	}
+
+	presignedURL, err := s.s3.PresignedGet(ctx, file, expiration, reqParams)
+	if err != nil {
+		logging.L(ctx).Error(fmt.Sprintf("Fail presign download of file %s", file), logging.ErrAttr(err))
+		return "", apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail presign download of file %s", file))
+	}
+
+	return presignedURL.String(), nil
+}
+
+func (s *S3FileStorage) Size(ctx context.Context, file string) (int64, error) {
+	size, err := s.s3.Size(ctx, file)
+	if err != nil {
+		logging.L(ctx).Error(fmt.Sprintf("Fail read size of file %s", file), logging.ErrAttr(err))
+		return 0, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read size of file %s", file))
+	}
+
+	return size, nil
+}
+
+func (s *S3FileStorage) PresignWrite(ctx context.Context, file string, expiration time.Duration) (string, error) {
+	presignedURL, err := s.s3.PresignedPut(ctx, file, expiration)
+	if err != nil {
+		logging.L(ctx).Error(fmt.Sprintf("Fail presign upload of file %s", file), logging.ErrAttr(err))
+		return "", apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail presign upload of file %s", file))
+	}
+
+	return presignedURL.String(), nil
+}
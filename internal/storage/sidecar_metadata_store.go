@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/goccy/go-json"
+)
+
+// SidecarMetadataStore keeps metadata in <file>.metadata JSON files next to
+// the files in the file storage.
+type SidecarMetadataStore struct {
+	fileStorage FileStorage
+}
+
+func NewSidecarMetadataStore(fileStorage FileStorage) MetadataStore {
+	return &SidecarMetadataStore{fileStorage: fileStorage}
+}
+
+var _ MetadataStore = (*SidecarMetadataStore)(nil)
+
+func (s *SidecarMetadataStore) Get(ctx context.Context, file string) (*domain.FileMetadata, error) {
+	reader, err := s.fileStorage.Read(ctx, s.metadataFile(file))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read metadata of file %s", file))
+	}
+	metadata, err := domain.NewFileMetadataFromBytes(data)
+	if err != nil {
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read metadata of file %s", file))
+	}
+	return metadata, nil
+}
+
+// List reads the sidecar of every file, so it costs a storage request per file.
+func (s *SidecarMetadataStore) List(ctx context.Context) ([]*domain.FileMetadata, error) {
+	fileNames, err := s.fileStorage.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*domain.FileMetadata, len(fileNames))
+	for i, fileName := range fileNames {
+		file, err := s.Get(ctx, fileName)
+		if err != nil {
+			return nil, err
+		}
+		files[i] = file
+	}
+
+	return files, nil
+}
+
+// Ids lists the sidecars in the root directory of the file storage.
+func (s *SidecarMetadataStore) Ids(ctx context.Context) ([]string, error) {
+	fileNames, err := s.fileStorage.FilesIn(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, fileName := range fileNames {
+		if id, ok := strings.CutSuffix(fileName, ".metadata"); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func (s *SidecarMetadataStore) ListIn(ctx context.Context, directory string) ([]*domain.FileMetadata, error) {
+	fileNames, err := s.fileStorage.FilesIn(ctx, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*domain.FileMetadata{}
+	for _, fileName := range fileNames {
+		fileName, ok := strings.CutSuffix(fileName, ".metadata")
+		if !ok {
+			continue
+		}
+		file, err := s.Get(ctx, fmt.Sprintf("%s/%s", strings.TrimSuffix(directory, "/"), fileName))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// Query filters and sorts the result of List in memory.
+func (s *SidecarMetadataStore) Query(ctx context.Context, query *domain.FileQuery) (*domain.FilePage, error) {
+	files, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return queryFiles(query, files)
+}
+
+func (s *SidecarMetadataStore) Put(ctx context.Context, metadata *domain.FileMetadata) error {
+	metadataInBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return apperr.ErrInternalServerError.WithMessage("Fail serialize metadata")
+	}
+
+	file := s.metadataFile(metadata.Id)
+	if s.fileStorage.IsExist(ctx, file) {
+		if err := s.fileStorage.Delete(ctx, file); err != nil {
+			return err
+		}
+	}
+
+	return s.fileStorage.Write(ctx, file, bytes.NewReader(metadataInBytes), int64(len(metadataInBytes)), "application/json")
+}
+
+func (s *SidecarMetadataStore) Delete(ctx context.Context, file string) error {
+	return s.fileStorage.Delete(ctx, s.metadataFile(file))
+}
+
+func (s *SidecarMetadataStore) Close() error {
+	return nil
+}
+
+func (s *SidecarMetadataStore) metadataFile(file string) string {
+	return fmt.Sprintf("%s.metadata", file)
+}
@@ -0,0 +1,396 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bruhabruh/file-hosting/internal/app/apperr"
+	"github.com/bruhabruh/file-hosting/internal/domain"
+	"github.com/bruhabruh/file-hosting/pkg/logging"
+	"github.com/goccy/go-json"
+)
+
+const metadataColumns = "id, name, mime_type, sha1, size, meta, created_at, expired_at, backup_name, max_downloads, owner, management_token_hash, private, password_hash"
+
+// addedColumns are added by migrateColumns to tables created before the
+// columns existed.
+var addedColumns = []struct {
+	name       string
+	definition string
+}{
+	{name: "max_downloads", definition: "BIGINT NOT NULL DEFAULT 0"},
+	{name: "owner", definition: "TEXT NOT NULL DEFAULT ''"},
+	{name: "management_token_hash", definition: "TEXT NOT NULL DEFAULT ''"},
+	{name: "private", definition: "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{name: "password_hash", definition: "TEXT NOT NULL DEFAULT ''"},
+}
+
+// hostedFileCondition skips files in directories, e.g. previous versions.
+const hostedFileCondition = "id NOT LIKE '%/%'"
+
+// sqlMetadataStore keeps metadata in the file_metadata table. Queries are
+// shared by SQLite and PostgreSQL, times are stored as unix nanoseconds.
+type sqlMetadataStore struct {
+	db *sql.DB
+	// metaMatch is the condition of a meta key and value, formatted with
+	// their placeholders.
+	metaMatch string
+}
+
+func (s *sqlMetadataStore) Get(ctx context.Context, file string) (*domain.FileMetadata, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+metadataColumns+" FROM file_metadata WHERE id = $1", file)
+
+	metadata, err := scanMetadata(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperr.ErrNotFound.WithMessage(fmt.Sprintf("Metadata of file %s not found", file))
+	}
+	if err != nil {
+		logging.L(ctx).Error("Fail select metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail read metadata of file %s", file))
+	}
+
+	return metadata, nil
+}
+
+func (s *sqlMetadataStore) List(ctx context.Context) ([]*domain.FileMetadata, error) {
+	return s.selectMetadata(ctx, "SELECT "+metadataColumns+" FROM file_metadata WHERE "+hostedFileCondition+" ORDER BY id")
+}
+
+func (s *sqlMetadataStore) Ids(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id FROM file_metadata WHERE "+hostedFileCondition+" ORDER BY id")
+	if err != nil {
+		logging.L(ctx).Error("Fail select metadata ids", logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			logging.L(ctx).Error("Fail scan metadata id", logging.ErrAttr(err))
+			return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		logging.L(ctx).Error("Fail select metadata ids", logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+	}
+
+	return ids, nil
+}
+
+func (s *sqlMetadataStore) ListIn(ctx context.Context, directory string) ([]*domain.FileMetadata, error) {
+	prefix := escapeLike(strings.TrimSuffix(directory, "/") + "/")
+	return s.selectMetadata(
+		ctx,
+		`SELECT `+metadataColumns+` FROM file_metadata WHERE id LIKE $1 ESCAPE '\' AND id NOT LIKE $2 ESCAPE '\' ORDER BY id`,
+		prefix+"%",
+		prefix+"%/%",
+	)
+}
+
+func (s *sqlMetadataStore) selectMetadata(ctx context.Context, query string, args ...any) ([]*domain.FileMetadata, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.L(ctx).Error("Fail select metadata", logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+	}
+	defer rows.Close()
+
+	files := []*domain.FileMetadata{}
+	for rows.Next() {
+		metadata, err := scanMetadata(rows)
+		if err != nil {
+			logging.L(ctx).Error("Fail scan metadata", logging.ErrAttr(err))
+			return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+		}
+		files = append(files, metadata)
+	}
+	if err := rows.Err(); err != nil {
+		logging.L(ctx).Error("Fail select metadata", logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+	}
+
+	return files, nil
+}
+
+func (s *sqlMetadataStore) Query(ctx context.Context, query *domain.FileQuery) (*domain.FilePage, error) {
+	after, err := decodeFileCursor(query)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := s.queryConditions(query)
+
+	page := &domain.FilePage{}
+	row := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM file_metadata"+sqlWhere(where), args...)
+	if err := row.Scan(&page.Total); err != nil {
+		logging.L(ctx).Error("Fail count metadata", logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+	}
+
+	column := sortColumn(query.Sort)
+	order, compare := "ASC", ">"
+	if query.Descending {
+		order, compare = "DESC", "<"
+	}
+	if after != nil {
+		var value any = after.Number
+		if query.Sort == domain.FileSortName {
+			value = after.Name
+		}
+		args = append(args, value, after.Id)
+		where = append(where, fmt.Sprintf(
+			"(%[1]s %[2]s $%[3]d OR (%[1]s = $%[3]d AND id %[2]s $%[4]d))",
+			column, compare, len(args)-1, len(args),
+		))
+	}
+	// One more row tells whether there is a next page
+	args = append(args, query.Limit+1)
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			"SELECT "+metadataColumns+" FROM file_metadata%s ORDER BY %s %s, id %s LIMIT $%d",
+			sqlWhere(where), column, order, order, len(args),
+		),
+		args...,
+	)
+	if err != nil {
+		logging.L(ctx).Error("Fail select metadata", logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+	}
+	defer rows.Close()
+
+	page.Files = []*domain.FileMetadata{}
+	for rows.Next() {
+		metadata, err := scanMetadata(rows)
+		if err != nil {
+			logging.L(ctx).Error("Fail scan metadata", logging.ErrAttr(err))
+			return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+		}
+		page.Files = append(page.Files, metadata)
+	}
+	if err := rows.Err(); err != nil {
+		logging.L(ctx).Error("Fail select metadata", logging.ErrAttr(err))
+		return nil, apperr.ErrInternalServerError.WithMessage("Fail read metadata")
+	}
+
+	if len(page.Files) > query.Limit {
+		page.Files = page.Files[:query.Limit]
+		page.NextCursor = newFileCursor(query, page.Files[query.Limit-1]).encode()
+	}
+
+	return page, nil
+}
+
+// queryConditions returns the filters of query with their arguments,
+// numbered from $1.
+func (s *sqlMetadataStore) queryConditions(query *domain.FileQuery) ([]string, []any) {
+	var (
+		where = []string{hostedFileCondition}
+		args  []any
+	)
+	add := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		where = append(where, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if len(query.NamePrefix) > 0 {
+		add(`name LIKE %s ESCAPE '\'`, escapeLike(query.NamePrefix)+"%")
+	}
+	if mimeType, ok := strings.CutSuffix(query.MimeType, "*"); ok {
+		add(`mime_type LIKE %s ESCAPE '\'`, escapeLike(mimeType)+"%")
+	} else if len(query.MimeType) > 0 {
+		add("mime_type = %s", query.MimeType)
+	}
+	if !query.CreatedAfter.IsZero() {
+		add("created_at > %s", query.CreatedAfter.UnixNano())
+	}
+	if !query.CreatedBefore.IsZero() {
+		add("created_at < %s", query.CreatedBefore.UnixNano())
+	}
+	if !query.ExpiredAfter.IsZero() || !query.ExpiredBefore.IsZero() {
+		add("expired_at <> %s", domain.PermanentExpiredAt.UnixNano())
+	}
+	if !query.ExpiredAfter.IsZero() {
+		add("expired_at > %s", query.ExpiredAfter.UnixNano())
+	}
+	if !query.ExpiredBefore.IsZero() {
+		add("expired_at < %s", query.ExpiredBefore.UnixNano())
+	}
+	if query.HasBackup != nil {
+		if *query.HasBackup {
+			where = append(where, "backup_name <> ''")
+		} else {
+			where = append(where, "backup_name = ''")
+		}
+	}
+	if len(query.Owner) > 0 {
+		add("owner = %s", query.Owner)
+	}
+	for key, value := range query.Meta {
+		add(s.metaMatch, key, value)
+	}
+
+	return where, args
+}
+
+func sqlWhere(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func sortColumn(sort domain.FileSort) string {
+	switch sort {
+	case domain.FileSortName:
+		return "name"
+	case domain.FileSortSize:
+		return "size"
+	default:
+		return "created_at"
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (s *sqlMetadataStore) Put(ctx context.Context, metadata *domain.FileMetadata) error {
+	meta, err := json.Marshal(metadata.Meta)
+	if err != nil {
+		return apperr.ErrInternalServerError.WithMessage("Fail serialize metadata")
+	}
+
+	_, err = s.db.ExecContext(
+		ctx,
+		`INSERT INTO file_metadata (`+metadataColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			mime_type = excluded.mime_type,
+			sha1 = excluded.sha1,
+			size = excluded.size,
+			meta = excluded.meta,
+			created_at = excluded.created_at,
+			expired_at = excluded.expired_at,
+			backup_name = excluded.backup_name,
+			max_downloads = excluded.max_downloads,
+			owner = excluded.owner,
+			management_token_hash = excluded.management_token_hash,
+			private = excluded.private,
+			password_hash = excluded.password_hash`,
+		metadata.Id,
+		metadata.Name,
+		metadata.MimeType,
+		metadata.Sha1,
+		metadata.Size,
+		string(meta),
+		metadata.CreatedAt.UnixNano(),
+		metadata.ExpiredAt.UnixNano(),
+		metadata.BackupName,
+		metadata.MaxDownloads,
+		metadata.Owner,
+		metadata.ManagementTokenHash,
+		metadata.Private,
+		metadata.PasswordHash,
+	)
+	if err != nil {
+		logging.L(ctx).Error("Fail upsert metadata", logging.StringAttr("file", metadata.Id), logging.ErrAttr(err))
+		return apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail write metadata of file %s", metadata.Id))
+	}
+
+	return nil
+}
+
+func (s *sqlMetadataStore) Delete(ctx context.Context, file string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM file_metadata WHERE id = $1", file)
+	if err != nil {
+		logging.L(ctx).Error("Fail delete metadata", logging.StringAttr("file", file), logging.ErrAttr(err))
+		return apperr.ErrInternalServerError.WithMessage(fmt.Sprintf("Fail delete metadata of file %s", file))
+	}
+
+	return nil
+}
+
+func (s *sqlMetadataStore) Close() error {
+	return s.db.Close()
+}
+
+// migrateColumns adds the addedColumns missing in the file_metadata table.
+func migrateColumns(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT * FROM file_metadata LIMIT 0")
+	if err != nil {
+		return err
+	}
+	columns, err := rows.Columns()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, column := range addedColumns {
+		if slices.Contains(columns, column.name) {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE file_metadata ADD COLUMN %s %s", column.name, column.definition)); err != nil {
+			return fmt.Errorf("add column %s: %w", column.name, err)
+		}
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMetadata(row rowScanner) (*domain.FileMetadata, error) {
+	var (
+		metadata  domain.FileMetadata
+		meta      string
+		createdAt int64
+		expiredAt int64
+	)
+
+	err := row.Scan(
+		&metadata.Id,
+		&metadata.Name,
+		&metadata.MimeType,
+		&metadata.Sha1,
+		&metadata.Size,
+		&meta,
+		&createdAt,
+		&expiredAt,
+		&metadata.BackupName,
+		&metadata.MaxDownloads,
+		&metadata.Owner,
+		&metadata.ManagementTokenHash,
+		&metadata.Private,
+		&metadata.PasswordHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(meta), &metadata.Meta); err != nil {
+		return nil, err
+	}
+	metadata.CreatedAt = time.Unix(0, createdAt)
+	metadata.ExpiredAt = time.Unix(0, expiredAt)
+
+	return &metadata, nil
+}
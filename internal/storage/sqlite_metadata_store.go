@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteMetadataSchema = `
+CREATE TABLE IF NOT EXISTS file_metadata (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	mime_type TEXT NOT NULL,
+	sha1 TEXT NOT NULL,
+	size INTEGER NOT NULL,
+	meta TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	expired_at INTEGER NOT NULL,
+	backup_name TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS file_metadata_created_at_idx ON file_metadata (created_at);
+CREATE INDEX IF NOT EXISTS file_metadata_expired_at_idx ON file_metadata (expired_at);
+CREATE INDEX IF NOT EXISTS file_metadata_name_idx ON file_metadata (name, id);
+CREATE INDEX IF NOT EXISTS file_metadata_size_idx ON file_metadata (size, id);
+`
+
+const sqliteMetaMatch = "EXISTS (SELECT 1 FROM json_each(meta) AS m, json_each(m.value) AS v WHERE m.key = %s AND v.value = %s)"
+
+// NewSQLiteMetadataStore opens the embedded SQLite database at path,
+// creating it when needed.
+func NewSQLiteMetadataStore(path string) (MetadataStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=case_sensitive_like(1)", path))
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serializes writes, a single connection avoids busy errors
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteMetadataSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+	if err := migrateColumns(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate schema: %w", err)
+	}
+
+	return &sqlMetadataStore{db: db, metaMatch: sqliteMetaMatch}, nil
+}
@@ -22,13 +22,75 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type FileSort int32
+
+const (
+	FileSort_FILE_SORT_CREATED_AT FileSort = 0
+	FileSort_FILE_SORT_NAME       FileSort = 1
+	FileSort_FILE_SORT_SIZE       FileSort = 2
+)
+
+// Enum value maps for FileSort.
+var (
+	FileSort_name = map[int32]string{
+		0: "FILE_SORT_CREATED_AT",
+		1: "FILE_SORT_NAME",
+		2: "FILE_SORT_SIZE",
+	}
+	FileSort_value = map[string]int32{
+		"FILE_SORT_CREATED_AT": 0,
+		"FILE_SORT_NAME":       1,
+		"FILE_SORT_SIZE":       2,
+	}
+)
+
+func (x FileSort) Enum() *FileSort {
+	p := new(FileSort)
+	*p = x
+	return p
+}
+
+func (x FileSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FileSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_file_hosting_proto_enumTypes[0].Descriptor()
+}
+
+func (FileSort) Type() protoreflect.EnumType {
+	return &file_file_hosting_proto_enumTypes[0]
+}
+
+func (x FileSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FileSort.Descriptor instead.
+func (FileSort) EnumDescriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{0}
+}
+
 type UploadFileRequest struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Filename      string                    `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
-	Content       []byte                    `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	Metadata      map[string]*MetadataValue `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	ContentType   *string                   `protobuf:"bytes,4,opt,name=contentType,proto3,oneof" json:"contentType,omitempty"`
-	Duration      *string                   `protobuf:"bytes,5,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	state       protoimpl.MessageState    `protogen:"open.v1"`
+	Filename    string                    `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Content     []byte                    `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Metadata    map[string]*MetadataValue `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ContentType *string                   `protobuf:"bytes,4,opt,name=contentType,proto3,oneof" json:"contentType,omitempty"`
+	// duration is a Go duration ("90m"), an ISO 8601 duration ("P1DT12H"),
+	// whole days or weeks ("7d") or "-1" for a permanent file.
+	Duration *string `protobuf:"bytes,5,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	// expiresAt is an RFC 3339 time, instead of duration.
+	ExpiresAt *string `protobuf:"bytes,6,opt,name=expiresAt,proto3,oneof" json:"expiresAt,omitempty"`
+	// maxDownloads deletes the file after as many downloads, 0 for no limit.
+	MaxDownloads *int64 `protobuf:"varint,7,opt,name=maxDownloads,proto3,oneof" json:"maxDownloads,omitempty"`
+	// generativeName stores the file under a generated id, filename is kept as
+	// its name. The response carries the management token of the file.
+	GenerativeName *bool `protobuf:"varint,8,opt,name=generativeName,proto3,oneof" json:"generativeName,omitempty"`
+	// private files are read only with an API key or a signed URL.
+	Private *bool `protobuf:"varint,9,opt,name=private,proto3,oneof" json:"private,omitempty"`
+	// password is required to read the file, only its hash is stored.
+	Password      *string `protobuf:"bytes,10,opt,name=password,proto3,oneof" json:"password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -98,29 +160,1625 @@ func (x *UploadFileRequest) GetDuration() string {
 	return ""
 }
 
-type UploadFileResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+func (x *UploadFileRequest) GetExpiresAt() string {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *UploadFileRequest) GetMaxDownloads() int64 {
+	if x != nil && x.MaxDownloads != nil {
+		return *x.MaxDownloads
+	}
+	return 0
+}
+
+func (x *UploadFileRequest) GetGenerativeName() bool {
+	if x != nil && x.GenerativeName != nil {
+		return *x.GenerativeName
+	}
+	return false
+}
+
+func (x *UploadFileRequest) GetPrivate() bool {
+	if x != nil && x.Private != nil {
+		return *x.Private
+	}
+	return false
+}
+
+func (x *UploadFileRequest) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+type UploadFileInfo struct {
+	state          protoimpl.MessageState    `protogen:"open.v1"`
+	Filename       string                    `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Metadata       map[string]*MetadataValue `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ContentType    *string                   `protobuf:"bytes,3,opt,name=contentType,proto3,oneof" json:"contentType,omitempty"`
+	Duration       *string                   `protobuf:"bytes,4,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	Size           *int64                    `protobuf:"varint,5,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	ExpiresAt      *string                   `protobuf:"bytes,6,opt,name=expiresAt,proto3,oneof" json:"expiresAt,omitempty"`
+	MaxDownloads   *int64                    `protobuf:"varint,7,opt,name=maxDownloads,proto3,oneof" json:"maxDownloads,omitempty"`
+	GenerativeName *bool                     `protobuf:"varint,8,opt,name=generativeName,proto3,oneof" json:"generativeName,omitempty"`
+	Private        *bool                     `protobuf:"varint,9,opt,name=private,proto3,oneof" json:"private,omitempty"`
+	Password       *string                   `protobuf:"bytes,10,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UploadFileInfo) Reset() {
+	*x = UploadFileInfo{}
+	mi := &file_file_hosting_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadFileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileInfo) ProtoMessage() {}
+
+func (x *UploadFileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileInfo.ProtoReflect.Descriptor instead.
+func (*UploadFileInfo) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UploadFileInfo) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UploadFileInfo) GetMetadata() map[string]*MetadataValue {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UploadFileInfo) GetContentType() string {
+	if x != nil && x.ContentType != nil {
+		return *x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadFileInfo) GetDuration() string {
+	if x != nil && x.Duration != nil {
+		return *x.Duration
+	}
+	return ""
+}
+
+func (x *UploadFileInfo) GetSize() int64 {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return 0
+}
+
+func (x *UploadFileInfo) GetExpiresAt() string {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *UploadFileInfo) GetMaxDownloads() int64 {
+	if x != nil && x.MaxDownloads != nil {
+		return *x.MaxDownloads
+	}
+	return 0
+}
+
+func (x *UploadFileInfo) GetGenerativeName() bool {
+	if x != nil && x.GenerativeName != nil {
+		return *x.GenerativeName
+	}
+	return false
+}
+
+func (x *UploadFileInfo) GetPrivate() bool {
+	if x != nil && x.Private != nil {
+		return *x.Private
+	}
+	return false
+}
+
+func (x *UploadFileInfo) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+type UploadFileChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadFileChunk_Info
+	//	*UploadFileChunk_Chunk
+	Data          isUploadFileChunk_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *UploadFileChunk) Reset() {
+	*x = UploadFileChunk{}
+	mi := &file_file_hosting_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadFileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileChunk) ProtoMessage() {}
+
+func (x *UploadFileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileChunk.ProtoReflect.Descriptor instead.
+func (*UploadFileChunk) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UploadFileChunk) GetData() isUploadFileChunk_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadFileChunk) GetInfo() *UploadFileInfo {
+	if x != nil {
+		if x, ok := x.Data.(*UploadFileChunk_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *UploadFileChunk) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*UploadFileChunk_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadFileChunk_Data interface {
+	isUploadFileChunk_Data()
+}
+
+type UploadFileChunk_Info struct {
+	Info *UploadFileInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type UploadFileChunk_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadFileChunk_Info) isUploadFileChunk_Data() {}
+
+func (*UploadFileChunk_Chunk) isUploadFileChunk_Data() {}
+
+type UploadFileResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Id    string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// managementToken authorizes DeleteFile, UpdateExpiry and
+	// UpdateFileMetadata of a file uploaded with generativeName, passed in the
+	// x-management-token metadata. It is returned only here.
+	ManagementToken *string `protobuf:"bytes,3,opt,name=managementToken,proto3,oneof" json:"managementToken,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *UploadFileResponse) Reset() {
 	*x = UploadFileResponse{}
-	mi := &file_file_hosting_proto_msgTypes[1]
+	mi := &file_file_hosting_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileResponse) ProtoMessage() {}
+
+func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileResponse.ProtoReflect.Descriptor instead.
+func (*UploadFileResponse) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UploadFileResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *UploadFileResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UploadFileResponse) GetManagementToken() string {
+	if x != nil && x.ManagementToken != nil {
+		return *x.ManagementToken
+	}
+	return ""
+}
+
+type FileId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileId) Reset() {
+	*x = FileId{}
+	mi := &file_file_hosting_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileId) ProtoMessage() {}
+
+func (x *FileId) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileId.ProtoReflect.Descriptor instead.
+func (*FileId) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FileId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type File struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Filename      string                    `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Content       []byte                    `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	ContentType   string                    `protobuf:"bytes,3,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Metadata      map[string]*MetadataValue `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *File) Reset() {
+	*x = File{}
+	mi := &file_file_hosting_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*File) ProtoMessage() {}
+
+func (x *File) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use File.ProtoReflect.Descriptor instead.
+func (*File) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *File) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *File) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *File) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *File) GetMetadata() map[string]*MetadataValue {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type FileChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*FileChunk_Metadata
+	//	*FileChunk_Chunk
+	Data          isFileChunk_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	mi := &file_file_hosting_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FileChunk) GetData() isFileChunk_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *FileChunk) GetMetadata() *FileMetadata {
+	if x != nil {
+		if x, ok := x.Data.(*FileChunk_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *FileChunk) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*FileChunk_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isFileChunk_Data interface {
+	isFileChunk_Data()
+}
+
+type FileChunk_Metadata struct {
+	Metadata *FileMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type FileChunk_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*FileChunk_Metadata) isFileChunk_Data() {}
+
+func (*FileChunk_Chunk) isFileChunk_Data() {}
+
+type FileMetadata struct {
+	state              protoimpl.MessageState    `protogen:"open.v1"`
+	Id                 string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name               string                    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MimeType           string                    `protobuf:"bytes,3,opt,name=mimeType,proto3" json:"mimeType,omitempty"`
+	Sha1               string                    `protobuf:"bytes,4,opt,name=sha1,proto3" json:"sha1,omitempty"`
+	CreatedAt          string                    `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	ExpiredAt          string                    `protobuf:"bytes,6,opt,name=expiredAt,proto3" json:"expiredAt,omitempty"`
+	BackupName         *string                   `protobuf:"bytes,7,opt,name=backupName,proto3,oneof" json:"backupName,omitempty"`
+	Meta               map[string]*MetadataValue `protobuf:"bytes,8,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Size               int64                     `protobuf:"varint,9,opt,name=size,proto3" json:"size,omitempty"`
+	MaxDownloads       *int64                    `protobuf:"varint,10,opt,name=maxDownloads,proto3,oneof" json:"maxDownloads,omitempty"`
+	RemainingDownloads *int64                    `protobuf:"varint,11,opt,name=remainingDownloads,proto3,oneof" json:"remainingDownloads,omitempty"`
+	// owner is the identity of the JWT caller who uploaded the file.
+	Owner             *string `protobuf:"bytes,12,opt,name=owner,proto3,oneof" json:"owner,omitempty"`
+	Private           *bool   `protobuf:"varint,13,opt,name=private,proto3,oneof" json:"private,omitempty"`
+	PasswordProtected *bool   `protobuf:"varint,14,opt,name=passwordProtected,proto3,oneof" json:"passwordProtected,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FileMetadata) Reset() {
+	*x = FileMetadata{}
+	mi := &file_file_hosting_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileMetadata) ProtoMessage() {}
+
+func (x *FileMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileMetadata.ProtoReflect.Descriptor instead.
+func (*FileMetadata) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FileMetadata) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetSha1() string {
+	if x != nil {
+		return x.Sha1
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetExpiredAt() string {
+	if x != nil {
+		return x.ExpiredAt
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetBackupName() string {
+	if x != nil && x.BackupName != nil {
+		return *x.BackupName
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetMeta() map[string]*MetadataValue {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *FileMetadata) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileMetadata) GetMaxDownloads() int64 {
+	if x != nil && x.MaxDownloads != nil {
+		return *x.MaxDownloads
+	}
+	return 0
+}
+
+func (x *FileMetadata) GetRemainingDownloads() int64 {
+	if x != nil && x.RemainingDownloads != nil {
+		return *x.RemainingDownloads
+	}
+	return 0
+}
+
+func (x *FileMetadata) GetOwner() string {
+	if x != nil && x.Owner != nil {
+		return *x.Owner
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetPrivate() bool {
+	if x != nil && x.Private != nil {
+		return *x.Private
+	}
+	return false
+}
+
+func (x *FileMetadata) GetPasswordProtected() bool {
+	if x != nil && x.PasswordProtected != nil {
+		return *x.PasswordProtected
+	}
+	return false
+}
+
+type MetadataValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetadataValue) Reset() {
+	*x = MetadataValue{}
+	mi := &file_file_hosting_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetadataValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataValue) ProtoMessage() {}
+
+func (x *MetadataValue) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataValue.ProtoReflect.Descriptor instead.
+func (*MetadataValue) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MetadataValue) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetFilesRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	NamePrefix *string                `protobuf:"bytes,1,opt,name=namePrefix,proto3,oneof" json:"namePrefix,omitempty"`
+	// mimeType matches exactly or by type when it ends with "/*", e.g. "image/*".
+	MimeType *string `protobuf:"bytes,2,opt,name=mimeType,proto3,oneof" json:"mimeType,omitempty"`
+	// Time filters are RFC 3339 times, expiredAfter and expiredBefore never
+	// match permanent files.
+	CreatedAfter  *string `protobuf:"bytes,3,opt,name=createdAfter,proto3,oneof" json:"createdAfter,omitempty"`
+	CreatedBefore *string `protobuf:"bytes,4,opt,name=createdBefore,proto3,oneof" json:"createdBefore,omitempty"`
+	ExpiredAfter  *string `protobuf:"bytes,5,opt,name=expiredAfter,proto3,oneof" json:"expiredAfter,omitempty"`
+	ExpiredBefore *string `protobuf:"bytes,6,opt,name=expiredBefore,proto3,oneof" json:"expiredBefore,omitempty"`
+	HasBackup     *bool   `protobuf:"varint,7,opt,name=hasBackup,proto3,oneof" json:"hasBackup,omitempty"`
+	// meta matches files having the value among the values of each key.
+	Meta       map[string]string `protobuf:"bytes,8,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Sort       FileSort          `protobuf:"varint,9,opt,name=sort,proto3,enum=filehosting.FileSort" json:"sort,omitempty"`
+	Descending bool              `protobuf:"varint,10,opt,name=descending,proto3" json:"descending,omitempty"`
+	// limit defaults to 100, at most 1000.
+	Limit *int32 `protobuf:"varint,11,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	// cursor is nextCursor of the previous page.
+	Cursor *string `protobuf:"bytes,12,opt,name=cursor,proto3,oneof" json:"cursor,omitempty"`
+	// owner matches files uploaded by the JWT caller with the identity.
+	Owner         *string `protobuf:"bytes,13,opt,name=owner,proto3,oneof" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFilesRequest) Reset() {
+	*x = GetFilesRequest{}
+	mi := &file_file_hosting_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFilesRequest) ProtoMessage() {}
+
+func (x *GetFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFilesRequest.ProtoReflect.Descriptor instead.
+func (*GetFilesRequest) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetFilesRequest) GetNamePrefix() string {
+	if x != nil && x.NamePrefix != nil {
+		return *x.NamePrefix
+	}
+	return ""
+}
+
+func (x *GetFilesRequest) GetMimeType() string {
+	if x != nil && x.MimeType != nil {
+		return *x.MimeType
+	}
+	return ""
+}
+
+func (x *GetFilesRequest) GetCreatedAfter() string {
+	if x != nil && x.CreatedAfter != nil {
+		return *x.CreatedAfter
+	}
+	return ""
+}
+
+func (x *GetFilesRequest) GetCreatedBefore() string {
+	if x != nil && x.CreatedBefore != nil {
+		return *x.CreatedBefore
+	}
+	return ""
+}
+
+func (x *GetFilesRequest) GetExpiredAfter() string {
+	if x != nil && x.ExpiredAfter != nil {
+		return *x.ExpiredAfter
+	}
+	return ""
+}
+
+func (x *GetFilesRequest) GetExpiredBefore() string {
+	if x != nil && x.ExpiredBefore != nil {
+		return *x.ExpiredBefore
+	}
+	return ""
+}
+
+func (x *GetFilesRequest) GetHasBackup() bool {
+	if x != nil && x.HasBackup != nil {
+		return *x.HasBackup
+	}
+	return false
+}
+
+func (x *GetFilesRequest) GetMeta() map[string]string {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GetFilesRequest) GetSort() FileSort {
+	if x != nil {
+		return x.Sort
+	}
+	return FileSort_FILE_SORT_CREATED_AT
+}
+
+func (x *GetFilesRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *GetFilesRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *GetFilesRequest) GetCursor() string {
+	if x != nil && x.Cursor != nil {
+		return *x.Cursor
+	}
+	return ""
+}
+
+func (x *GetFilesRequest) GetOwner() string {
+	if x != nil && x.Owner != nil {
+		return *x.Owner
+	}
+	return ""
+}
+
+type Files struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Metadata []*FileMetadata        `protobuf:"bytes,1,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	// nextCursor is empty on the last page.
+	NextCursor string `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	// total is the count of files matching the filters on all pages.
+	Total         int64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Files) Reset() {
+	*x = Files{}
+	mi := &file_file_hosting_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Files) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Files) ProtoMessage() {}
+
+func (x *Files) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Files.ProtoReflect.Descriptor instead.
+func (*Files) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Files) GetMetadata() []*FileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Files) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *Files) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type RenameFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	NewName       string                 `protobuf:"bytes,2,opt,name=newName,proto3" json:"newName,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFileRequest) Reset() {
+	*x = RenameFileRequest{}
+	mi := &file_file_hosting_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFileRequest) ProtoMessage() {}
+
+func (x *RenameFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFileRequest.ProtoReflect.Descriptor instead.
+func (*RenameFileRequest) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RenameFileRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameFileRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+type UpdateExpiryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// duration and expiresAt accept the same values as in UploadFileRequest,
+	// duration "-1" makes the file permanent.
+	Duration      *string `protobuf:"bytes,2,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	ExpiresAt     *string `protobuf:"bytes,3,opt,name=expiresAt,proto3,oneof" json:"expiresAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateExpiryRequest) Reset() {
+	*x = UpdateExpiryRequest{}
+	mi := &file_file_hosting_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateExpiryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateExpiryRequest) ProtoMessage() {}
+
+func (x *UpdateExpiryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateExpiryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateExpiryRequest) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateExpiryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateExpiryRequest) GetDuration() string {
+	if x != nil && x.Duration != nil {
+		return *x.Duration
+	}
+	return ""
+}
+
+func (x *UpdateExpiryRequest) GetExpiresAt() string {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return ""
+}
+
+type UpdateFileMetadataRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata      map[string]*MetadataValue `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFileMetadataRequest) Reset() {
+	*x = UpdateFileMetadataRequest{}
+	mi := &file_file_hosting_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFileMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFileMetadataRequest) ProtoMessage() {}
+
+func (x *UpdateFileMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFileMetadataRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFileMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateFileMetadataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateFileMetadataRequest) GetMetadata() map[string]*MetadataValue {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type SignFileUrlRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// lifetime accepts the same values as duration of UploadFileRequest, the
+	// default lifetime is used without it.
+	Lifetime *string `protobuf:"bytes,2,opt,name=lifetime,proto3,oneof" json:"lifetime,omitempty"`
+	// ip and method (GET or HEAD) bind the URL to the client and the method.
+	Ip            *string `protobuf:"bytes,3,opt,name=ip,proto3,oneof" json:"ip,omitempty"`
+	Method        *string `protobuf:"bytes,4,opt,name=method,proto3,oneof" json:"method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignFileUrlRequest) Reset() {
+	*x = SignFileUrlRequest{}
+	mi := &file_file_hosting_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignFileUrlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignFileUrlRequest) ProtoMessage() {}
+
+func (x *SignFileUrlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignFileUrlRequest.ProtoReflect.Descriptor instead.
+func (*SignFileUrlRequest) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SignFileUrlRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SignFileUrlRequest) GetLifetime() string {
+	if x != nil && x.Lifetime != nil {
+		return *x.Lifetime
+	}
+	return ""
+}
+
+func (x *SignFileUrlRequest) GetIp() string {
+	if x != nil && x.Ip != nil {
+		return *x.Ip
+	}
+	return ""
+}
+
+func (x *SignFileUrlRequest) GetMethod() string {
+	if x != nil && x.Method != nil {
+		return *x.Method
+	}
+	return ""
+}
+
+type SignedFileUrl struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,2,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedFileUrl) Reset() {
+	*x = SignedFileUrl{}
+	mi := &file_file_hosting_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedFileUrl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedFileUrl) ProtoMessage() {}
+
+func (x *SignedFileUrl) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedFileUrl.ProtoReflect.Descriptor instead.
+func (*SignedFileUrl) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SignedFileUrl) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SignedFileUrl) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type TrashedFileId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashedFileId) Reset() {
+	*x = TrashedFileId{}
+	mi := &file_file_hosting_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashedFileId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashedFileId) ProtoMessage() {}
+
+func (x *TrashedFileId) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashedFileId.ProtoReflect.Descriptor instead.
+func (*TrashedFileId) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TrashedFileId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type TrashedFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileId        string                 `protobuf:"bytes,2,opt,name=fileId,proto3" json:"fileId,omitempty"`
+	DeletedAt     string                 `protobuf:"bytes,3,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`
+	PurgeAt       string                 `protobuf:"bytes,4,opt,name=purgeAt,proto3" json:"purgeAt,omitempty"`
+	Metadata      *FileMetadata          `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashedFile) Reset() {
+	*x = TrashedFile{}
+	mi := &file_file_hosting_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashedFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashedFile) ProtoMessage() {}
+
+func (x *TrashedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashedFile.ProtoReflect.Descriptor instead.
+func (*TrashedFile) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TrashedFile) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TrashedFile) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *TrashedFile) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+func (x *TrashedFile) GetPurgeAt() string {
+	if x != nil {
+		return x.PurgeAt
+	}
+	return ""
+}
+
+func (x *TrashedFile) GetMetadata() *FileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type TrashedFiles struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*TrashedFile         `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashedFiles) Reset() {
+	*x = TrashedFiles{}
+	mi := &file_file_hosting_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashedFiles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashedFiles) ProtoMessage() {}
+
+func (x *TrashedFiles) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashedFiles.ProtoReflect.Descriptor instead.
+func (*TrashedFiles) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TrashedFiles) GetFiles() []*TrashedFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type FileVersionId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileVersionId) Reset() {
+	*x = FileVersionId{}
+	mi := &file_file_hosting_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileVersionId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileVersionId) ProtoMessage() {}
+
+func (x *FileVersionId) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileVersionId.ProtoReflect.Descriptor instead.
+func (*FileVersionId) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FileVersionId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FileVersionId) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type FileVersion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// version is the unix time in nanoseconds when the version was uploaded.
+	Version       string        `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Current       bool          `protobuf:"varint,2,opt,name=current,proto3" json:"current,omitempty"`
+	Metadata      *FileMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileVersion) Reset() {
+	*x = FileVersion{}
+	mi := &file_file_hosting_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileVersion) ProtoMessage() {}
+
+func (x *FileVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileVersion.ProtoReflect.Descriptor instead.
+func (*FileVersion) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FileVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *FileVersion) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+func (x *FileVersion) GetMetadata() *FileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type FileVersions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*FileVersion         `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileVersions) Reset() {
+	*x = FileVersions{}
+	mi := &file_file_hosting_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileVersions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileVersions) ProtoMessage() {}
+
+func (x *FileVersions) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileVersions.ProtoReflect.Descriptor instead.
+func (*FileVersions) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FileVersions) GetVersions() []*FileVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type CreatePresignedUploadRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Filename      string                    `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Metadata      map[string]*MetadataValue `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Duration      *string                   `protobuf:"bytes,3,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	ExpiresAt     *string                   `protobuf:"bytes,4,opt,name=expiresAt,proto3,oneof" json:"expiresAt,omitempty"`
+	MaxDownloads  *int64                    `protobuf:"varint,5,opt,name=maxDownloads,proto3,oneof" json:"maxDownloads,omitempty"`
+	Private       *bool                     `protobuf:"varint,6,opt,name=private,proto3,oneof" json:"private,omitempty"`
+	Password      *string                   `protobuf:"bytes,7,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePresignedUploadRequest) Reset() {
+	*x = CreatePresignedUploadRequest{}
+	mi := &file_file_hosting_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePresignedUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePresignedUploadRequest) ProtoMessage() {}
+
+func (x *CreatePresignedUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePresignedUploadRequest.ProtoReflect.Descriptor instead.
+func (*CreatePresignedUploadRequest) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CreatePresignedUploadRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *CreatePresignedUploadRequest) GetMetadata() map[string]*MetadataValue {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CreatePresignedUploadRequest) GetDuration() string {
+	if x != nil && x.Duration != nil {
+		return *x.Duration
+	}
+	return ""
+}
+
+func (x *CreatePresignedUploadRequest) GetExpiresAt() string {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *CreatePresignedUploadRequest) GetMaxDownloads() int64 {
+	if x != nil && x.MaxDownloads != nil {
+		return *x.MaxDownloads
+	}
+	return 0
+}
+
+func (x *CreatePresignedUploadRequest) GetPrivate() bool {
+	if x != nil && x.Private != nil {
+		return *x.Private
+	}
+	return false
+}
+
+func (x *CreatePresignedUploadRequest) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+type PresignedUpload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	UrlExpiredAt  string                 `protobuf:"bytes,3,opt,name=urlExpiredAt,proto3" json:"urlExpiredAt,omitempty"`
+	ExpiredAt     string                 `protobuf:"bytes,4,opt,name=expiredAt,proto3" json:"expiredAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PresignedUpload) Reset() {
+	*x = PresignedUpload{}
+	mi := &file_file_hosting_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UploadFileResponse) String() string {
+func (x *PresignedUpload) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadFileResponse) ProtoMessage() {}
+func (*PresignedUpload) ProtoMessage() {}
 
-func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_hosting_proto_msgTypes[1]
+func (x *PresignedUpload) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -131,47 +1789,61 @@ func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadFileResponse.ProtoReflect.Descriptor instead.
-func (*UploadFileResponse) Descriptor() ([]byte, []int) {
-	return file_file_hosting_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use PresignedUpload.ProtoReflect.Descriptor instead.
+func (*PresignedUpload) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *UploadFileResponse) GetUrl() string {
+func (x *PresignedUpload) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PresignedUpload) GetUrl() string {
 	if x != nil {
 		return x.Url
 	}
 	return ""
 }
 
-func (x *UploadFileResponse) GetId() string {
+func (x *PresignedUpload) GetUrlExpiredAt() string {
 	if x != nil {
-		return x.Id
+		return x.UrlExpiredAt
 	}
 	return ""
 }
 
-type FileId struct {
+func (x *PresignedUpload) GetExpiredAt() string {
+	if x != nil {
+		return x.ExpiredAt
+	}
+	return ""
+}
+
+type PresignedUploadId struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FileId) Reset() {
-	*x = FileId{}
-	mi := &file_file_hosting_proto_msgTypes[2]
+func (x *PresignedUploadId) Reset() {
+	*x = PresignedUploadId{}
+	mi := &file_file_hosting_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FileId) String() string {
+func (x *PresignedUploadId) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FileId) ProtoMessage() {}
+func (*PresignedUploadId) ProtoMessage() {}
 
-func (x *FileId) ProtoReflect() protoreflect.Message {
-	mi := &file_file_hosting_proto_msgTypes[2]
+func (x *PresignedUploadId) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -182,43 +1854,43 @@ func (x *FileId) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FileId.ProtoReflect.Descriptor instead.
-func (*FileId) Descriptor() ([]byte, []int) {
-	return file_file_hosting_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use PresignedUploadId.ProtoReflect.Descriptor instead.
+func (*PresignedUploadId) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *FileId) GetId() string {
+func (x *PresignedUploadId) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type File struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Filename      string                    `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
-	Content       []byte                    `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	ContentType   string                    `protobuf:"bytes,3,opt,name=contentType,proto3" json:"contentType,omitempty"`
-	Metadata      map[string]*MetadataValue `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+type CreateWebhookRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Url    string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Secret *string                `protobuf:"bytes,2,opt,name=secret,proto3,oneof" json:"secret,omitempty"`
+	// events are sent to the webhook, all events when empty.
+	Events        []string `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *File) Reset() {
-	*x = File{}
-	mi := &file_file_hosting_proto_msgTypes[3]
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_file_hosting_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *File) String() string {
+func (x *CreateWebhookRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*File) ProtoMessage() {}
+func (*CreateWebhookRequest) ProtoMessage() {}
 
-func (x *File) ProtoReflect() protoreflect.Message {
-	mi := &file_file_hosting_proto_msgTypes[3]
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -229,68 +1901,60 @@ func (x *File) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use File.ProtoReflect.Descriptor instead.
-func (*File) Descriptor() ([]byte, []int) {
-	return file_file_hosting_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *File) GetFilename() string {
+func (x *CreateWebhookRequest) GetUrl() string {
 	if x != nil {
-		return x.Filename
+		return x.Url
 	}
 	return ""
 }
 
-func (x *File) GetContent() []byte {
-	if x != nil {
-		return x.Content
-	}
-	return nil
-}
-
-func (x *File) GetContentType() string {
-	if x != nil {
-		return x.ContentType
+func (x *CreateWebhookRequest) GetSecret() string {
+	if x != nil && x.Secret != nil {
+		return *x.Secret
 	}
 	return ""
 }
 
-func (x *File) GetMetadata() map[string]*MetadataValue {
+func (x *CreateWebhookRequest) GetEvents() []string {
 	if x != nil {
-		return x.Metadata
+		return x.Events
 	}
 	return nil
 }
 
-type FileMetadata struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	MimeType      string                    `protobuf:"bytes,3,opt,name=mimeType,proto3" json:"mimeType,omitempty"`
-	Sha1          string                    `protobuf:"bytes,4,opt,name=sha1,proto3" json:"sha1,omitempty"`
-	CreatedAt     string                    `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	ExpiredAt     string                    `protobuf:"bytes,6,opt,name=expiredAt,proto3" json:"expiredAt,omitempty"`
-	BackupName    *string                   `protobuf:"bytes,7,opt,name=backupName,proto3,oneof" json:"backupName,omitempty"`
-	Meta          map[string]*MetadataValue `protobuf:"bytes,8,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+type Webhook struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url    string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Secret *string                `protobuf:"bytes,3,opt,name=secret,proto3,oneof" json:"secret,omitempty"`
+	Events []string               `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	// static webhooks are set in the config and can not be deleted.
+	Static        bool   `protobuf:"varint,5,opt,name=static,proto3" json:"static,omitempty"`
+	CreatedAt     string `protobuf:"bytes,6,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FileMetadata) Reset() {
-	*x = FileMetadata{}
-	mi := &file_file_hosting_proto_msgTypes[4]
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_file_hosting_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FileMetadata) String() string {
+func (x *Webhook) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FileMetadata) ProtoMessage() {}
+func (*Webhook) ProtoMessage() {}
 
-func (x *FileMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_file_hosting_proto_msgTypes[4]
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -301,89 +1965,75 @@ func (x *FileMetadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FileMetadata.ProtoReflect.Descriptor instead.
-func (*FileMetadata) Descriptor() ([]byte, []int) {
-	return file_file_hosting_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *FileMetadata) GetId() string {
+func (x *Webhook) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *FileMetadata) GetName() string {
+func (x *Webhook) GetUrl() string {
 	if x != nil {
-		return x.Name
+		return x.Url
 	}
 	return ""
 }
 
-func (x *FileMetadata) GetMimeType() string {
-	if x != nil {
-		return x.MimeType
+func (x *Webhook) GetSecret() string {
+	if x != nil && x.Secret != nil {
+		return *x.Secret
 	}
 	return ""
 }
 
-func (x *FileMetadata) GetSha1() string {
+func (x *Webhook) GetEvents() []string {
 	if x != nil {
-		return x.Sha1
+		return x.Events
 	}
-	return ""
+	return nil
 }
 
-func (x *FileMetadata) GetCreatedAt() string {
+func (x *Webhook) GetStatic() bool {
 	if x != nil {
-		return x.CreatedAt
+		return x.Static
 	}
-	return ""
+	return false
 }
 
-func (x *FileMetadata) GetExpiredAt() string {
+func (x *Webhook) GetCreatedAt() string {
 	if x != nil {
-		return x.ExpiredAt
-	}
-	return ""
-}
-
-func (x *FileMetadata) GetBackupName() string {
-	if x != nil && x.BackupName != nil {
-		return *x.BackupName
+		return x.CreatedAt
 	}
 	return ""
 }
 
-func (x *FileMetadata) GetMeta() map[string]*MetadataValue {
-	if x != nil {
-		return x.Meta
-	}
-	return nil
-}
-
-type MetadataValue struct {
+type Webhooks struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MetadataValue) Reset() {
-	*x = MetadataValue{}
-	mi := &file_file_hosting_proto_msgTypes[5]
+func (x *Webhooks) Reset() {
+	*x = Webhooks{}
+	mi := &file_file_hosting_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MetadataValue) String() string {
+func (x *Webhooks) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MetadataValue) ProtoMessage() {}
+func (*Webhooks) ProtoMessage() {}
 
-func (x *MetadataValue) ProtoReflect() protoreflect.Message {
-	mi := &file_file_hosting_proto_msgTypes[5]
+func (x *Webhooks) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -394,40 +2044,40 @@ func (x *MetadataValue) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MetadataValue.ProtoReflect.Descriptor instead.
-func (*MetadataValue) Descriptor() ([]byte, []int) {
-	return file_file_hosting_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Webhooks.ProtoReflect.Descriptor instead.
+func (*Webhooks) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *MetadataValue) GetValues() []string {
+func (x *Webhooks) GetWebhooks() []*Webhook {
 	if x != nil {
-		return x.Values
+		return x.Webhooks
 	}
 	return nil
 }
 
-type Files struct {
+type WebhookId struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Metadata      []*FileMetadata        `protobuf:"bytes,1,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Files) Reset() {
-	*x = Files{}
-	mi := &file_file_hosting_proto_msgTypes[6]
+func (x *WebhookId) Reset() {
+	*x = WebhookId{}
+	mi := &file_file_hosting_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Files) String() string {
+func (x *WebhookId) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Files) ProtoMessage() {}
+func (*WebhookId) ProtoMessage() {}
 
-func (x *Files) ProtoReflect() protoreflect.Message {
-	mi := &file_file_hosting_proto_msgTypes[6]
+func (x *WebhookId) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -438,41 +2088,51 @@ func (x *Files) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Files.ProtoReflect.Descriptor instead.
-func (*Files) Descriptor() ([]byte, []int) {
-	return file_file_hosting_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use WebhookId.ProtoReflect.Descriptor instead.
+func (*WebhookId) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *Files) GetMetadata() []*FileMetadata {
+func (x *WebhookId) GetId() string {
 	if x != nil {
-		return x.Metadata
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-type RenameFileRequest struct {
+type WebhookDelivery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	NewName       string                 `protobuf:"bytes,2,opt,name=newName,proto3" json:"newName,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhookId,proto3" json:"webhookId,omitempty"`
+	EventId       string                 `protobuf:"bytes,3,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	EventType     string                 `protobuf:"bytes,4,opt,name=eventType,proto3" json:"eventType,omitempty"`
+	FileId        string                 `protobuf:"bytes,5,opt,name=fileId,proto3" json:"fileId,omitempty"`
+	Attempt       int32                  `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,7,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
+	Error         *string                `protobuf:"bytes,8,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Succeeded     bool                   `protobuf:"varint,9,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Duration      string                 `protobuf:"bytes,10,opt,name=duration,proto3" json:"duration,omitempty"`
+	AttemptedAt   string                 `protobuf:"bytes,11,opt,name=attemptedAt,proto3" json:"attemptedAt,omitempty"`
+	NextAttemptAt *string                `protobuf:"bytes,12,opt,name=nextAttemptAt,proto3,oneof" json:"nextAttemptAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RenameFileRequest) Reset() {
-	*x = RenameFileRequest{}
-	mi := &file_file_hosting_proto_msgTypes[7]
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_file_hosting_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RenameFileRequest) String() string {
+func (x *WebhookDelivery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameFileRequest) ProtoMessage() {}
+func (*WebhookDelivery) ProtoMessage() {}
 
-func (x *RenameFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_hosting_proto_msgTypes[7]
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -483,44 +2143,202 @@ func (x *RenameFileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameFileRequest.ProtoReflect.Descriptor instead.
-func (*RenameFileRequest) Descriptor() ([]byte, []int) {
-	return file_file_hosting_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *RenameFileRequest) GetId() string {
+func (x *WebhookDelivery) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *RenameFileRequest) GetNewName() string {
+func (x *WebhookDelivery) GetWebhookId() string {
 	if x != nil {
-		return x.NewName
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *WebhookDelivery) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttemptedAt() string {
+	if x != nil {
+		return x.AttemptedAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() string {
+	if x != nil && x.NextAttemptAt != nil {
+		return *x.NextAttemptAt
 	}
 	return ""
 }
 
+type WebhookDeliveries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDeliveries) Reset() {
+	*x = WebhookDeliveries{}
+	mi := &file_file_hosting_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDeliveries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeliveries) ProtoMessage() {}
+
+func (x *WebhookDeliveries) ProtoReflect() protoreflect.Message {
+	mi := &file_file_hosting_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDeliveries.ProtoReflect.Descriptor instead.
+func (*WebhookDeliveries) Descriptor() ([]byte, []int) {
+	return file_file_hosting_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WebhookDeliveries) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
 var File_file_hosting_proto protoreflect.FileDescriptor
 
 const file_file_hosting_proto_rawDesc = "" +
 	"\n" +
-	"\x12file-hosting.proto\x12\vfilehosting\x1a\x1bgoogle/protobuf/empty.proto\"\xd1\x02\n" +
+	"\x12file-hosting.proto\x12\vfilehosting\x1a\x1bgoogle/protobuf/empty.proto\"\xd5\x04\n" +
 	"\x11UploadFileRequest\x12\x1a\n" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\fR\acontent\x12H\n" +
 	"\bmetadata\x18\x03 \x03(\v2,.filehosting.UploadFileRequest.MetadataEntryR\bmetadata\x12%\n" +
 	"\vcontentType\x18\x04 \x01(\tH\x00R\vcontentType\x88\x01\x01\x12\x1f\n" +
-	"\bduration\x18\x05 \x01(\tH\x01R\bduration\x88\x01\x01\x1aW\n" +
+	"\bduration\x18\x05 \x01(\tH\x01R\bduration\x88\x01\x01\x12!\n" +
+	"\texpiresAt\x18\x06 \x01(\tH\x02R\texpiresAt\x88\x01\x01\x12'\n" +
+	"\fmaxDownloads\x18\a \x01(\x03H\x03R\fmaxDownloads\x88\x01\x01\x12+\n" +
+	"\x0egenerativeName\x18\b \x01(\bH\x04R\x0egenerativeName\x88\x01\x01\x12\x1d\n" +
+	"\aprivate\x18\t \x01(\bH\x05R\aprivate\x88\x01\x01\x12\x1f\n" +
+	"\bpassword\x18\n" +
+	" \x01(\tH\x06R\bpassword\x88\x01\x01\x1aW\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.filehosting.MetadataValueR\x05value:\x028\x01B\x0e\n" +
+	"\f_contentTypeB\v\n" +
+	"\t_durationB\f\n" +
+	"\n" +
+	"_expiresAtB\x0f\n" +
+	"\r_maxDownloadsB\x11\n" +
+	"\x0f_generativeNameB\n" +
+	"\n" +
+	"\b_privateB\v\n" +
+	"\t_password\"\xd7\x04\n" +
+	"\x0eUploadFileInfo\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12E\n" +
+	"\bmetadata\x18\x02 \x03(\v2).filehosting.UploadFileInfo.MetadataEntryR\bmetadata\x12%\n" +
+	"\vcontentType\x18\x03 \x01(\tH\x00R\vcontentType\x88\x01\x01\x12\x1f\n" +
+	"\bduration\x18\x04 \x01(\tH\x01R\bduration\x88\x01\x01\x12\x17\n" +
+	"\x04size\x18\x05 \x01(\x03H\x02R\x04size\x88\x01\x01\x12!\n" +
+	"\texpiresAt\x18\x06 \x01(\tH\x03R\texpiresAt\x88\x01\x01\x12'\n" +
+	"\fmaxDownloads\x18\a \x01(\x03H\x04R\fmaxDownloads\x88\x01\x01\x12+\n" +
+	"\x0egenerativeName\x18\b \x01(\bH\x05R\x0egenerativeName\x88\x01\x01\x12\x1d\n" +
+	"\aprivate\x18\t \x01(\bH\x06R\aprivate\x88\x01\x01\x12\x1f\n" +
+	"\bpassword\x18\n" +
+	" \x01(\tH\aR\bpassword\x88\x01\x01\x1aW\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
 	"\x05value\x18\x02 \x01(\v2\x1a.filehosting.MetadataValueR\x05value:\x028\x01B\x0e\n" +
 	"\f_contentTypeB\v\n" +
-	"\t_duration\"6\n" +
+	"\t_durationB\a\n" +
+	"\x05_sizeB\f\n" +
+	"\n" +
+	"_expiresAtB\x0f\n" +
+	"\r_maxDownloadsB\x11\n" +
+	"\x0f_generativeNameB\n" +
+	"\n" +
+	"\b_privateB\v\n" +
+	"\t_password\"d\n" +
+	"\x0fUploadFileChunk\x121\n" +
+	"\x04info\x18\x01 \x01(\v2\x1b.filehosting.UploadFileInfoH\x00R\x04info\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"y\n" +
 	"\x12UploadFileResponse\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\tR\x02id\"\x18\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12-\n" +
+	"\x0fmanagementToken\x18\x03 \x01(\tH\x00R\x0fmanagementToken\x88\x01\x01B\x12\n" +
+	"\x10_managementToken\"\x18\n" +
 	"\x06FileId\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\xf4\x01\n" +
 	"\x04File\x12\x1a\n" +
@@ -530,7 +2348,11 @@ const file_file_hosting_proto_rawDesc = "" +
 	"\bmetadata\x18\x04 \x03(\v2\x1f.filehosting.File.MetadataEntryR\bmetadata\x1aW\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
-	"\x05value\x18\x02 \x01(\v2\x1a.filehosting.MetadataValueR\x05value:\x028\x01\"\xe0\x02\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.filehosting.MetadataValueR\x05value:\x028\x01\"d\n" +
+	"\tFileChunk\x127\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x19.filehosting.FileMetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"\x93\x05\n" +
 	"\fFileMetadata\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
@@ -541,28 +2363,206 @@ const file_file_hosting_proto_rawDesc = "" +
 	"\n" +
 	"backupName\x18\a \x01(\tH\x00R\n" +
 	"backupName\x88\x01\x01\x127\n" +
-	"\x04meta\x18\b \x03(\v2#.filehosting.FileMetadata.MetaEntryR\x04meta\x1aS\n" +
+	"\x04meta\x18\b \x03(\v2#.filehosting.FileMetadata.MetaEntryR\x04meta\x12\x12\n" +
+	"\x04size\x18\t \x01(\x03R\x04size\x12'\n" +
+	"\fmaxDownloads\x18\n" +
+	" \x01(\x03H\x01R\fmaxDownloads\x88\x01\x01\x123\n" +
+	"\x12remainingDownloads\x18\v \x01(\x03H\x02R\x12remainingDownloads\x88\x01\x01\x12\x19\n" +
+	"\x05owner\x18\f \x01(\tH\x03R\x05owner\x88\x01\x01\x12\x1d\n" +
+	"\aprivate\x18\r \x01(\bH\x04R\aprivate\x88\x01\x01\x121\n" +
+	"\x11passwordProtected\x18\x0e \x01(\bH\x05R\x11passwordProtected\x88\x01\x01\x1aS\n" +
 	"\tMetaEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
 	"\x05value\x18\x02 \x01(\v2\x1a.filehosting.MetadataValueR\x05value:\x028\x01B\r\n" +
-	"\v_backupName\"'\n" +
+	"\v_backupNameB\x0f\n" +
+	"\r_maxDownloadsB\x15\n" +
+	"\x13_remainingDownloadsB\b\n" +
+	"\x06_ownerB\n" +
+	"\n" +
+	"\b_privateB\x14\n" +
+	"\x12_passwordProtected\"'\n" +
 	"\rMetadataValue\x12\x16\n" +
-	"\x06values\x18\x01 \x03(\tR\x06values\">\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"\xc4\x05\n" +
+	"\x0fGetFilesRequest\x12#\n" +
+	"\n" +
+	"namePrefix\x18\x01 \x01(\tH\x00R\n" +
+	"namePrefix\x88\x01\x01\x12\x1f\n" +
+	"\bmimeType\x18\x02 \x01(\tH\x01R\bmimeType\x88\x01\x01\x12'\n" +
+	"\fcreatedAfter\x18\x03 \x01(\tH\x02R\fcreatedAfter\x88\x01\x01\x12)\n" +
+	"\rcreatedBefore\x18\x04 \x01(\tH\x03R\rcreatedBefore\x88\x01\x01\x12'\n" +
+	"\fexpiredAfter\x18\x05 \x01(\tH\x04R\fexpiredAfter\x88\x01\x01\x12)\n" +
+	"\rexpiredBefore\x18\x06 \x01(\tH\x05R\rexpiredBefore\x88\x01\x01\x12!\n" +
+	"\thasBackup\x18\a \x01(\bH\x06R\thasBackup\x88\x01\x01\x12:\n" +
+	"\x04meta\x18\b \x03(\v2&.filehosting.GetFilesRequest.MetaEntryR\x04meta\x12)\n" +
+	"\x04sort\x18\t \x01(\x0e2\x15.filehosting.FileSortR\x04sort\x12\x1e\n" +
+	"\n" +
+	"descending\x18\n" +
+	" \x01(\bR\n" +
+	"descending\x12\x19\n" +
+	"\x05limit\x18\v \x01(\x05H\aR\x05limit\x88\x01\x01\x12\x1b\n" +
+	"\x06cursor\x18\f \x01(\tH\bR\x06cursor\x88\x01\x01\x12\x19\n" +
+	"\x05owner\x18\r \x01(\tH\tR\x05owner\x88\x01\x01\x1a7\n" +
+	"\tMetaEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\r\n" +
+	"\v_namePrefixB\v\n" +
+	"\t_mimeTypeB\x0f\n" +
+	"\r_createdAfterB\x10\n" +
+	"\x0e_createdBeforeB\x0f\n" +
+	"\r_expiredAfterB\x10\n" +
+	"\x0e_expiredBeforeB\f\n" +
+	"\n" +
+	"_hasBackupB\b\n" +
+	"\x06_limitB\t\n" +
+	"\a_cursorB\b\n" +
+	"\x06_owner\"t\n" +
 	"\x05Files\x125\n" +
-	"\bmetadata\x18\x01 \x03(\v2\x19.filehosting.FileMetadataR\bmetadata\"=\n" +
+	"\bmetadata\x18\x01 \x03(\v2\x19.filehosting.FileMetadataR\bmetadata\x12\x1e\n" +
+	"\n" +
+	"nextCursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\"=\n" +
 	"\x11RenameFileRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
-	"\anewName\x18\x02 \x01(\tR\anewName2\x8b\x03\n" +
+	"\anewName\x18\x02 \x01(\tR\anewName\"\x84\x01\n" +
+	"\x13UpdateExpiryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\bduration\x18\x02 \x01(\tH\x00R\bduration\x88\x01\x01\x12!\n" +
+	"\texpiresAt\x18\x03 \x01(\tH\x01R\texpiresAt\x88\x01\x01B\v\n" +
+	"\t_durationB\f\n" +
+	"\n" +
+	"_expiresAt\"\xd6\x01\n" +
+	"\x19UpdateFileMetadataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12P\n" +
+	"\bmetadata\x18\x02 \x03(\v24.filehosting.UpdateFileMetadataRequest.MetadataEntryR\bmetadata\x1aW\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.filehosting.MetadataValueR\x05value:\x028\x01\"\x96\x01\n" +
+	"\x12SignFileUrlRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\blifetime\x18\x02 \x01(\tH\x00R\blifetime\x88\x01\x01\x12\x13\n" +
+	"\x02ip\x18\x03 \x01(\tH\x01R\x02ip\x88\x01\x01\x12\x1b\n" +
+	"\x06method\x18\x04 \x01(\tH\x02R\x06method\x88\x01\x01B\v\n" +
+	"\t_lifetimeB\x05\n" +
+	"\x03_ipB\t\n" +
+	"\a_method\"?\n" +
+	"\rSignedFileUrl\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1c\n" +
+	"\texpiresAt\x18\x02 \x01(\tR\texpiresAt\"\x1f\n" +
+	"\rTrashedFileId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa4\x01\n" +
+	"\vTrashedFile\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06fileId\x18\x02 \x01(\tR\x06fileId\x12\x1c\n" +
+	"\tdeletedAt\x18\x03 \x01(\tR\tdeletedAt\x12\x18\n" +
+	"\apurgeAt\x18\x04 \x01(\tR\apurgeAt\x125\n" +
+	"\bmetadata\x18\x05 \x01(\v2\x19.filehosting.FileMetadataR\bmetadata\">\n" +
+	"\fTrashedFiles\x12.\n" +
+	"\x05files\x18\x01 \x03(\v2\x18.filehosting.TrashedFileR\x05files\"9\n" +
+	"\rFileVersionId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"x\n" +
+	"\vFileVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x18\n" +
+	"\acurrent\x18\x02 \x01(\bR\acurrent\x125\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x19.filehosting.FileMetadataR\bmetadata\"D\n" +
+	"\fFileVersions\x124\n" +
+	"\bversions\x18\x01 \x03(\v2\x18.filehosting.FileVersionR\bversions\"\xda\x03\n" +
+	"\x1cCreatePresignedUploadRequest\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12S\n" +
+	"\bmetadata\x18\x02 \x03(\v27.filehosting.CreatePresignedUploadRequest.MetadataEntryR\bmetadata\x12\x1f\n" +
+	"\bduration\x18\x03 \x01(\tH\x00R\bduration\x88\x01\x01\x12!\n" +
+	"\texpiresAt\x18\x04 \x01(\tH\x01R\texpiresAt\x88\x01\x01\x12'\n" +
+	"\fmaxDownloads\x18\x05 \x01(\x03H\x02R\fmaxDownloads\x88\x01\x01\x12\x1d\n" +
+	"\aprivate\x18\x06 \x01(\bH\x03R\aprivate\x88\x01\x01\x12\x1f\n" +
+	"\bpassword\x18\a \x01(\tH\x04R\bpassword\x88\x01\x01\x1aW\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.filehosting.MetadataValueR\x05value:\x028\x01B\v\n" +
+	"\t_durationB\f\n" +
+	"\n" +
+	"_expiresAtB\x0f\n" +
+	"\r_maxDownloadsB\n" +
+	"\n" +
+	"\b_privateB\v\n" +
+	"\t_password\"u\n" +
+	"\x0fPresignedUpload\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\"\n" +
+	"\furlExpiredAt\x18\x03 \x01(\tR\furlExpiredAt\x12\x1c\n" +
+	"\texpiredAt\x18\x04 \x01(\tR\texpiredAt\"#\n" +
+	"\x11PresignedUploadId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"h\n" +
+	"\x14CreateWebhookRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1b\n" +
+	"\x06secret\x18\x02 \x01(\tH\x00R\x06secret\x88\x01\x01\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06eventsB\t\n" +
+	"\a_secret\"\xa1\x01\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1b\n" +
+	"\x06secret\x18\x03 \x01(\tH\x00R\x06secret\x88\x01\x01\x12\x16\n" +
+	"\x06events\x18\x04 \x03(\tR\x06events\x12\x16\n" +
+	"\x06static\x18\x05 \x01(\bR\x06static\x12\x1c\n" +
+	"\tcreatedAt\x18\x06 \x01(\tR\tcreatedAtB\t\n" +
+	"\a_secret\"<\n" +
+	"\bWebhooks\x120\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x14.filehosting.WebhookR\bwebhooks\"\x1b\n" +
+	"\tWebhookId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x87\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\twebhookId\x18\x02 \x01(\tR\twebhookId\x12\x18\n" +
+	"\aeventId\x18\x03 \x01(\tR\aeventId\x12\x1c\n" +
+	"\teventType\x18\x04 \x01(\tR\teventType\x12\x16\n" +
+	"\x06fileId\x18\x05 \x01(\tR\x06fileId\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\x12\x1e\n" +
+	"\n" +
+	"statusCode\x18\a \x01(\x05R\n" +
+	"statusCode\x12\x19\n" +
+	"\x05error\x18\b \x01(\tH\x00R\x05error\x88\x01\x01\x12\x1c\n" +
+	"\tsucceeded\x18\t \x01(\bR\tsucceeded\x12\x1a\n" +
+	"\bduration\x18\n" +
+	" \x01(\tR\bduration\x12 \n" +
+	"\vattemptedAt\x18\v \x01(\tR\vattemptedAt\x12)\n" +
+	"\rnextAttemptAt\x18\f \x01(\tH\x01R\rnextAttemptAt\x88\x01\x01B\b\n" +
+	"\x06_errorB\x10\n" +
+	"\x0e_nextAttemptAt\"Q\n" +
+	"\x11WebhookDeliveries\x12<\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1c.filehosting.WebhookDeliveryR\n" +
+	"deliveries*L\n" +
+	"\bFileSort\x12\x18\n" +
+	"\x14FILE_SORT_CREATED_AT\x10\x00\x12\x12\n" +
+	"\x0eFILE_SORT_NAME\x10\x01\x12\x12\n" +
+	"\x0eFILE_SORT_SIZE\x10\x022\x9c\r\n" +
 	"\vFileHosting\x12M\n" +
 	"\n" +
-	"UploadFile\x12\x1e.filehosting.UploadFileRequest\x1a\x1f.filehosting.UploadFileResponse\x121\n" +
-	"\aGetFile\x12\x13.filehosting.FileId\x1a\x11.filehosting.File\x12A\n" +
-	"\x0fGetFileMetadata\x12\x13.filehosting.FileId\x1a\x19.filehosting.FileMetadata\x126\n" +
-	"\bGetFiles\x12\x16.google.protobuf.Empty\x1a\x12.filehosting.Files\x12D\n" +
+	"UploadFile\x12\x1e.filehosting.UploadFileRequest\x1a\x1f.filehosting.UploadFileResponse\x12S\n" +
+	"\x10UploadFileStream\x12\x1c.filehosting.UploadFileChunk\x1a\x1f.filehosting.UploadFileResponse(\x01\x121\n" +
+	"\aGetFile\x12\x13.filehosting.FileId\x1a\x11.filehosting.File\x12>\n" +
+	"\rGetFileStream\x12\x13.filehosting.FileId\x1a\x16.filehosting.FileChunk0\x01\x12A\n" +
+	"\x0fGetFileMetadata\x12\x13.filehosting.FileId\x1a\x19.filehosting.FileMetadata\x12<\n" +
+	"\bGetFiles\x12\x1c.filehosting.GetFilesRequest\x1a\x12.filehosting.Files\x12D\n" +
 	"\n" +
-	"RenameFile\x12\x1e.filehosting.RenameFileRequest\x1a\x16.google.protobuf.Empty\x129\n" +
+	"RenameFile\x12\x1e.filehosting.RenameFileRequest\x1a\x16.google.protobuf.Empty\x12K\n" +
+	"\fUpdateExpiry\x12 .filehosting.UpdateExpiryRequest\x1a\x19.filehosting.FileMetadata\x12W\n" +
+	"\x12UpdateFileMetadata\x12&.filehosting.UpdateFileMetadataRequest\x1a\x19.filehosting.FileMetadata\x12J\n" +
+	"\vSignFileUrl\x12\x1f.filehosting.SignFileUrlRequest\x1a\x1a.filehosting.SignedFileUrl\x129\n" +
 	"\n" +
-	"DeleteFile\x12\x13.filehosting.FileId\x1a\x16.google.protobuf.EmptyB3Z1github.com/bruhabruh/file-hosting/pkg/filehostingb\x06proto3"
+	"DeleteFile\x12\x13.filehosting.FileId\x1a\x16.google.protobuf.Empty\x12D\n" +
+	"\x0fGetTrashedFiles\x12\x16.google.protobuf.Empty\x1a\x19.filehosting.TrashedFiles\x12K\n" +
+	"\x12RestoreTrashedFile\x12\x1a.filehosting.TrashedFileId\x1a\x19.filehosting.FileMetadata\x12A\n" +
+	"\x0fGetFileVersions\x12\x13.filehosting.FileId\x1a\x19.filehosting.FileVersions\x12?\n" +
+	"\x0eGetFileVersion\x12\x1a.filehosting.FileVersionId\x1a\x11.filehosting.File\x12K\n" +
+	"\x12RestoreFileVersion\x12\x1a.filehosting.FileVersionId\x1a\x19.filehosting.FileMetadata\x12G\n" +
+	"\x11DeleteFileVersion\x12\x1a.filehosting.FileVersionId\x1a\x16.google.protobuf.Empty\x12`\n" +
+	"\x15CreatePresignedUpload\x12).filehosting.CreatePresignedUploadRequest\x1a\x1c.filehosting.PresignedUpload\x12Z\n" +
+	"\x17FinalizePresignedUpload\x12\x1e.filehosting.PresignedUploadId\x1a\x1f.filehosting.UploadFileResponse\x12<\n" +
+	"\vGetWebhooks\x12\x16.google.protobuf.Empty\x1a\x15.filehosting.Webhooks\x12H\n" +
+	"\rCreateWebhook\x12!.filehosting.CreateWebhookRequest\x1a\x14.filehosting.Webhook\x12?\n" +
+	"\rDeleteWebhook\x12\x16.filehosting.WebhookId\x1a\x16.google.protobuf.Empty\x12N\n" +
+	"\x14GetWebhookDeliveries\x12\x16.filehosting.WebhookId\x1a\x1e.filehosting.WebhookDeliveriesB3Z1github.com/bruhabruh/file-hosting/pkg/filehostingb\x06proto3"
 
 var (
 	file_file_hosting_proto_rawDescOnce sync.Once
@@ -576,46 +2576,125 @@ func file_file_hosting_proto_rawDescGZIP() []byte {
 	return file_file_hosting_proto_rawDescData
 }
 
-var file_file_hosting_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_file_hosting_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_file_hosting_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_file_hosting_proto_goTypes = []any{
-	(*UploadFileRequest)(nil),  // 0: filehosting.UploadFileRequest
-	(*UploadFileResponse)(nil), // 1: filehosting.UploadFileResponse
-	(*FileId)(nil),             // 2: filehosting.FileId
-	(*File)(nil),               // 3: filehosting.File
-	(*FileMetadata)(nil),       // 4: filehosting.FileMetadata
-	(*MetadataValue)(nil),      // 5: filehosting.MetadataValue
-	(*Files)(nil),              // 6: filehosting.Files
-	(*RenameFileRequest)(nil),  // 7: filehosting.RenameFileRequest
-	nil,                        // 8: filehosting.UploadFileRequest.MetadataEntry
-	nil,                        // 9: filehosting.File.MetadataEntry
-	nil,                        // 10: filehosting.FileMetadata.MetaEntry
-	(*emptypb.Empty)(nil),      // 11: google.protobuf.Empty
+	(FileSort)(0),                        // 0: filehosting.FileSort
+	(*UploadFileRequest)(nil),            // 1: filehosting.UploadFileRequest
+	(*UploadFileInfo)(nil),               // 2: filehosting.UploadFileInfo
+	(*UploadFileChunk)(nil),              // 3: filehosting.UploadFileChunk
+	(*UploadFileResponse)(nil),           // 4: filehosting.UploadFileResponse
+	(*FileId)(nil),                       // 5: filehosting.FileId
+	(*File)(nil),                         // 6: filehosting.File
+	(*FileChunk)(nil),                    // 7: filehosting.FileChunk
+	(*FileMetadata)(nil),                 // 8: filehosting.FileMetadata
+	(*MetadataValue)(nil),                // 9: filehosting.MetadataValue
+	(*GetFilesRequest)(nil),              // 10: filehosting.GetFilesRequest
+	(*Files)(nil),                        // 11: filehosting.Files
+	(*RenameFileRequest)(nil),            // 12: filehosting.RenameFileRequest
+	(*UpdateExpiryRequest)(nil),          // 13: filehosting.UpdateExpiryRequest
+	(*UpdateFileMetadataRequest)(nil),    // 14: filehosting.UpdateFileMetadataRequest
+	(*SignFileUrlRequest)(nil),           // 15: filehosting.SignFileUrlRequest
+	(*SignedFileUrl)(nil),                // 16: filehosting.SignedFileUrl
+	(*TrashedFileId)(nil),                // 17: filehosting.TrashedFileId
+	(*TrashedFile)(nil),                  // 18: filehosting.TrashedFile
+	(*TrashedFiles)(nil),                 // 19: filehosting.TrashedFiles
+	(*FileVersionId)(nil),                // 20: filehosting.FileVersionId
+	(*FileVersion)(nil),                  // 21: filehosting.FileVersion
+	(*FileVersions)(nil),                 // 22: filehosting.FileVersions
+	(*CreatePresignedUploadRequest)(nil), // 23: filehosting.CreatePresignedUploadRequest
+	(*PresignedUpload)(nil),              // 24: filehosting.PresignedUpload
+	(*PresignedUploadId)(nil),            // 25: filehosting.PresignedUploadId
+	(*CreateWebhookRequest)(nil),         // 26: filehosting.CreateWebhookRequest
+	(*Webhook)(nil),                      // 27: filehosting.Webhook
+	(*Webhooks)(nil),                     // 28: filehosting.Webhooks
+	(*WebhookId)(nil),                    // 29: filehosting.WebhookId
+	(*WebhookDelivery)(nil),              // 30: filehosting.WebhookDelivery
+	(*WebhookDeliveries)(nil),            // 31: filehosting.WebhookDeliveries
+	nil,                                  // 32: filehosting.UploadFileRequest.MetadataEntry
+	nil,                                  // 33: filehosting.UploadFileInfo.MetadataEntry
+	nil,                                  // 34: filehosting.File.MetadataEntry
+	nil,                                  // 35: filehosting.FileMetadata.MetaEntry
+	nil,                                  // 36: filehosting.GetFilesRequest.MetaEntry
+	nil,                                  // 37: filehosting.UpdateFileMetadataRequest.MetadataEntry
+	nil,                                  // 38: filehosting.CreatePresignedUploadRequest.MetadataEntry
+	(*emptypb.Empty)(nil),                // 39: google.protobuf.Empty
 }
 var file_file_hosting_proto_depIdxs = []int32{
-	8,  // 0: filehosting.UploadFileRequest.metadata:type_name -> filehosting.UploadFileRequest.MetadataEntry
-	9,  // 1: filehosting.File.metadata:type_name -> filehosting.File.MetadataEntry
-	10, // 2: filehosting.FileMetadata.meta:type_name -> filehosting.FileMetadata.MetaEntry
-	4,  // 3: filehosting.Files.metadata:type_name -> filehosting.FileMetadata
-	5,  // 4: filehosting.UploadFileRequest.MetadataEntry.value:type_name -> filehosting.MetadataValue
-	5,  // 5: filehosting.File.MetadataEntry.value:type_name -> filehosting.MetadataValue
-	5,  // 6: filehosting.FileMetadata.MetaEntry.value:type_name -> filehosting.MetadataValue
-	0,  // 7: filehosting.FileHosting.UploadFile:input_type -> filehosting.UploadFileRequest
-	2,  // 8: filehosting.FileHosting.GetFile:input_type -> filehosting.FileId
-	2,  // 9: filehosting.FileHosting.GetFileMetadata:input_type -> filehosting.FileId
-	11, // 10: filehosting.FileHosting.GetFiles:input_type -> google.protobuf.Empty
-	7,  // 11: filehosting.FileHosting.RenameFile:input_type -> filehosting.RenameFileRequest
-	2,  // 12: filehosting.FileHosting.DeleteFile:input_type -> filehosting.FileId
-	1,  // 13: filehosting.FileHosting.UploadFile:output_type -> filehosting.UploadFileResponse
-	3,  // 14: filehosting.FileHosting.GetFile:output_type -> filehosting.File
-	4,  // 15: filehosting.FileHosting.GetFileMetadata:output_type -> filehosting.FileMetadata
-	6,  // 16: filehosting.FileHosting.GetFiles:output_type -> filehosting.Files
-	11, // 17: filehosting.FileHosting.RenameFile:output_type -> google.protobuf.Empty
-	11, // 18: filehosting.FileHosting.DeleteFile:output_type -> google.protobuf.Empty
-	13, // [13:19] is the sub-list for method output_type
-	7,  // [7:13] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	32, // 0: filehosting.UploadFileRequest.metadata:type_name -> filehosting.UploadFileRequest.MetadataEntry
+	33, // 1: filehosting.UploadFileInfo.metadata:type_name -> filehosting.UploadFileInfo.MetadataEntry
+	2,  // 2: filehosting.UploadFileChunk.info:type_name -> filehosting.UploadFileInfo
+	34, // 3: filehosting.File.metadata:type_name -> filehosting.File.MetadataEntry
+	8,  // 4: filehosting.FileChunk.metadata:type_name -> filehosting.FileMetadata
+	35, // 5: filehosting.FileMetadata.meta:type_name -> filehosting.FileMetadata.MetaEntry
+	36, // 6: filehosting.GetFilesRequest.meta:type_name -> filehosting.GetFilesRequest.MetaEntry
+	0,  // 7: filehosting.GetFilesRequest.sort:type_name -> filehosting.FileSort
+	8,  // 8: filehosting.Files.metadata:type_name -> filehosting.FileMetadata
+	37, // 9: filehosting.UpdateFileMetadataRequest.metadata:type_name -> filehosting.UpdateFileMetadataRequest.MetadataEntry
+	8,  // 10: filehosting.TrashedFile.metadata:type_name -> filehosting.FileMetadata
+	18, // 11: filehosting.TrashedFiles.files:type_name -> filehosting.TrashedFile
+	8,  // 12: filehosting.FileVersion.metadata:type_name -> filehosting.FileMetadata
+	21, // 13: filehosting.FileVersions.versions:type_name -> filehosting.FileVersion
+	38, // 14: filehosting.CreatePresignedUploadRequest.metadata:type_name -> filehosting.CreatePresignedUploadRequest.MetadataEntry
+	27, // 15: filehosting.Webhooks.webhooks:type_name -> filehosting.Webhook
+	30, // 16: filehosting.WebhookDeliveries.deliveries:type_name -> filehosting.WebhookDelivery
+	9,  // 17: filehosting.UploadFileRequest.MetadataEntry.value:type_name -> filehosting.MetadataValue
+	9,  // 18: filehosting.UploadFileInfo.MetadataEntry.value:type_name -> filehosting.MetadataValue
+	9,  // 19: filehosting.File.MetadataEntry.value:type_name -> filehosting.MetadataValue
+	9,  // 20: filehosting.FileMetadata.MetaEntry.value:type_name -> filehosting.MetadataValue
+	9,  // 21: filehosting.UpdateFileMetadataRequest.MetadataEntry.value:type_name -> filehosting.MetadataValue
+	9,  // 22: filehosting.CreatePresignedUploadRequest.MetadataEntry.value:type_name -> filehosting.MetadataValue
+	1,  // 23: filehosting.FileHosting.UploadFile:input_type -> filehosting.UploadFileRequest
+	3,  // 24: filehosting.FileHosting.UploadFileStream:input_type -> filehosting.UploadFileChunk
+	5,  // 25: filehosting.FileHosting.GetFile:input_type -> filehosting.FileId
+	5,  // 26: filehosting.FileHosting.GetFileStream:input_type -> filehosting.FileId
+	5,  // 27: filehosting.FileHosting.GetFileMetadata:input_type -> filehosting.FileId
+	10, // 28: filehosting.FileHosting.GetFiles:input_type -> filehosting.GetFilesRequest
+	12, // 29: filehosting.FileHosting.RenameFile:input_type -> filehosting.RenameFileRequest
+	13, // 30: filehosting.FileHosting.UpdateExpiry:input_type -> filehosting.UpdateExpiryRequest
+	14, // 31: filehosting.FileHosting.UpdateFileMetadata:input_type -> filehosting.UpdateFileMetadataRequest
+	15, // 32: filehosting.FileHosting.SignFileUrl:input_type -> filehosting.SignFileUrlRequest
+	5,  // 33: filehosting.FileHosting.DeleteFile:input_type -> filehosting.FileId
+	39, // 34: filehosting.FileHosting.GetTrashedFiles:input_type -> google.protobuf.Empty
+	17, // 35: filehosting.FileHosting.RestoreTrashedFile:input_type -> filehosting.TrashedFileId
+	5,  // 36: filehosting.FileHosting.GetFileVersions:input_type -> filehosting.FileId
+	20, // 37: filehosting.FileHosting.GetFileVersion:input_type -> filehosting.FileVersionId
+	20, // 38: filehosting.FileHosting.RestoreFileVersion:input_type -> filehosting.FileVersionId
+	20, // 39: filehosting.FileHosting.DeleteFileVersion:input_type -> filehosting.FileVersionId
+	23, // 40: filehosting.FileHosting.CreatePresignedUpload:input_type -> filehosting.CreatePresignedUploadRequest
+	25, // 41: filehosting.FileHosting.FinalizePresignedUpload:input_type -> filehosting.PresignedUploadId
+	39, // 42: filehosting.FileHosting.GetWebhooks:input_type -> google.protobuf.Empty
+	26, // 43: filehosting.FileHosting.CreateWebhook:input_type -> filehosting.CreateWebhookRequest
+	29, // 44: filehosting.FileHosting.DeleteWebhook:input_type -> filehosting.WebhookId
+	29, // 45: filehosting.FileHosting.GetWebhookDeliveries:input_type -> filehosting.WebhookId
+	4,  // 46: filehosting.FileHosting.UploadFile:output_type -> filehosting.UploadFileResponse
+	4,  // 47: filehosting.FileHosting.UploadFileStream:output_type -> filehosting.UploadFileResponse
+	6,  // 48: filehosting.FileHosting.GetFile:output_type -> filehosting.File
+	7,  // 49: filehosting.FileHosting.GetFileStream:output_type -> filehosting.FileChunk
+	8,  // 50: filehosting.FileHosting.GetFileMetadata:output_type -> filehosting.FileMetadata
+	11, // 51: filehosting.FileHosting.GetFiles:output_type -> filehosting.Files
+	39, // 52: filehosting.FileHosting.RenameFile:output_type -> google.protobuf.Empty
+	8,  // 53: filehosting.FileHosting.UpdateExpiry:output_type -> filehosting.FileMetadata
+	8,  // 54: filehosting.FileHosting.UpdateFileMetadata:output_type -> filehosting.FileMetadata
+	16, // 55: filehosting.FileHosting.SignFileUrl:output_type -> filehosting.SignedFileUrl
+	39, // 56: filehosting.FileHosting.DeleteFile:output_type -> google.protobuf.Empty
+	19, // 57: filehosting.FileHosting.GetTrashedFiles:output_type -> filehosting.TrashedFiles
+	8,  // 58: filehosting.FileHosting.RestoreTrashedFile:output_type -> filehosting.FileMetadata
+	22, // 59: filehosting.FileHosting.GetFileVersions:output_type -> filehosting.FileVersions
+	6,  // 60: filehosting.FileHosting.GetFileVersion:output_type -> filehosting.File
+	8,  // 61: filehosting.FileHosting.RestoreFileVersion:output_type -> filehosting.FileMetadata
+	39, // 62: filehosting.FileHosting.DeleteFileVersion:output_type -> google.protobuf.Empty
+	24, // 63: filehosting.FileHosting.CreatePresignedUpload:output_type -> filehosting.PresignedUpload
+	4,  // 64: filehosting.FileHosting.FinalizePresignedUpload:output_type -> filehosting.UploadFileResponse
+	28, // 65: filehosting.FileHosting.GetWebhooks:output_type -> filehosting.Webhooks
+	27, // 66: filehosting.FileHosting.CreateWebhook:output_type -> filehosting.Webhook
+	39, // 67: filehosting.FileHosting.DeleteWebhook:output_type -> google.protobuf.Empty
+	31, // 68: filehosting.FileHosting.GetWebhookDeliveries:output_type -> filehosting.WebhookDeliveries
+	46, // [46:69] is the sub-list for method output_type
+	23, // [23:46] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_file_hosting_proto_init() }
@@ -624,19 +2703,37 @@ func file_file_hosting_proto_init() {
 		return
 	}
 	file_file_hosting_proto_msgTypes[0].OneofWrappers = []any{}
-	file_file_hosting_proto_msgTypes[4].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[1].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[2].OneofWrappers = []any{
+		(*UploadFileChunk_Info)(nil),
+		(*UploadFileChunk_Chunk)(nil),
+	}
+	file_file_hosting_proto_msgTypes[3].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[6].OneofWrappers = []any{
+		(*FileChunk_Metadata)(nil),
+		(*FileChunk_Chunk)(nil),
+	}
+	file_file_hosting_proto_msgTypes[7].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[9].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[12].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[14].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[22].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[25].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[26].OneofWrappers = []any{}
+	file_file_hosting_proto_msgTypes[29].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_file_hosting_proto_rawDesc), len(file_file_hosting_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      1,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_file_hosting_proto_goTypes,
 		DependencyIndexes: file_file_hosting_proto_depIdxs,
+		EnumInfos:         file_file_hosting_proto_enumTypes,
 		MessageInfos:      file_file_hosting_proto_msgTypes,
 	}.Build()
 	File_file_hosting_proto = out.File
@@ -20,12 +20,29 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileHosting_UploadFile_FullMethodName      = "/filehosting.FileHosting/UploadFile"
-	FileHosting_GetFile_FullMethodName         = "/filehosting.FileHosting/GetFile"
-	FileHosting_GetFileMetadata_FullMethodName = "/filehosting.FileHosting/GetFileMetadata"
-	FileHosting_GetFiles_FullMethodName        = "/filehosting.FileHosting/GetFiles"
-	FileHosting_RenameFile_FullMethodName      = "/filehosting.FileHosting/RenameFile"
-	FileHosting_DeleteFile_FullMethodName      = "/filehosting.FileHosting/DeleteFile"
+	FileHosting_UploadFile_FullMethodName              = "/filehosting.FileHosting/UploadFile"
+	FileHosting_UploadFileStream_FullMethodName        = "/filehosting.FileHosting/UploadFileStream"
+	FileHosting_GetFile_FullMethodName                 = "/filehosting.FileHosting/GetFile"
+	FileHosting_GetFileStream_FullMethodName           = "/filehosting.FileHosting/GetFileStream"
+	FileHosting_GetFileMetadata_FullMethodName         = "/filehosting.FileHosting/GetFileMetadata"
+	FileHosting_GetFiles_FullMethodName                = "/filehosting.FileHosting/GetFiles"
+	FileHosting_RenameFile_FullMethodName              = "/filehosting.FileHosting/RenameFile"
+	FileHosting_UpdateExpiry_FullMethodName            = "/filehosting.FileHosting/UpdateExpiry"
+	FileHosting_UpdateFileMetadata_FullMethodName      = "/filehosting.FileHosting/UpdateFileMetadata"
+	FileHosting_SignFileUrl_FullMethodName             = "/filehosting.FileHosting/SignFileUrl"
+	FileHosting_DeleteFile_FullMethodName              = "/filehosting.FileHosting/DeleteFile"
+	FileHosting_GetTrashedFiles_FullMethodName         = "/filehosting.FileHosting/GetTrashedFiles"
+	FileHosting_RestoreTrashedFile_FullMethodName      = "/filehosting.FileHosting/RestoreTrashedFile"
+	FileHosting_GetFileVersions_FullMethodName         = "/filehosting.FileHosting/GetFileVersions"
+	FileHosting_GetFileVersion_FullMethodName          = "/filehosting.FileHosting/GetFileVersion"
+	FileHosting_RestoreFileVersion_FullMethodName      = "/filehosting.FileHosting/RestoreFileVersion"
+	FileHosting_DeleteFileVersion_FullMethodName       = "/filehosting.FileHosting/DeleteFileVersion"
+	FileHosting_CreatePresignedUpload_FullMethodName   = "/filehosting.FileHosting/CreatePresignedUpload"
+	FileHosting_FinalizePresignedUpload_FullMethodName = "/filehosting.FileHosting/FinalizePresignedUpload"
+	FileHosting_GetWebhooks_FullMethodName             = "/filehosting.FileHosting/GetWebhooks"
+	FileHosting_CreateWebhook_FullMethodName           = "/filehosting.FileHosting/CreateWebhook"
+	FileHosting_DeleteWebhook_FullMethodName           = "/filehosting.FileHosting/DeleteWebhook"
+	FileHosting_GetWebhookDeliveries_FullMethodName    = "/filehosting.FileHosting/GetWebhookDeliveries"
 )
 
 // FileHostingClient is the client API for FileHosting service.
@@ -33,11 +50,59 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type FileHostingClient interface {
 	UploadFile(ctx context.Context, in *UploadFileRequest, opts ...grpc.CallOption) (*UploadFileResponse, error)
+	// UploadFileStream uploads a file of any size. The first message must carry
+	// the info, all following messages carry the content chunks.
+	UploadFileStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse], error)
+	// GetFile and GetFileStream count a download of files with maxDownloads.
+	// GetFile, GetFileStream and GetFileMetadata of a file with a password read
+	// the password from the x-file-password metadata.
 	GetFile(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*File, error)
+	// GetFileStream downloads a file of any size. The first message carries the
+	// metadata, all following messages carry the content chunks.
+	GetFileStream(ctx context.Context, in *FileId, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
 	GetFileMetadata(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*FileMetadata, error)
-	GetFiles(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Files, error)
+	// GetFiles returns a page of files. GetFilesRequest without fields is
+	// compatible with google.protobuf.Empty and returns the first page.
+	GetFiles(ctx context.Context, in *GetFilesRequest, opts ...grpc.CallOption) (*Files, error)
 	RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// UpdateExpiry sets a new expiry of a file, counted from now, without
+	// uploading it again.
+	UpdateExpiry(ctx context.Context, in *UpdateExpiryRequest, opts ...grpc.CallOption) (*FileMetadata, error)
+	// UpdateFileMetadata replaces the custom metadata of a file.
+	UpdateFileMetadata(ctx context.Context, in *UpdateFileMetadataRequest, opts ...grpc.CallOption) (*FileMetadata, error)
+	// SignFileUrl returns a URL reading a private file without an API key
+	// until it expires.
+	SignFileUrl(ctx context.Context, in *SignFileUrlRequest, opts ...grpc.CallOption) (*SignedFileUrl, error)
+	// DeleteFile deletes the current version of a file, previous versions are
+	// kept and can be restored. With the trash enabled the file is moved to the
+	// trash, like files deleted on expiration.
 	DeleteFile(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetTrashedFiles lists files in the trash, recently deleted first.
+	GetTrashedFiles(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TrashedFiles, error)
+	// RestoreTrashedFile moves a trashed file back under its id. Expired files
+	// are restored with the default duration.
+	RestoreTrashedFile(ctx context.Context, in *TrashedFileId, opts ...grpc.CallOption) (*FileMetadata, error)
+	// GetFileVersions lists the versions of a file newest first, starting with
+	// the current one while the file exists.
+	GetFileVersions(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*FileVersions, error)
+	GetFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (*File, error)
+	// RestoreFileVersion uploads the content of a version as the current
+	// version, the replaced one becomes a previous version.
+	RestoreFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (*FileMetadata, error)
+	DeleteFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// CreatePresignedUpload returns a URL to upload a file directly to the
+	// storage by a PUT request. The file is hosted after FinalizePresignedUpload.
+	CreatePresignedUpload(ctx context.Context, in *CreatePresignedUploadRequest, opts ...grpc.CallOption) (*PresignedUpload, error)
+	FinalizePresignedUpload(ctx context.Context, in *PresignedUploadId, opts ...grpc.CallOption) (*UploadFileResponse, error)
+	// GetWebhooks lists the webhook subscriptions without their secrets.
+	GetWebhooks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Webhooks, error)
+	// CreateWebhook subscribes a URL to events of files. The secret is
+	// generated when it is not given and is returned only here.
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, in *WebhookId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetWebhookDeliveries lists the latest deliveries to a webhook, newest
+	// first.
+	GetWebhookDeliveries(ctx context.Context, in *WebhookId, opts ...grpc.CallOption) (*WebhookDeliveries, error)
 }
 
 type fileHostingClient struct {
@@ -58,6 +123,19 @@ func (c *fileHostingClient) UploadFile(ctx context.Context, in *UploadFileReques
 	return out, nil
 }
 
+func (c *fileHostingClient) UploadFileStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileHosting_ServiceDesc.Streams[0], FileHosting_UploadFileStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadFileChunk, UploadFileResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileHosting_UploadFileStreamClient = grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse]
+
 func (c *fileHostingClient) GetFile(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*File, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(File)
@@ -68,6 +146,25 @@ func (c *fileHostingClient) GetFile(ctx context.Context, in *FileId, opts ...grp
 	return out, nil
 }
 
+func (c *fileHostingClient) GetFileStream(ctx context.Context, in *FileId, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileHosting_ServiceDesc.Streams[1], FileHosting_GetFileStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FileId, FileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileHosting_GetFileStreamClient = grpc.ServerStreamingClient[FileChunk]
+
 func (c *fileHostingClient) GetFileMetadata(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*FileMetadata, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FileMetadata)
@@ -78,7 +175,7 @@ func (c *fileHostingClient) GetFileMetadata(ctx context.Context, in *FileId, opt
 	return out, nil
 }
 
-func (c *fileHostingClient) GetFiles(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Files, error) {
+func (c *fileHostingClient) GetFiles(ctx context.Context, in *GetFilesRequest, opts ...grpc.CallOption) (*Files, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Files)
 	err := c.cc.Invoke(ctx, FileHosting_GetFiles_FullMethodName, in, out, cOpts...)
@@ -98,6 +195,36 @@ func (c *fileHostingClient) RenameFile(ctx context.Context, in *RenameFileReques
 	return out, nil
 }
 
+func (c *fileHostingClient) UpdateExpiry(ctx context.Context, in *UpdateExpiryRequest, opts ...grpc.CallOption) (*FileMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileMetadata)
+	err := c.cc.Invoke(ctx, FileHosting_UpdateExpiry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) UpdateFileMetadata(ctx context.Context, in *UpdateFileMetadataRequest, opts ...grpc.CallOption) (*FileMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileMetadata)
+	err := c.cc.Invoke(ctx, FileHosting_UpdateFileMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) SignFileUrl(ctx context.Context, in *SignFileUrlRequest, opts ...grpc.CallOption) (*SignedFileUrl, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignedFileUrl)
+	err := c.cc.Invoke(ctx, FileHosting_SignFileUrl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fileHostingClient) DeleteFile(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -108,16 +235,184 @@ func (c *fileHostingClient) DeleteFile(ctx context.Context, in *FileId, opts ...
 	return out, nil
 }
 
+func (c *fileHostingClient) GetTrashedFiles(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TrashedFiles, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrashedFiles)
+	err := c.cc.Invoke(ctx, FileHosting_GetTrashedFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) RestoreTrashedFile(ctx context.Context, in *TrashedFileId, opts ...grpc.CallOption) (*FileMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileMetadata)
+	err := c.cc.Invoke(ctx, FileHosting_RestoreTrashedFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) GetFileVersions(ctx context.Context, in *FileId, opts ...grpc.CallOption) (*FileVersions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileVersions)
+	err := c.cc.Invoke(ctx, FileHosting_GetFileVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) GetFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (*File, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(File)
+	err := c.cc.Invoke(ctx, FileHosting_GetFileVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) RestoreFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (*FileMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileMetadata)
+	err := c.cc.Invoke(ctx, FileHosting_RestoreFileVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) DeleteFileVersion(ctx context.Context, in *FileVersionId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FileHosting_DeleteFileVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) CreatePresignedUpload(ctx context.Context, in *CreatePresignedUploadRequest, opts ...grpc.CallOption) (*PresignedUpload, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PresignedUpload)
+	err := c.cc.Invoke(ctx, FileHosting_CreatePresignedUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) FinalizePresignedUpload(ctx context.Context, in *PresignedUploadId, opts ...grpc.CallOption) (*UploadFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadFileResponse)
+	err := c.cc.Invoke(ctx, FileHosting_FinalizePresignedUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) GetWebhooks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Webhooks, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhooks)
+	err := c.cc.Invoke(ctx, FileHosting_GetWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, FileHosting_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) DeleteWebhook(ctx context.Context, in *WebhookId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FileHosting_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileHostingClient) GetWebhookDeliveries(ctx context.Context, in *WebhookId, opts ...grpc.CallOption) (*WebhookDeliveries, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookDeliveries)
+	err := c.cc.Invoke(ctx, FileHosting_GetWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileHostingServer is the server API for FileHosting service.
 // All implementations must embed UnimplementedFileHostingServer
 // for forward compatibility.
 type FileHostingServer interface {
 	UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error)
+	// UploadFileStream uploads a file of any size. The first message must carry
+	// the info, all following messages carry the content chunks.
+	UploadFileStream(grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]) error
+	// GetFile and GetFileStream count a download of files with maxDownloads.
+	// GetFile, GetFileStream and GetFileMetadata of a file with a password read
+	// the password from the x-file-password metadata.
 	GetFile(context.Context, *FileId) (*File, error)
+	// GetFileStream downloads a file of any size. The first message carries the
+	// metadata, all following messages carry the content chunks.
+	GetFileStream(*FileId, grpc.ServerStreamingServer[FileChunk]) error
 	GetFileMetadata(context.Context, *FileId) (*FileMetadata, error)
-	GetFiles(context.Context, *emptypb.Empty) (*Files, error)
+	// GetFiles returns a page of files. GetFilesRequest without fields is
+	// compatible with google.protobuf.Empty and returns the first page.
+	GetFiles(context.Context, *GetFilesRequest) (*Files, error)
 	RenameFile(context.Context, *RenameFileRequest) (*emptypb.Empty, error)
+	// UpdateExpiry sets a new expiry of a file, counted from now, without
+	// uploading it again.
+	UpdateExpiry(context.Context, *UpdateExpiryRequest) (*FileMetadata, error)
+	// UpdateFileMetadata replaces the custom metadata of a file.
+	UpdateFileMetadata(context.Context, *UpdateFileMetadataRequest) (*FileMetadata, error)
+	// SignFileUrl returns a URL reading a private file without an API key
+	// until it expires.
+	SignFileUrl(context.Context, *SignFileUrlRequest) (*SignedFileUrl, error)
+	// DeleteFile deletes the current version of a file, previous versions are
+	// kept and can be restored. With the trash enabled the file is moved to the
+	// trash, like files deleted on expiration.
 	DeleteFile(context.Context, *FileId) (*emptypb.Empty, error)
+	// GetTrashedFiles lists files in the trash, recently deleted first.
+	GetTrashedFiles(context.Context, *emptypb.Empty) (*TrashedFiles, error)
+	// RestoreTrashedFile moves a trashed file back under its id. Expired files
+	// are restored with the default duration.
+	RestoreTrashedFile(context.Context, *TrashedFileId) (*FileMetadata, error)
+	// GetFileVersions lists the versions of a file newest first, starting with
+	// the current one while the file exists.
+	GetFileVersions(context.Context, *FileId) (*FileVersions, error)
+	GetFileVersion(context.Context, *FileVersionId) (*File, error)
+	// RestoreFileVersion uploads the content of a version as the current
+	// version, the replaced one becomes a previous version.
+	RestoreFileVersion(context.Context, *FileVersionId) (*FileMetadata, error)
+	DeleteFileVersion(context.Context, *FileVersionId) (*emptypb.Empty, error)
+	// CreatePresignedUpload returns a URL to upload a file directly to the
+	// storage by a PUT request. The file is hosted after FinalizePresignedUpload.
+	CreatePresignedUpload(context.Context, *CreatePresignedUploadRequest) (*PresignedUpload, error)
+	FinalizePresignedUpload(context.Context, *PresignedUploadId) (*UploadFileResponse, error)
+	// GetWebhooks lists the webhook subscriptions without their secrets.
+	GetWebhooks(context.Context, *emptypb.Empty) (*Webhooks, error)
+	// CreateWebhook subscribes a URL to events of files. The secret is
+	// generated when it is not given and is returned only here.
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error)
+	DeleteWebhook(context.Context, *WebhookId) (*emptypb.Empty, error)
+	// GetWebhookDeliveries lists the latest deliveries to a webhook, newest
+	// first.
+	GetWebhookDeliveries(context.Context, *WebhookId) (*WebhookDeliveries, error)
 	mustEmbedUnimplementedFileHostingServer()
 }
 
@@ -131,21 +426,72 @@ type UnimplementedFileHostingServer struct{}
 func (UnimplementedFileHostingServer) UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
 }
+func (UnimplementedFileHostingServer) UploadFileStream(grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFileStream not implemented")
+}
 func (UnimplementedFileHostingServer) GetFile(context.Context, *FileId) (*File, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFile not implemented")
 }
+func (UnimplementedFileHostingServer) GetFileStream(*FileId, grpc.ServerStreamingServer[FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method GetFileStream not implemented")
+}
 func (UnimplementedFileHostingServer) GetFileMetadata(context.Context, *FileId) (*FileMetadata, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFileMetadata not implemented")
 }
-func (UnimplementedFileHostingServer) GetFiles(context.Context, *emptypb.Empty) (*Files, error) {
+func (UnimplementedFileHostingServer) GetFiles(context.Context, *GetFilesRequest) (*Files, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFiles not implemented")
 }
 func (UnimplementedFileHostingServer) RenameFile(context.Context, *RenameFileRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenameFile not implemented")
 }
+func (UnimplementedFileHostingServer) UpdateExpiry(context.Context, *UpdateExpiryRequest) (*FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateExpiry not implemented")
+}
+func (UnimplementedFileHostingServer) UpdateFileMetadata(context.Context, *UpdateFileMetadataRequest) (*FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFileMetadata not implemented")
+}
+func (UnimplementedFileHostingServer) SignFileUrl(context.Context, *SignFileUrlRequest) (*SignedFileUrl, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignFileUrl not implemented")
+}
 func (UnimplementedFileHostingServer) DeleteFile(context.Context, *FileId) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
 }
+func (UnimplementedFileHostingServer) GetTrashedFiles(context.Context, *emptypb.Empty) (*TrashedFiles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrashedFiles not implemented")
+}
+func (UnimplementedFileHostingServer) RestoreTrashedFile(context.Context, *TrashedFileId) (*FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreTrashedFile not implemented")
+}
+func (UnimplementedFileHostingServer) GetFileVersions(context.Context, *FileId) (*FileVersions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileVersions not implemented")
+}
+func (UnimplementedFileHostingServer) GetFileVersion(context.Context, *FileVersionId) (*File, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileVersion not implemented")
+}
+func (UnimplementedFileHostingServer) RestoreFileVersion(context.Context, *FileVersionId) (*FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFileVersion not implemented")
+}
+func (UnimplementedFileHostingServer) DeleteFileVersion(context.Context, *FileVersionId) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFileVersion not implemented")
+}
+func (UnimplementedFileHostingServer) CreatePresignedUpload(context.Context, *CreatePresignedUploadRequest) (*PresignedUpload, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePresignedUpload not implemented")
+}
+func (UnimplementedFileHostingServer) FinalizePresignedUpload(context.Context, *PresignedUploadId) (*UploadFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinalizePresignedUpload not implemented")
+}
+func (UnimplementedFileHostingServer) GetWebhooks(context.Context, *emptypb.Empty) (*Webhooks, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhooks not implemented")
+}
+func (UnimplementedFileHostingServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedFileHostingServer) DeleteWebhook(context.Context, *WebhookId) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedFileHostingServer) GetWebhookDeliveries(context.Context, *WebhookId) (*WebhookDeliveries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDeliveries not implemented")
+}
 func (UnimplementedFileHostingServer) mustEmbedUnimplementedFileHostingServer() {}
 func (UnimplementedFileHostingServer) testEmbeddedByValue()                     {}
 
@@ -185,6 +531,13 @@ func _FileHosting_UploadFile_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileHosting_UploadFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileHostingServer).UploadFileStream(&grpc.GenericServerStream[UploadFileChunk, UploadFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileHosting_UploadFileStreamServer = grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]
+
 func _FileHosting_GetFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FileId)
 	if err := dec(in); err != nil {
@@ -203,6 +556,17 @@ func _FileHosting_GetFile_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileHosting_GetFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileId)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileHostingServer).GetFileStream(m, &grpc.GenericServerStream[FileId, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileHosting_GetFileStreamServer = grpc.ServerStreamingServer[FileChunk]
+
 func _FileHosting_GetFileMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FileId)
 	if err := dec(in); err != nil {
@@ -222,7 +586,7 @@ func _FileHosting_GetFileMetadata_Handler(srv interface{}, ctx context.Context,
 }
 
 func _FileHosting_GetFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(GetFilesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -234,7 +598,7 @@ func _FileHosting_GetFiles_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: FileHosting_GetFiles_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileHostingServer).GetFiles(ctx, req.(*emptypb.Empty))
+		return srv.(FileHostingServer).GetFiles(ctx, req.(*GetFilesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -257,6 +621,60 @@ func _FileHosting_RenameFile_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileHosting_UpdateExpiry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateExpiryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).UpdateExpiry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_UpdateExpiry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).UpdateExpiry(ctx, req.(*UpdateExpiryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_UpdateFileMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFileMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).UpdateFileMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_UpdateFileMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).UpdateFileMetadata(ctx, req.(*UpdateFileMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_SignFileUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignFileUrlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).SignFileUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_SignFileUrl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).SignFileUrl(ctx, req.(*SignFileUrlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FileHosting_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FileId)
 	if err := dec(in); err != nil {
@@ -275,6 +693,222 @@ func _FileHosting_DeleteFile_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileHosting_GetTrashedFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).GetTrashedFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_GetTrashedFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).GetTrashedFiles(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_RestoreTrashedFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrashedFileId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).RestoreTrashedFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_RestoreTrashedFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).RestoreTrashedFile(ctx, req.(*TrashedFileId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_GetFileVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).GetFileVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_GetFileVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).GetFileVersions(ctx, req.(*FileId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_GetFileVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileVersionId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).GetFileVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_GetFileVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).GetFileVersion(ctx, req.(*FileVersionId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_RestoreFileVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileVersionId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).RestoreFileVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_RestoreFileVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).RestoreFileVersion(ctx, req.(*FileVersionId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_DeleteFileVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileVersionId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).DeleteFileVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_DeleteFileVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).DeleteFileVersion(ctx, req.(*FileVersionId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_CreatePresignedUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePresignedUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).CreatePresignedUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_CreatePresignedUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).CreatePresignedUpload(ctx, req.(*CreatePresignedUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_FinalizePresignedUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PresignedUploadId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).FinalizePresignedUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_FinalizePresignedUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).FinalizePresignedUpload(ctx, req.(*PresignedUploadId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_GetWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).GetWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_GetWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).GetWebhooks(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).DeleteWebhook(ctx, req.(*WebhookId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileHosting_GetWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileHostingServer).GetWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileHosting_GetWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileHostingServer).GetWebhookDeliveries(ctx, req.(*WebhookId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FileHosting_ServiceDesc is the grpc.ServiceDesc for FileHosting service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -302,11 +936,82 @@ var FileHosting_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RenameFile",
 			Handler:    _FileHosting_RenameFile_Handler,
 		},
+		{
+			MethodName: "UpdateExpiry",
+			Handler:    _FileHosting_UpdateExpiry_Handler,
+		},
+		{
+			MethodName: "UpdateFileMetadata",
+			Handler:    _FileHosting_UpdateFileMetadata_Handler,
+		},
+		{
+			MethodName: "SignFileUrl",
+			Handler:    _FileHosting_SignFileUrl_Handler,
+		},
 		{
 			MethodName: "DeleteFile",
 			Handler:    _FileHosting_DeleteFile_Handler,
 		},
+		{
+			MethodName: "GetTrashedFiles",
+			Handler:    _FileHosting_GetTrashedFiles_Handler,
+		},
+		{
+			MethodName: "RestoreTrashedFile",
+			Handler:    _FileHosting_RestoreTrashedFile_Handler,
+		},
+		{
+			MethodName: "GetFileVersions",
+			Handler:    _FileHosting_GetFileVersions_Handler,
+		},
+		{
+			MethodName: "GetFileVersion",
+			Handler:    _FileHosting_GetFileVersion_Handler,
+		},
+		{
+			MethodName: "RestoreFileVersion",
+			Handler:    _FileHosting_RestoreFileVersion_Handler,
+		},
+		{
+			MethodName: "DeleteFileVersion",
+			Handler:    _FileHosting_DeleteFileVersion_Handler,
+		},
+		{
+			MethodName: "CreatePresignedUpload",
+			Handler:    _FileHosting_CreatePresignedUpload_Handler,
+		},
+		{
+			MethodName: "FinalizePresignedUpload",
+			Handler:    _FileHosting_FinalizePresignedUpload_Handler,
+		},
+		{
+			MethodName: "GetWebhooks",
+			Handler:    _FileHosting_GetWebhooks_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _FileHosting_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _FileHosting_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "GetWebhookDeliveries",
+			Handler:    _FileHosting_GetWebhookDeliveries_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadFileStream",
+			Handler:       _FileHosting_UploadFileStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetFileStream",
+			Handler:       _FileHosting_GetFileStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "file-hosting.proto",
 }
@@ -2,7 +2,6 @@ package grpcinterceptors
 
 import (
 	"context"
-	"fmt"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -10,7 +9,11 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func UnaryServerAuthorizationInterceptor(apiKey string) grpc.UnaryServerInterceptor {
+// AuthorizeFunc authorizes a call of fullMethod with the authorization
+// metadata and returns the context the call continues with.
+type AuthorizeFunc func(ctx context.Context, fullMethod string, authorization string) (context.Context, error)
+
+func UnaryServerAuthorizationInterceptor(authorize AuthorizeFunc) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req any,
@@ -24,13 +27,42 @@ func UnaryServerAuthorizationInterceptor(apiKey string) grpc.UnaryServerIntercep
 		}
 
 		// Получаем Authorization header
-		auth := extractMetadata(md, "authorization")
-		expectedAuth := fmt.Sprintf("Bearer %s", apiKey)
-
-		if auth != expectedAuth {
-			return nil, status.Error(codes.Unauthenticated, "invalid or missing authorization")
+		ctx, err := authorize(ctx, info.FullMethod, extractMetadata(md, "authorization"))
+		if err != nil {
+			return nil, err
 		}
 
 		return handler(ctx, req)
 	}
 }
+
+func StreamServerAuthorizationInterceptor(authorize AuthorizeFunc) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		md, ok := metadata.FromIncomingContext(ss.Context())
+		if !ok {
+			return status.Error(codes.Unauthenticated, "missing metadata")
+		}
+
+		ctx, err := authorize(ss.Context(), info.FullMethod, extractMetadata(md, "authorization"))
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream replaces the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
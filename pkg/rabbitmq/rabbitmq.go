@@ -2,75 +2,294 @@ package rabbitmq
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/bruhabruh/file-hosting/pkg/logging"
 	"github.com/streadway/amqp"
 )
 
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+	confirmTimeout    = 5 * time.Second
+)
+
+var ErrNotConnected = errors.New("rabbitmq is not connected")
+
+// RabbitMQ keeps a connection with a channel in confirm mode. After the
+// connection is lost it reconnects with exponential backoff, declares the
+// queues again and restarts the consumers.
 type RabbitMQ struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
+	url        string
+	mu         sync.RWMutex
+	conn       *amqp.Connection
+	channel    *amqp.Channel
+	confirms   *confirms
+	publishMu  sync.Mutex
+	topology   []func(ch *amqp.Channel) error
+	consumers  []*consumer
+	connected  atomic.Bool
+	reconnects atomic.Uint64
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+// confirms receives the publisher confirms of a channel.
+type confirms struct {
+	ch chan amqp.Confirmation
+	// published is the delivery tag of the last message published on the
+	// channel, guarded by publishMu.
+	published uint64
+}
+
+type consumer struct {
+	ctx       context.Context
+	queueName string
+	handler   func(amqp.Delivery)
 }
 
 func NewRabbitMQ(url string) (*RabbitMQ, error) {
-	conn, err := amqp.Dial(url)
-	if err != nil {
+	r := &RabbitMQ{
+		url:    url,
+		closed: make(chan struct{}),
+	}
+
+	if err := r.connect(); err != nil {
 		return nil, err
 	}
 
+	return r, nil
+}
+
+func (r *RabbitMQ) connect() error {
+	conn, err := amqp.Dial(r.url)
+	if err != nil {
+		return err
+	}
+
 	ch, err := conn.Channel()
 	if err != nil {
-		return nil, err
+		conn.Close()
+		return err
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, declare := range r.topology {
+		if err := declare(ch); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	r.conn = conn
+	r.channel = ch
+	r.confirms = &confirms{ch: ch.NotifyPublish(make(chan amqp.Confirmation, 1))}
+	r.connected.Store(true)
+
+	for _, c := range r.consumers {
+		if c.ctx.Err() != nil {
+			continue
+		}
+		if err := r.startConsumer(c); err != nil {
+			r.connected.Store(false)
+			conn.Close()
+			return err
+		}
 	}
 
-	return &RabbitMQ{
-		conn:    conn,
-		channel: ch,
-	}, nil
+	go r.watch(conn, conn.NotifyClose(make(chan *amqp.Error, 1)), ch.NotifyClose(make(chan *amqp.Error, 1)))
+
+	return nil
 }
 
-func (r *RabbitMQ) DeclareQueue(queueName string) error {
-	_, err := r.channel.QueueDeclare(
-		queueName,
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
-	if err != nil {
+// watch reconnects after the connection or the channel is closed by the
+// broker or the network.
+func (r *RabbitMQ) watch(conn *amqp.Connection, connClosed chan *amqp.Error, channelClosed chan *amqp.Error) {
+	var err *amqp.Error
+	select {
+	case err = <-connClosed:
+	case err = <-channelClosed:
+	}
+	if err == nil {
+		// Closed by Close
+		return
+	}
+
+	r.connected.Store(false)
+	conn.Close()
+	logging.Default().Error("RabbitMQ connection lost", logging.ErrAttr(err))
+
+	delay := minReconnectDelay
+	for {
+		select {
+		case <-r.closed:
+			return
+		case <-time.After(delay):
+		}
+
+		if err := r.connect(); err != nil {
+			logging.Default().Error("Fail reconnect to RabbitMQ", logging.ErrAttr(err), logging.DurationAttr("retry_in", delay))
+			delay = min(delay*2, maxReconnectDelay)
+			continue
+		}
+
+		r.reconnects.Add(1)
+		logging.Default().Info("RabbitMQ reconnected")
+		return
+	}
+}
+
+// Connected tells whether the connection is open.
+func (r *RabbitMQ) Connected() bool {
+	return r.connected.Load()
+}
+
+// Reconnects is how many times the connection was restored.
+func (r *RabbitMQ) Reconnects() uint64 {
+	return r.reconnects.Load()
+}
+
+// Health returns ErrNotConnected while the connection is lost.
+func (r *RabbitMQ) Health() error {
+	if !r.Connected() {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+func (r *RabbitMQ) currentChannel() (*amqp.Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.Connected() {
+		return nil, ErrNotConnected
+	}
+	return r.channel, nil
+}
+
+// declare runs declare now and again after each reconnect.
+func (r *RabbitMQ) declare(declare func(ch *amqp.Channel) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.Connected() {
+		return ErrNotConnected
+	}
+	if err := declare(r.channel); err != nil {
 		return err
 	}
+	r.topology = append(r.topology, declare)
 
 	return nil
 }
 
+func (r *RabbitMQ) DeclareQueue(queueName string) error {
+	return r.declare(func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclare(
+			queueName,
+			true,
+			false,
+			false,
+			false,
+			nil,
+		)
+		return err
+	})
+}
+
 func (r *RabbitMQ) Publish(queueName string, message []byte, contentTypes ...string) error {
 	contentType := "text/plain"
 	if len(contentTypes) > 0 {
 		contentType = contentTypes[0]
 	}
 
-	err := r.channel.Publish(
+	return r.publish(queueName, amqp.Publishing{
+		ContentType:  contentType,
+		Body:         message,
+		DeliveryMode: 2,
+	})
+}
+
+// publish returns after the broker confirms the message is persisted.
+func (r *RabbitMQ) publish(queueName string, msg amqp.Publishing) error {
+	r.publishMu.Lock()
+	defer r.publishMu.Unlock()
+
+	r.mu.RLock()
+	ch, confirms := r.channel, r.confirms
+	r.mu.RUnlock()
+	if !r.Connected() {
+		return ErrNotConnected
+	}
+
+	err := ch.Publish(
 		"",
 		queueName,
 		false,
 		false,
-		amqp.Publishing{
-			ContentType:  contentType,
-			Body:         message,
-			DeliveryMode: 2,
-		},
+		msg,
 	)
 	if err != nil {
 		return err
 	}
+	confirms.published++
+	tag := confirms.published
 
-	return nil
+	timeout := time.After(confirmTimeout)
+	for {
+		select {
+		case confirm, ok := <-confirms.ch:
+			if !ok {
+				return ErrNotConnected
+			}
+			if confirm.DeliveryTag < tag {
+				// Late confirm of a message which timed out
+				continue
+			}
+			if !confirm.Ack {
+				return fmt.Errorf("message to %s is not confirmed", queueName)
+			}
+			return nil
+		case <-timeout:
+			return fmt.Errorf("message to %s is not confirmed in %s", queueName, confirmTimeout)
+		}
+	}
 }
 
+// Consume passes messages of queueName to handler until ctx is done. The
+// consumer is restarted after a reconnect.
 func (r *RabbitMQ) Consume(ctx context.Context, queueName string, handler func(amqp.Delivery)) error {
+	c := &consumer{ctx: ctx, queueName: queueName, handler: handler}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.Connected() {
+		return ErrNotConnected
+	}
+	if err := r.startConsumer(c); err != nil {
+		return err
+	}
+	r.consumers = append(r.consumers, c)
+
+	return nil
+}
+
+// startConsumer must be called with mu held.
+func (r *RabbitMQ) startConsumer(c *consumer) error {
 	msgs, err := r.channel.Consume(
-		queueName,
+		c.queueName,
 		"",
 		false,
 		false,
@@ -85,10 +304,14 @@ func (r *RabbitMQ) Consume(ctx context.Context, queueName string, handler func(a
 	go func() {
 		for {
 			select {
-			case <-ctx.Done():
+			case <-c.ctx.Done():
 				return
-			case msg := <-msgs:
-				handler(msg)
+			case msg, ok := <-msgs:
+				if !ok {
+					// The channel is closed, a reconnect starts a new consumer
+					return
+				}
+				c.handler(msg)
 			}
 		}
 	}()
@@ -97,6 +320,14 @@ func (r *RabbitMQ) Consume(ctx context.Context, queueName string, handler func(a
 }
 
 func (r *RabbitMQ) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.connected.Store(false)
 	if err := r.channel.Close(); err != nil {
 		return err
 	}
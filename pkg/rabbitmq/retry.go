@@ -0,0 +1,228 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const (
+	retryCountHeader = "x-retry-count"
+	errorHeader      = "x-error"
+)
+
+// RetryPolicy bounds the retries of a failed message. The delay before
+// retry n is Backoff doubled n-1 times, up to MaxBackoff.
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxBackoff)
+}
+
+// DeadLetter is a message which failed after all retries or was rejected.
+type DeadLetter struct {
+	Body    []byte
+	Retries int
+	// Error is the reason of the last failure.
+	Error    string
+	FailedAt time.Time
+}
+
+func deadLetterQueue(queueName string) string {
+	return queueName + ".dead"
+}
+
+func retryQueue(queueName string, retry int) string {
+	return fmt.Sprintf("%s.retry.%d", queueName, retry)
+}
+
+// DeclareQueueWithRetry declares queueName, the "<queue>.dead" queue of
+// messages which failed after all retries, and a "<queue>.retry.<n>" queue
+// for each retry, which holds messages until their delay expires and
+// dead-letters them back to queueName.
+//
+// queueName is declared without arguments, as by DeclareQueue, and the
+// delays are set on the messages, so the declarations do not depend on
+// policy and existing queues stay equivalent when it changes.
+func (r *RabbitMQ) DeclareQueueWithRetry(queueName string, policy RetryPolicy) error {
+	return r.declare(func(ch *amqp.Channel) error {
+		if _, err := ch.QueueDeclare(deadLetterQueue(queueName), true, false, false, false, nil); err != nil {
+			return err
+		}
+
+		if _, err := ch.QueueDeclare(queueName, true, false, false, false, nil); err != nil {
+			return err
+		}
+
+		for retry := 1; retry <= policy.MaxRetries; retry++ {
+			_, err := ch.QueueDeclare(
+				retryQueue(queueName, retry),
+				true,
+				false,
+				false,
+				false,
+				amqp.Table{
+					"x-dead-letter-exchange":    "",
+					"x-dead-letter-routing-key": queueName,
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Retry acknowledges msg of queueName and publishes it to the next retry
+// queue, or to the dead-letter queue when the retries of policy are
+// exhausted. cause is kept in the headers of the message.
+func (r *RabbitMQ) Retry(queueName string, policy RetryPolicy, msg amqp.Delivery, cause error) error {
+	retry := retries(msg) + 1
+
+	target := retryQueue(queueName, retry)
+	expiration := strconv.FormatInt(policy.delay(retry).Milliseconds(), 10)
+	if retry > policy.MaxRetries {
+		target = deadLetterQueue(queueName)
+		expiration = ""
+	}
+
+	if err := r.republish(target, msg, retry, cause, expiration); err != nil {
+		return err
+	}
+
+	return msg.Ack(false)
+}
+
+// Reject acknowledges msg of queueName and publishes it to the dead-letter
+// queue without retries.
+func (r *RabbitMQ) Reject(queueName string, msg amqp.Delivery, cause error) error {
+	if err := r.republish(deadLetterQueue(queueName), msg, retries(msg), cause, ""); err != nil {
+		return err
+	}
+
+	return msg.Ack(false)
+}
+
+// republish publishes msg to target, expiration is the TTL of the message in
+// milliseconds, empty for none.
+func (r *RabbitMQ) republish(target string, msg amqp.Delivery, retry int, cause error, expiration string) error {
+	headers := amqp.Table{}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[retryCountHeader] = int32(retry)
+	if cause != nil {
+		headers[errorHeader] = cause.Error()
+	}
+
+	return r.publish(target, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		Body:         msg.Body,
+		DeliveryMode: 2,
+		Timestamp:    time.Now(),
+		Expiration:   expiration,
+	})
+}
+
+func retries(msg amqp.Delivery) int {
+	switch retry := msg.Headers[retryCountHeader].(type) {
+	case int32:
+		return int(retry)
+	case int64:
+		return int(retry)
+	default:
+		return 0
+	}
+}
+
+// DeadLetters returns up to limit messages of the dead-letter queue of
+// queueName, oldest first, and leaves them in the queue.
+func (r *RabbitMQ) DeadLetters(queueName string, limit int) ([]DeadLetter, error) {
+	deliveries, err := r.getDeadLetters(queueName, limit)
+	// The messages are held unacknowledged until all are read, so none is
+	// read twice
+	for _, msg := range deliveries {
+		msg.Nack(false, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]DeadLetter, 0, len(deliveries))
+	for _, msg := range deliveries {
+		deadLetter := DeadLetter{
+			Body:     msg.Body,
+			Retries:  retries(msg),
+			FailedAt: msg.Timestamp,
+		}
+		if cause, ok := msg.Headers[errorHeader].(string); ok {
+			deadLetter.Error = cause
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, nil
+}
+
+// ReplayDeadLetters moves up to limit messages of the dead-letter queue of
+// queueName back to queueName with their retries reset, and returns how many
+// were moved.
+func (r *RabbitMQ) ReplayDeadLetters(queueName string, limit int) (int, error) {
+	deliveries, err := r.getDeadLetters(queueName, limit)
+	if err != nil {
+		for _, msg := range deliveries {
+			msg.Nack(false, true)
+		}
+		return 0, err
+	}
+
+	replayed := 0
+	for i, msg := range deliveries {
+		if err := r.Publish(queueName, msg.Body, msg.ContentType); err != nil {
+			for _, msg := range deliveries[i:] {
+				msg.Nack(false, true)
+			}
+			return replayed, err
+		}
+		if err := msg.Ack(false); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+func (r *RabbitMQ) getDeadLetters(queueName string, limit int) ([]amqp.Delivery, error) {
+	ch, err := r.currentChannel()
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []amqp.Delivery{}
+	for len(deliveries) < limit {
+		msg, ok, err := ch.Get(deadLetterQueue(queueName), false)
+		if err != nil {
+			return deliveries, err
+		}
+		if !ok {
+			break
+		}
+		deliveries = append(deliveries, msg)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,255 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// abortTimeout bounds the abort of a failed multipart upload, which runs
+// even when the upload context is already canceled.
+const abortTimeout = 30 * time.Second
+
+// UploadMultipart uploads reader of any length by parts of the configured
+// size, sending up to the configured concurrency of parts in parallel. Memory
+// usage is bounded by partSize * concurrency. A stream shorter than a part is
+// uploaded by a single request. The multipart upload is aborted on any error,
+// including cancellation of ctx, so no incomplete parts are left in the bucket.
+func (s *S3) UploadMultipart(ctx context.Context, filename string, reader io.Reader, opts minio.PutObjectOptions) (err error) {
+	buffers := make(chan []byte, s.concurrency)
+	for range s.concurrency {
+		buffers <- make([]byte, s.partSize)
+	}
+
+	first := <-buffers
+	n, err := io.ReadFull(reader, first)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		_, err = s.client.PutObject(ctx, s.bucket, s.object(filename), bytes.NewReader(first[:n]), int64(n), opts)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	object := s.object(filename)
+	uploadID, err := s.core.NewMultipartUpload(ctx, s.bucket, object, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abortTimeout)
+		defer cancel()
+		if abortErr := s.core.AbortMultipartUpload(abortCtx, s.bucket, object, uploadID); abortErr != nil {
+			err = errors.Join(err, fmt.Errorf("abort multipart upload: %w", abortErr))
+		}
+	}()
+
+	uploadCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		parts []minio.CompletePart
+	)
+
+	upload := func(partNumber int, buffer []byte, size int) {
+		defer wg.Done()
+		defer func() { buffers <- buffer }()
+
+		part, err := s.core.PutObjectPart(uploadCtx, s.bucket, object, uploadID, partNumber, bytes.NewReader(buffer[:size]), int64(size), minio.PutObjectPartOptions{})
+		if err != nil {
+			cancel(err)
+			return
+		}
+
+		mu.Lock()
+		parts = append(parts, minio.CompletePart{PartNumber: partNumber, ETag: part.ETag})
+		mu.Unlock()
+	}
+
+	wg.Add(1)
+	go upload(1, first, n)
+
+	for partNumber := 2; ; partNumber++ {
+		var buffer []byte
+		select {
+		case buffer = <-buffers:
+		case <-uploadCtx.Done():
+			wg.Wait()
+			return context.Cause(uploadCtx)
+		}
+
+		n, readErr := io.ReadFull(reader, buffer)
+		if n > 0 {
+			wg.Add(1)
+			go upload(partNumber, buffer, n)
+		} else {
+			buffers <- buffer
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			cancel(readErr)
+			wg.Wait()
+			return readErr
+		}
+	}
+
+	wg.Wait()
+	if err := context.Cause(uploadCtx); err != nil {
+		return err
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	_, err = s.core.CompleteMultipartUpload(ctx, s.bucket, object, uploadID, parts, opts)
+	return err
+}
+
+// DownloadParallel reads the object of the given size by ranged requests of
+// the configured part size, prefetching up to the configured concurrency of
+// parts in parallel. Parts are returned in order, memory usage is bounded by
+// partSize * (concurrency + 1).
+func (s *S3) DownloadParallel(ctx context.Context, filename string, size int64) io.ReadSeekCloser {
+	r := &parallelReader{
+		s3:       s,
+		ctx:      ctx,
+		filename: filename,
+		size:     size,
+	}
+	r.start(0)
+	return r
+}
+
+type partResult struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+type parallelReader struct {
+	s3       *S3
+	ctx      context.Context
+	filename string
+	size     int64
+
+	offset  int64
+	parts   chan *partResult
+	cancel  context.CancelFunc
+	current []byte
+	closed  bool
+}
+
+func (r *parallelReader) start(offset int64) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	parts := make(chan *partResult, r.s3.concurrency-1)
+
+	r.offset = offset
+	r.parts = parts
+	r.cancel = cancel
+	r.current = nil
+
+	go func() {
+		defer close(parts)
+
+		for start := offset; start < r.size; start += r.s3.partSize {
+			length := min(r.s3.partSize, r.size-start)
+			part := &partResult{done: make(chan struct{})}
+
+			select {
+			case parts <- part:
+			case <-ctx.Done():
+				return
+			}
+
+			go r.fetch(ctx, part, start, length)
+		}
+	}()
+}
+
+func (r *parallelReader) fetch(ctx context.Context, part *partResult, start int64, length int64) {
+	defer close(part.done)
+
+	object, err := r.s3.DownloadRange(ctx, r.filename, start, length)
+	if err != nil {
+		part.err = err
+		return
+	}
+	defer object.Close()
+
+	part.data = make([]byte, length)
+	if _, err := io.ReadFull(object, part.data); err != nil {
+		part.err = err
+	}
+}
+
+func (r *parallelReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errors.New("s3: read of closed object")
+	}
+
+	for len(r.current) == 0 {
+		part, ok := <-r.parts
+		if !ok {
+			if err := r.ctx.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+
+		<-part.done
+		if part.err != nil {
+			return 0, part.err
+		}
+		r.current = part.data
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	r.offset += int64(n)
+	return n, nil
+}
+
+// Seek restarts prefetching from the new offset.
+func (r *parallelReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("s3: negative position")
+	}
+	if offset == r.offset {
+		return offset, nil
+	}
+
+	r.cancel()
+	r.start(offset)
+	return offset, nil
+}
+
+func (r *parallelReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.cancel()
+	return nil
+}
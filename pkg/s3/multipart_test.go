@@ -0,0 +1,296 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const testBucket = "bucket"
+
+// fakeS3 keeps objects in memory and answers the requests of single and
+// multipart uploads and of ranged downloads.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[int][]byte
+	// completed holds the part numbers of the last completed upload in the
+	// order of the request.
+	completed []int
+	// partDelay delays the response to an uploaded part.
+	partDelay func(partNumber int) time.Duration
+	ranges    int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: make(map[string][]byte),
+		parts:   make(map[int][]byte),
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/"+testBucket+"/")
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>upload</UploadId></InitiateMultipartUploadResult>", testBucket, key)
+
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		body, _ := io.ReadAll(r.Body)
+		if f.partDelay != nil {
+			time.Sleep(f.partDelay(partNumber))
+		}
+		f.mu.Lock()
+		f.parts[partNumber] = body
+		f.mu.Unlock()
+		w.Header().Set("ETag", fmt.Sprintf("\"part-%d\"", partNumber))
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&complete); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		f.completed = nil
+		var object []byte
+		for _, part := range complete.Parts {
+			f.completed = append(f.completed, part.PartNumber)
+			object = append(object, f.parts[part.PartNumber]...)
+		}
+		f.objects[key] = object
+		f.mu.Unlock()
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>\"object\"</ETag></CompleteMultipartUploadResult>", testBucket, key)
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		f.objects[key] = body
+		f.mu.Unlock()
+		w.Header().Set("ETag", "\"object\"")
+
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		f.mu.Lock()
+		object, ok := f.objects[key]
+		if len(r.Header.Get("Range")) > 0 {
+			f.ranges++
+		}
+		f.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", "\"object\"")
+		http.ServeContent(w, r, "", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), bytes.NewReader(object))
+
+	default:
+		http.Error(w, "unexpected request", http.StatusNotImplemented)
+	}
+}
+
+// newTestS3 connects to fake by parts smaller than S3 allows, so tests use
+// small objects.
+func newTestS3(t *testing.T, fake *fakeS3, partSize int64, concurrency int) *S3 {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Region: "us-east-1",
+		Creds:  credentials.NewStaticV4("", "", ""),
+	})
+	if err != nil {
+		t.Fatalf("minio.New: %v", err)
+	}
+
+	return &S3{
+		client:      client,
+		core:        &minio.Core{Client: client},
+		bucket:      testBucket,
+		partSize:    partSize,
+		concurrency: concurrency,
+	}
+}
+
+func testData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestUploadMultipartPartOrder(t *testing.T) {
+	const (
+		partSize = 1024
+		parts    = 8
+	)
+	fake := newFakeS3()
+	// Earlier parts finish later, so they are collected out of order
+	fake.partDelay = func(partNumber int) time.Duration {
+		return time.Duration(parts-partNumber) * 10 * time.Millisecond
+	}
+	s := newTestS3(t, fake, partSize, 4)
+
+	data := testData(partSize*(parts-1) + partSize/2)
+	if err := s.UploadMultipart(context.Background(), "file", bytes.NewReader(data), minio.PutObjectOptions{}); err != nil {
+		t.Fatalf("UploadMultipart: %v", err)
+	}
+
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	if !slices.Equal(fake.completed, want) {
+		t.Errorf("completed parts %v, want %v", fake.completed, want)
+	}
+	if !bytes.Equal(fake.objects["file"], data) {
+		t.Error("uploaded object differs from the data")
+	}
+}
+
+func TestUploadMultipartSinglePart(t *testing.T) {
+	fake := newFakeS3()
+	s := newTestS3(t, fake, 1024, 4)
+
+	data := testData(100)
+	if err := s.UploadMultipart(context.Background(), "file", bytes.NewReader(data), minio.PutObjectOptions{}); err != nil {
+		t.Fatalf("UploadMultipart: %v", err)
+	}
+
+	if fake.completed != nil || len(fake.parts) != 0 {
+		t.Errorf("short stream uploaded by parts %v", fake.completed)
+	}
+	if !bytes.Equal(fake.objects["file"], data) {
+		t.Error("uploaded object differs from the data")
+	}
+}
+
+func TestParallelReaderSeek(t *testing.T) {
+	const partSize = 1024
+	fake := newFakeS3()
+	data := testData(partSize*10 + 100)
+	fake.objects["file"] = data
+	s := newTestS3(t, fake, partSize, 3)
+
+	reader := s.DownloadParallel(context.Background(), "file", int64(len(data)))
+	defer reader.Close()
+
+	buffer := make([]byte, 1500)
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(buffer, data[:1500]) {
+		t.Error("read differs from the data")
+	}
+
+	// Each case reads 600 bytes after seeking, relative offsets count from
+	// the end of the previous read
+	tests := []struct {
+		name   string
+		offset int64
+		whence int
+		want   int64
+	}{
+		{name: "start inside part", offset: 2500, whence: io.SeekStart, want: 2500},
+		{name: "current backwards", offset: -2000, whence: io.SeekCurrent, want: 1100},
+		{name: "current forwards across parts", offset: 5000, whence: io.SeekCurrent, want: 6700},
+		{name: "end", offset: -50, whence: io.SeekEnd, want: int64(len(data)) - 50},
+		{name: "part boundary", offset: 3 * partSize, whence: io.SeekStart, want: 3 * partSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position, err := reader.Seek(tt.offset, tt.whence)
+			if err != nil {
+				t.Fatalf("Seek: %v", err)
+			}
+			if position != tt.want {
+				t.Fatalf("position %d, want %d", position, tt.want)
+			}
+
+			// Reads continue at the new position
+			size := min(600, int64(len(data))-position)
+			buffer := make([]byte, size)
+			if _, err := io.ReadFull(reader, buffer); err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !bytes.Equal(buffer, data[position:position+size]) {
+				t.Error("read after seek differs from the data")
+			}
+		})
+	}
+
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	all, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+	if !bytes.Equal(all, data) {
+		t.Error("read after seek to the start differs from the data")
+	}
+
+	if _, err := reader.Seek(-1, io.SeekStart); err == nil {
+		t.Error("Seek to a negative position succeeded")
+	}
+}
+
+func TestParallelReaderSeekToPosition(t *testing.T) {
+	const partSize = 1024
+	fake := newFakeS3()
+	data := testData(partSize * 4)
+	fake.objects["file"] = data
+	s := newTestS3(t, fake, partSize, 2)
+
+	reader := s.DownloadParallel(context.Background(), "file", int64(len(data)))
+	defer reader.Close()
+
+	buffer := make([]byte, 100)
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	// Seeking to the current position keeps the prefetched parts
+	position, err := reader.Seek(0, io.SeekCurrent)
+	if err != nil || position != 100 {
+		t.Fatalf("Seek: %d, %v", position, err)
+	}
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+	if !bytes.Equal(rest, data[100:]) {
+		t.Error("read after seek to the current position differs from the data")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.ranges != 4 {
+		t.Errorf("got %d ranged requests, want 4", fake.ranges)
+	}
+}
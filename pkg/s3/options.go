@@ -0,0 +1,23 @@
+package s3
+
+type options struct {
+	partSize    int64
+	concurrency int
+}
+
+type Option func(*options)
+
+// WithPartSize sets the size of parts of multipart uploads and ranged
+// downloads. S3 requires between 5 MiB and 5 GiB, New rejects other sizes.
+func WithPartSize(partSize int64) Option {
+	return func(o *options) {
+		o.partSize = partSize
+	}
+}
+
+// WithConcurrency sets how many parts are transferred in parallel.
+func WithConcurrency(concurrency int) Option {
+	return func(o *options) {
+		o.concurrency = concurrency
+	}
+}
@@ -0,0 +1,20 @@
+package s3
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// PresignedGet returns a URL which allows to download the object without
+// credentials until it expires. reqParams override response headers, e.g.
+// response-content-type or response-content-disposition.
+func (s *S3) PresignedGet(ctx context.Context, filename string, expires time.Duration, reqParams url.Values) (*url.URL, error) {
+	return s.client.PresignedGetObject(ctx, s.bucket, s.object(filename), expires, reqParams)
+}
+
+// PresignedPut returns a URL which allows to upload the object by a single
+// PUT request without credentials until it expires.
+func (s *S3) PresignedPut(ctx context.Context, filename string, expires time.Duration) (*url.URL, error) {
+	return s.client.PresignedPutObject(ctx, s.bucket, s.object(filename), expires)
+}
@@ -4,18 +4,44 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
+const (
+	defaultPartSize    = 16 * 1024 * 1024
+	defaultConcurrency = 4
+	// minPartSize and maxPartSize are the limits of S3 for all parts but the
+	// last one of a multipart upload.
+	minPartSize = 5 * 1024 * 1024
+	maxPartSize = 5 * 1024 * 1024 * 1024
+	// maxCopySize is the largest object S3 copies in a single request.
+	maxCopySize = 5 * 1024 * 1024 * 1024
+)
+
 type S3 struct {
-	client    *minio.Client
-	bucket    string
-	directory string
+	client      *minio.Client
+	core        *minio.Core
+	bucket      string
+	directory   string
+	partSize    int64
+	concurrency int
 }
 
-func New(endpoint string, region string, accessKey string, secretKey string, useSSL bool, bucket string, directory string) (*S3, error) {
+func New(endpoint string, region string, accessKey string, secretKey string, useSSL bool, bucket string, directory string, opts ...Option) (*S3, error) {
+	o := &options{
+		partSize:    defaultPartSize,
+		concurrency: defaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.partSize < minPartSize || o.partSize > maxPartSize {
+		return nil, fmt.Errorf("part size must be between %d and %d bytes, got %d", minPartSize, maxPartSize, o.partSize)
+	}
+
 	client, err := minio.New(endpoint, &minio.Options{
 		Region: region,
 		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
@@ -37,9 +63,12 @@ func New(endpoint string, region string, accessKey string, secretKey string, use
 	}
 
 	return &S3{
-		client:    client,
-		bucket:    bucket,
-		directory: directory,
+		client:      client,
+		core:        &minio.Core{Client: client},
+		bucket:      bucket,
+		directory:   directory,
+		partSize:    o.partSize,
+		concurrency: max(o.concurrency, 1),
 	}, nil
 }
 
@@ -71,15 +100,75 @@ func (s *S3) Objects(ctx context.Context) ([]string, error) {
 	return result, nil
 }
 
+// ObjectsIn lists objects placed directly in directory, names are relative to
+// it. An empty directory lists the root directory.
+func (s *S3) ObjectsIn(ctx context.Context, directory string) ([]string, error) {
+	var result []string
+
+	prefix := strings.TrimSuffix(s.object(strings.TrimSuffix(directory, "/")), "/")
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+	objectCh := s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: false,
+	})
+
+	for object := range objectCh {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+
+		name := strings.TrimPrefix(object.Key, prefix)
+		if len(name) == 0 || strings.HasSuffix(name, "/") {
+			continue
+		}
+
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+// Upload streams reader to the object. Pass size -1 when the length is unknown.
+// Objects bigger than a part are sent by a multipart upload.
 func (s *S3) Upload(ctx context.Context, filename string, reader io.Reader, size int64, contentType string) error {
-	_, err := s.client.PutObject(ctx, s.bucket, s.object(filename), reader, size, minio.PutObjectOptions{ContentType: contentType})
-	return err
+	opts := minio.PutObjectOptions{
+		ContentType: contentType,
+	}
+
+	if size >= 0 && size <= s.partSize {
+		_, err := s.client.PutObject(ctx, s.bucket, s.object(filename), reader, size, opts)
+		return err
+	}
+
+	return s.UploadMultipart(ctx, filename, reader, opts)
 }
 
-func (s *S3) Download(ctx context.Context, filename string) (*minio.Object, error) {
+// Download opens the object for reading. Objects bigger than a part are
+// downloaded by parallel ranged requests when concurrency allows it.
+func (s *S3) Download(ctx context.Context, filename string) (io.ReadSeekCloser, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, s.object(filename), minio.StatObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.concurrency > 1 && info.Size > s.partSize {
+		return s.DownloadParallel(ctx, filename, info.Size), nil
+	}
+
 	return s.client.GetObject(ctx, s.bucket, s.object(filename), minio.GetObjectOptions{})
 }
 
+// DownloadRange requests only length bytes of the object starting at offset.
+func (s *S3) DownloadRange(ctx context.Context, filename string, offset int64, length int64) (*minio.Object, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+	return s.client.GetObject(ctx, s.bucket, s.object(filename), opts)
+}
+
 func (s *S3) Delete(ctx context.Context, filename string) error {
 	return s.client.RemoveObject(ctx, s.bucket, s.object(filename), minio.RemoveObjectOptions{})
 }
@@ -89,7 +178,21 @@ func (s *S3) Exists(ctx context.Context, filename string) bool {
 	return err == nil
 }
 
+// Size returns the size of the object in bytes.
+func (s *S3) Size(ctx context.Context, filename string) (int64, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, s.object(filename), minio.StatObjectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return info.Size, nil
+}
+
 func (s *S3) Rename(ctx context.Context, oldFilename string, newFilename string) error {
+	info, err := s.client.StatObject(ctx, s.bucket, s.object(oldFilename), minio.StatObjectOptions{})
+	if err != nil {
+		return err
+	}
+
 	src := minio.CopySrcOptions{
 		Bucket: s.bucket,
 		Object: s.object(oldFilename),
@@ -98,7 +201,12 @@ func (s *S3) Rename(ctx context.Context, oldFilename string, newFilename string)
 		Bucket: s.bucket,
 		Object: s.object(newFilename),
 	}
-	_, err := s.client.CopyObject(ctx, dest, src)
+	if info.Size > maxCopySize {
+		// Compose copies big objects part by part
+		_, err = s.client.ComposeObject(ctx, dest, src)
+	} else {
+		_, err = s.client.CopyObject(ctx, dest, src)
+	}
 	if err != nil {
 		return err
 	}
@@ -109,6 +109,7 @@ func NewWithConfig(logger *slog.Logger, config Config) grpc.UnaryServerIntercept
 
 		// Inject logger with default attributes into context
 		ctx = logging.ContextWithLogger(ctx, logging.WithDefaultAttrs(logger, attrs.Group()))
+		ctx = context.WithValue(ctx, customAttributesCtxKey{}, &attrs.attrs)
 
 		// Call handler
 		resp, err := handler(ctx, req)
@@ -162,6 +163,15 @@ func NewWithConfig(logger *slog.Logger, config Config) grpc.UnaryServerIntercept
 	}
 }
 
+type customAttributesCtxKey struct{}
+
+// AddCustomAttributes adds custom attributes to the log of the request of ctx.
+func AddCustomAttributes(ctx context.Context, attr slog.Attr) {
+	if attrs, ok := ctx.Value(customAttributesCtxKey{}).(*[]slog.Attr); ok {
+		*attrs = append(*attrs, attr)
+	}
+}
+
 func extractMetadata(md metadata.MD, key string) string {
 	values := md.Get(key)
 	if len(values) > 0 {